@@ -0,0 +1,178 @@
+package consensus
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"kasplex-executor/api/models"
+	"kasplex-executor/storage"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Compare below the tip of both sides, the recent batches may be rolled back.
+const daaScoreMargin = 1000
+
+const (
+	StateConsistent  = "consistent"
+	StateDiverged    = "diverged"
+	StateUnreachable = "unreachable"
+	StatePending     = "pending"
+)
+
+type peerResponse struct {
+	Success bool                   `json:"success"`
+	Error   string                 `json:"error"`
+	Result  *models.CheckpointInfo `json:"result"`
+}
+
+var (
+	mutex  sync.RWMutex
+	status = models.ConsensusStatus{Peers: []models.PeerStatus{}}
+)
+
+// Run polls the checkpoint endpoint of the peer indexers and compares with the local checkpoint
+func Run(ctx context.Context, peers []string, interval time.Duration) {
+	if len(peers) == 0 {
+		return
+	}
+	if interval < 10*time.Second {
+		interval = 10 * time.Second
+	}
+	client := &http.Client{Timeout: 10 * time.Second}
+	slog.Info("consensus comparator started", "peers", len(peers), "interval", interval)
+	for {
+		peerList := make([]models.PeerStatus, 0, len(peers))
+		alert := false
+		for _, peer := range peers {
+			peerStatus := check(client, strings.TrimRight(peer, "/"))
+			if peerStatus.State == StateDiverged {
+				alert = true
+				slog.Error("consensus checkpoint diverged", "peer", peer, "daaScore", peerStatus.DaaScore, "checkpoint", peerStatus.Checkpoint, "checkpointPeer", peerStatus.CheckpointPeer)
+			} else if peerStatus.State == StateUnreachable {
+				slog.Warn("consensus peer unreachable", "peer", peer, "error", peerStatus.Error)
+			}
+			peerList = append(peerList, peerStatus)
+		}
+		mutex.Lock()
+		if alert && !status.Alert {
+			status.MtsAlert = time.Now().UnixMilli()
+		} else if !alert {
+			status.MtsAlert = 0
+		}
+		status.Alert = alert
+		status.Peers = peerList
+		mutex.Unlock()
+
+		select {
+		case <-ctx.Done():
+			slog.Info("consensus comparator stopped")
+			return
+		case <-time.After(interval):
+		}
+	}
+}
+
+// GetStatus returns the last comparison result of all peers
+func GetStatus() models.ConsensusStatus {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	result := status
+	result.Peers = append([]models.PeerStatus{}, status.Peers...)
+	return result
+}
+
+// check compares the checkpoint at the same daaScore, below the tip of both sides
+func check(client *http.Client, peer string) models.PeerStatus {
+	peerStatus := models.PeerStatus{
+		Peer:     peer,
+		State:    StatePending,
+		MtsCheck: time.Now().UnixMilli(),
+	}
+	local, err := storage.GetCheckpointLast()
+	if err != nil {
+		peerStatus.Error = "local: " + err.Error()
+		return peerStatus
+	}
+	remote, err := fetch(client, peer+"/api/v1/checkpoint")
+	if err != nil {
+		peerStatus.State = StateUnreachable
+		peerStatus.Error = err.Error()
+		return peerStatus
+	}
+	if local == nil || remote == nil {
+		return peerStatus
+	}
+	daaScore := local.DaaScore
+	if remote.DaaScore < daaScore {
+		daaScore = remote.DaaScore
+	}
+	if daaScore <= daaScoreMargin {
+		return peerStatus
+	}
+	daaScore -= daaScoreMargin
+	peerStatus.DaaScore = daaScore
+
+	local, err = storage.GetCheckpointByDaaScore(daaScore)
+	if err != nil {
+		peerStatus.Error = "local: " + err.Error()
+		return peerStatus
+	}
+	remote, err = fetch(client, peer+"/api/v1/checkpoint?daaScore="+strconv.FormatUint(daaScore, 10))
+	if err != nil {
+		peerStatus.State = StateUnreachable
+		peerStatus.Error = err.Error()
+		return peerStatus
+	}
+	compare(&peerStatus, local, remote)
+	return peerStatus
+}
+
+// compare sets the state by the checkpoints at the same daaScore, the one missing on either side is pending, never consistent
+func compare(peerStatus *models.PeerStatus, local *models.CheckpointInfo, remote *models.CheckpointInfo) {
+	if local != nil {
+		peerStatus.Checkpoint = local.Checkpoint
+	}
+	if remote != nil {
+		peerStatus.CheckpointPeer = remote.Checkpoint
+	}
+	if local == nil || remote == nil {
+		peerStatus.State = StatePending
+		if local == nil {
+			peerStatus.Error = "local: checkpoint not found"
+		} else {
+			peerStatus.Error = "peer: checkpoint not found"
+		}
+		return
+	}
+	if local.Checkpoint != remote.Checkpoint || local.OpScore != remote.OpScore {
+		peerStatus.State = StateDiverged
+		return
+	}
+	peerStatus.State = StateConsistent
+}
+
+// fetch gets the checkpoint from the peer, nil if not found
+func fetch(client *http.Client, url string) (*models.CheckpointInfo, error) {
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	var decoded peerResponse
+	if err := json.NewDecoder(resp.Body).Decode(&decoded); err != nil {
+		return nil, fmt.Errorf("invalid response: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK || !decoded.Success {
+		return nil, errors.New("peer error: " + decoded.Error)
+	}
+	return decoded.Result, nil
+}
@@ -0,0 +1,35 @@
+package consensus
+
+import (
+	"kasplex-executor/api/models"
+	"testing"
+)
+
+func TestCompare(t *testing.T) {
+	cpA := &models.CheckpointInfo{OpScore: 10, Checkpoint: "a"}
+	cpAOther := &models.CheckpointInfo{OpScore: 11, Checkpoint: "a"}
+	cpB := &models.CheckpointInfo{OpScore: 10, Checkpoint: "b"}
+	tests := []struct {
+		name   string
+		local  *models.CheckpointInfo
+		remote *models.CheckpointInfo
+		want   string
+	}{
+		{"both missing", nil, nil, StatePending},
+		{"local missing", nil, cpA, StatePending},
+		{"peer missing", cpA, nil, StatePending},
+		{"same", cpA, &models.CheckpointInfo{OpScore: 10, Checkpoint: "a"}, StateConsistent},
+		{"checkpoint differs", cpA, cpB, StateDiverged},
+		{"opscore differs", cpA, cpAOther, StateDiverged},
+	}
+	for _, tt := range tests {
+		peerStatus := models.PeerStatus{State: StatePending}
+		compare(&peerStatus, tt.local, tt.remote)
+		if peerStatus.State != tt.want {
+			t.Errorf("%s: state = %s, want %s", tt.name, peerStatus.State, tt.want)
+		}
+		if tt.want == StatePending && peerStatus.Error == "" {
+			t.Errorf("%s: no error reported for the checkpoint missing", tt.name)
+		}
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"kasplex-executor/storage"
 	"net/http"
 	"strconv"
+	"strings"
 )
 
 func GetAddressBalances(w http.ResponseWriter, r *http.Request) {
@@ -18,13 +19,32 @@ func GetAddressBalances(w http.ResponseWriter, r *http.Request) {
 		sendResponse(w, http.StatusBadRequest, false, nil, "Address parameter is required")
 		return
 	}
+	decimal, errFormat := parseFormatDecimal(r)
+	if errFormat != nil {
+		sendResponse(w, http.StatusBadRequest, false, nil, "Invalid format parameter")
+		return
+	}
 
-	// Query storage for address balances
-	balances, err := storage.GetAddressBalances(address)
+	// Query storage for address balances, as of the daaScore/opScore if specified
+	var balances []*models.AddressBalance
+	var err error
+	if at := r.URL.Query().Get("at"); at != "" {
+		opScore, errAt := storage.ParseOpScoreAt(at)
+		if errAt != nil {
+			sendResponse(w, http.StatusBadRequest, false, nil, "Invalid at parameter")
+			return
+		}
+		balances, err = storage.GetAddressBalancesAt(address, opScore)
+	} else {
+		balances, err = storage.GetAddressBalances(address)
+	}
 	if err != nil {
 		sendResponse(w, http.StatusInternalServerError, false, nil, "Failed to fetch address balances")
 		return
 	}
+	if decimal {
+		formatAddressBalances(balances)
+	}
 
 	sendResponse(w, http.StatusOK, true, balances, "")
 }
@@ -43,6 +63,11 @@ func GetTopHolders(w http.ResponseWriter, r *http.Request) {
 	if pageSize < 1 || pageSize > 2000 {
 		pageSize = 2000
 	}
+	decimal, err := parseFormatDecimal(r)
+	if err != nil {
+		sendResponse(w, http.StatusBadRequest, false, nil, "Invalid format parameter")
+		return
+	}
 
 	holders, total, err := storage.GetTopHoldersByTokenCount(page, pageSize)
 	if err != nil {
@@ -61,12 +86,16 @@ func GetTopHolders(w http.ResponseWriter, r *http.Request) {
 				Locked:  holding.Locked,
 				Dec:     holding.Dec,
 			}
+			if decimal {
+				modelHoldings[j].BalanceFormatted = storage.FormatAmount(holding.Balance, holding.Dec)
+				modelHoldings[j].LockedFormatted = storage.FormatAmount(holding.Locked, holding.Dec)
+			}
 		}
 
 		modelHolders[i] = models.HolderPortfolio{
 			Address:    holder.Address,
 			TokenCount: holder.TokenCount,
-			TotalValue: holder.TotalValue,
+			TotalValue: holder.TotalValue.String(),
 			Holdings:   modelHoldings,
 		}
 	}
@@ -89,12 +118,102 @@ func GetAllAddressesBalances(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	decimal, err := parseFormatDecimal(r)
+	if err != nil {
+		sendResponse(w, http.StatusBadRequest, false, nil, "Invalid format parameter")
+		return
+	}
+
 	// Get all addresses and balances
 	addresses, err := storage.GetAllAddressesBalances()
 	if err != nil {
 		sendResponse(w, http.StatusInternalServerError, false, nil, "Failed to fetch addresses: "+err.Error())
 		return
 	}
+	if decimal {
+		for _, portfolio := range addresses {
+			formatAddressBalances(portfolio.Balances)
+		}
+	}
 
 	sendResponse(w, http.StatusOK, true, addresses, "")
 }
+
+// GetAddressOperations returns the operations affecting the address with cursor pagination
+func GetAddressOperations(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		sendResponse(w, http.StatusMethodNotAllowed, false, nil, "Method not allowed")
+		return
+	}
+
+	address := r.URL.Query().Get("address")
+	if address == "" {
+		sendResponse(w, http.StatusBadRequest, false, nil, "Address parameter is required")
+		return
+	}
+
+	// Optional filters
+	op := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("op")))
+	tick := ""
+	if tickParam := r.URL.Query().Get("tick"); tickParam != "" {
+		tick = sanitizeString(tickParam)
+		if !validateTick(tick) {
+			sendResponse(w, http.StatusBadRequest, false, nil, "Invalid tick parameter")
+			return
+		}
+	}
+
+	// Parse pagination parameters
+	pageSize, _ := strconv.Atoi(r.URL.Query().Get("pageSize"))
+	if pageSize < 1 || pageSize > 500 {
+		pageSize = 50
+	}
+	cursor := uint64(0)
+	if cursorStr := r.URL.Query().Get("cursor"); cursorStr != "" {
+		score, err := strconv.ParseUint(cursorStr, 10, 64)
+		if err != nil {
+			sendResponse(w, http.StatusBadRequest, false, nil, "Invalid cursor parameter")
+			return
+		}
+		cursor = score
+	}
+
+	decimal, err := parseFormatDecimal(r)
+	if err != nil {
+		sendResponse(w, http.StatusBadRequest, false, nil, "Invalid format parameter")
+		return
+	}
+
+	operations, cursorNext, hasMore, err := storage.GetAddressOperations(address, cursor, op, tick, pageSize)
+	if err != nil {
+		sendResponse(w, http.StatusInternalServerError, false, nil, "Failed to fetch address operations: "+err.Error())
+		return
+	}
+	if decimal {
+		cache := tokenDecCache{}
+		for i := range operations {
+			if dec, ok := cache.get(strings.ToUpper(operations[i].Tick)); ok {
+				operations[i].AmtFormatted = storage.FormatAmount(operations[i].Amt, dec)
+				operations[i].BalanceFormatted = storage.FormatAmount(operations[i].Balance, dec)
+			}
+		}
+	}
+
+	paginationInfo := &models.PaginationInfo{
+		PageSize: pageSize,
+		HasMore:  hasMore,
+	}
+	if hasMore {
+		paginationInfo.NextCursor = strconv.FormatUint(cursorNext, 10)
+	}
+
+	sendPaginatedResponse(w, http.StatusOK, true, operations, paginationInfo, "")
+}
+
+// formatAddressBalances fills the formatted amounts with the decimals of each balance.
+func formatAddressBalances(balances []*models.AddressBalance) {
+	for _, balance := range balances {
+		balance.BalanceFormatted = storage.FormatAmount(balance.Balance, balance.Dec)
+		balance.LockedFormatted = storage.FormatAmount(balance.Locked, balance.Dec)
+	}
+}
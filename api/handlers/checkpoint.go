@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"kasplex-executor/api/consensus"
+	"kasplex-executor/api/models"
+	"kasplex-executor/storage"
+	"net/http"
+	"strconv"
+)
+
+// GetCheckpoint returns the checkpoint at the opScore or daaScore, or the latest one if neither given
+func GetCheckpoint(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		sendResponse(w, http.StatusMethodNotAllowed, false, nil, "Method not allowed")
+		return
+	}
+
+	var checkpoint *models.CheckpointInfo
+	var err error
+	if opScoreStr := r.URL.Query().Get("opScore"); opScoreStr != "" {
+		opScore, errParse := strconv.ParseUint(opScoreStr, 10, 64)
+		if errParse != nil {
+			sendResponse(w, http.StatusBadRequest, false, nil, "Invalid opScore parameter")
+			return
+		}
+		checkpoint, err = storage.GetCheckpointByOpScore(opScore)
+	} else if daaScoreStr := r.URL.Query().Get("daaScore"); daaScoreStr != "" {
+		daaScore, errParse := strconv.ParseUint(daaScoreStr, 10, 64)
+		if errParse != nil {
+			sendResponse(w, http.StatusBadRequest, false, nil, "Invalid daaScore parameter")
+			return
+		}
+		checkpoint, err = storage.GetCheckpointByDaaScore(daaScore)
+	} else {
+		checkpoint, err = storage.GetCheckpointLast()
+	}
+	if err != nil {
+		sendResponse(w, http.StatusInternalServerError, false, nil, "Failed to fetch checkpoint: "+err.Error())
+		return
+	}
+	if checkpoint == nil {
+		sendResponse(w, http.StatusNotFound, false, nil, "Checkpoint not found")
+		return
+	}
+
+	sendResponse(w, http.StatusOK, true, checkpoint, "")
+}
+
+// GetCheckpointConsensus returns the comparison status with the peer indexers
+func GetCheckpointConsensus(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		sendResponse(w, http.StatusMethodNotAllowed, false, nil, "Method not allowed")
+		return
+	}
+
+	sendResponse(w, http.StatusOK, true, consensus.GetStatus(), "")
+}
@@ -2,7 +2,9 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"kasplex-executor/api/models"
+	"kasplex-executor/storage"
 	"net/http"
 	"strings"
 )
@@ -56,3 +58,50 @@ func validateTick(tick string) bool {
 func sanitizeString(s string) string {
 	return strings.TrimSpace(strings.ToUpper(s))
 }
+
+// parseFormatDecimal reports whether the amounts are also rendered with the token decimals,
+// by the optional query parameter "format=decimal".
+func parseFormatDecimal(r *http.Request) (bool, error) {
+	switch r.URL.Query().Get("format") {
+	case "", "raw":
+		return false, nil
+	case "decimal":
+		return true, nil
+	}
+	return false, errors.New("invalid format parameter")
+}
+
+// tokenDecCache looks up the decimals of each tick once in the request, false if the token not found.
+type tokenDecCache map[string]*int
+
+func (cache tokenDecCache) get(tick string) (int, bool) {
+	if !validateTick(tick) {
+		return 0, false
+	}
+	dec, exists := cache[tick]
+	if !exists {
+		if value, err := storage.GetTokenDec(tick); err == nil {
+			dec = &value
+		}
+		cache[tick] = dec
+	}
+	if dec == nil {
+		return 0, false
+	}
+	return *dec, true
+}
+
+// formatOperation fills the formatted amount of the operation.
+func (cache tokenDecCache) formatOperation(operation *models.Operation) {
+	if dec, ok := cache.get(strings.ToUpper(operation.Tick)); ok {
+		operation.AmtFormatted = storage.FormatAmount(operation.Amt, dec)
+	}
+}
+
+// formatOperations fills the formatted amount of the operations.
+func formatOperations(operations []models.Operation) {
+	cache := tokenDecCache{}
+	for i := range operations {
+		cache.formatOperation(&operations[i])
+	}
+}
@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"fmt"
+	"kasplex-executor/events"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Interval of the keep-alive comment in the event stream.
+const keepAliveInterval = 15 * time.Second
+
+// StreamEvents streams the accepted operations and the rollback retractions as server-sent events.
+// The optional tick, address and op parameters filter the events, each may be a comma separated list.
+func StreamEvents(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		sendResponse(w, http.StatusMethodNotAllowed, false, nil, "Method not allowed")
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		sendResponse(w, http.StatusInternalServerError, false, nil, "Streaming unsupported")
+		return
+	}
+
+	// Parse the filters
+	filter := events.FilterType{}
+	for _, tick := range splitParam(r.URL.Query().Get("tick")) {
+		tick = sanitizeString(tick)
+		if !validateTick(tick) {
+			sendResponse(w, http.StatusBadRequest, false, nil, "Invalid tick parameter")
+			return
+		}
+		filter.TickList = append(filter.TickList, tick)
+	}
+	filter.AddressList = splitParam(r.URL.Query().Get("address"))
+	for _, op := range splitParam(r.URL.Query().Get("op")) {
+		filter.OpList = append(filter.OpList, strings.ToLower(op))
+	}
+
+	sub, err := events.Subscribe(filter)
+	if err != nil {
+		sendResponse(w, http.StatusServiceUnavailable, false, nil, "Failed to subscribe events: "+err.Error())
+		return
+	}
+	defer sub.Unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprint(w, ": connected\n\n")
+	flusher.Flush()
+
+	ticker := time.NewTicker(keepAliveInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-ticker.C:
+			fmt.Fprint(w, ": keep-alive\n\n")
+			flusher.Flush()
+		case event, ok := <-sub.C():
+			if !ok {
+				// Dropped by the hub if the client is too slow, it should reconnect.
+				fmt.Fprint(w, "event: error\ndata: {\"error\":\"event queue overflow\"}\n\n")
+				flusher.Flush()
+				return
+			}
+			fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.OpScore, event.Type, event.Json)
+			flusher.Flush()
+		}
+	}
+}
+
+// splitParam splits the comma separated parameter, the empty items are skipped.
+func splitParam(param string) []string {
+	list := []string{}
+	for _, item := range strings.Split(param, ",") {
+		item = strings.TrimSpace(item)
+		if item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"kasplex-executor/api/models"
+	"kasplex-executor/config"
+	"kasplex-executor/explorer"
+	"kasplex-executor/storage"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Default max lag in daaScore of the readiness, about 1 minute.
+const readyLagMaxDefault = 600
+
+// Max age in seconds of the last batch committed of the readiness, the scanner is stuck if exceeded.
+const readyBatchAgeMax = 60
+
+// Healthz reports the storages and the scan state, unhealthy only if any storage unreachable.
+func Healthz(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		sendResponse(w, http.StatusMethodNotAllowed, false, nil, "Method not allowed")
+		return
+	}
+
+	health := getHealthStatus()
+	if len(health.Reasons) > 0 {
+		health.Status = "unhealthy"
+		sendResponse(w, http.StatusServiceUnavailable, false, health, "Unhealthy")
+		return
+	}
+	health.Status = "ok"
+	sendResponse(w, http.StatusOK, true, health, "")
+}
+
+// Readyz returns the readiness handler, unready if unhealthy, the scan is failing, catching up,
+// no batch committed within readyBatchAgeMax, or the lag exceeds lagMax (readyLagMaxDefault if 0).
+func Readyz(lagMax uint64) http.HandlerFunc {
+	if lagMax == 0 {
+		lagMax = readyLagMaxDefault
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			sendResponse(w, http.StatusMethodNotAllowed, false, nil, "Method not allowed")
+			return
+		}
+
+		health := getHealthStatus()
+		health.LagMax = lagMax
+		if health.ScanError != "" {
+			health.Reasons = append(health.Reasons, "scan failing")
+		}
+		if health.CatchingUp {
+			health.Reasons = append(health.Reasons, "catching up")
+		}
+		if health.MtsBatchLast <= 0 {
+			health.Reasons = append(health.Reasons, "no batch committed")
+		} else if ageBatch := (time.Now().UnixMilli() - health.MtsBatchLast) / 1000; ageBatch > readyBatchAgeMax {
+			health.Reasons = append(health.Reasons, "last batch "+strconv.FormatInt(ageBatch, 10)+"s ago")
+		}
+		if health.Lag > lagMax {
+			health.Reasons = append(health.Reasons, "lag "+strconv.FormatUint(health.Lag, 10)+" exceeds "+strconv.FormatUint(lagMax, 10))
+		}
+		if len(health.Reasons) > 0 {
+			health.Status = "unready"
+			sendResponse(w, http.StatusServiceUnavailable, false, health, "Not ready")
+			return
+		}
+		health.Status = "ready"
+		sendResponse(w, http.StatusOK, true, health, "")
+	}
+}
+
+// getHealthStatus checks the storages and reads the scan status, the reasons list the storages unreachable.
+func getHealthStatus() *models.HealthStatus {
+	status := explorer.GetStatus()
+	health := &models.HealthStatus{
+		Version:      config.Version,
+		Cassandra:    models.ComponentHealth{Ok: true},
+		Rocksdb:      models.ComponentHealth{Ok: true},
+		DaaScore:     status.DaaScore,
+		DaaScoreTip:  status.DaaScoreTip,
+		OpScore:      status.OpScoreLast,
+		Synced:       status.Synced,
+		CatchingUp:   status.CatchingUp,
+		ScanError:    status.ErrScan,
+		MtsBatchLast: status.MtsBatchLast,
+	}
+	if status.DaaScoreTip > status.DaaScore {
+		health.Lag = status.DaaScoreTip - status.DaaScore
+	}
+	if err := storage.PingCassa(); err != nil {
+		health.Cassandra = models.ComponentHealth{Error: err.Error()}
+		health.Reasons = append(health.Reasons, "cassandra unreachable")
+	}
+	if err := storage.PingRocks(); err != nil {
+		health.Rocksdb = models.ComponentHealth{Error: err.Error()}
+		health.Reasons = append(health.Reasons, "rocksdb unreachable")
+	}
+	return health
+}
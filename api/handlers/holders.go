@@ -19,6 +19,11 @@ func GetTokenHolders(w http.ResponseWriter, r *http.Request) {
 		sendResponse(w, http.StatusBadRequest, false, nil, "Invalid tick parameter")
 		return
 	}
+	decimal, errFormat := parseFormatDecimal(r)
+	if errFormat != nil {
+		sendResponse(w, http.StatusBadRequest, false, nil, "Invalid format parameter")
+		return
+	}
 
 	// Parse pagination parameters
 	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
@@ -30,12 +35,35 @@ func GetTokenHolders(w http.ResponseWriter, r *http.Request) {
 		pageSize = 20000
 	}
 
-	// Get holders with pagination
-	holders, total, err := storage.GetTokenHoldersPaginated(tick, page, pageSize)
+	// Get holders with pagination, as of the daaScore/opScore if specified
+	var holders []models.HolderInfo
+	var total int
+	var err error
+	if at := r.URL.Query().Get("at"); at != "" {
+		opScore, errAt := storage.ParseOpScoreAt(at)
+		if errAt != nil {
+			sendResponse(w, http.StatusBadRequest, false, nil, "Invalid at parameter")
+			return
+		}
+		holders, total, err = storage.GetTokenHoldersPaginatedAt(tick, opScore, page, pageSize)
+	} else {
+		holders, total, err = storage.GetTokenHoldersPaginated(tick, page, pageSize)
+	}
 	if err != nil {
 		sendResponse(w, http.StatusInternalServerError, false, nil, "Failed to fetch holders: "+err.Error())
 		return
 	}
+	if decimal {
+		dec, err := storage.GetTokenDec(tick)
+		if err != nil {
+			sendResponse(w, http.StatusInternalServerError, false, nil, "Failed to fetch token info: "+err.Error())
+			return
+		}
+		for i := range holders {
+			holders[i].BalanceFormatted = storage.FormatAmount(holders[i].Balance, dec)
+			holders[i].LockedFormatted = storage.FormatAmount(holders[i].Locked, dec)
+		}
+	}
 
 	// Calculate pagination info
 	totalPages := (total + pageSize - 1) / pageSize
@@ -0,0 +1,234 @@
+package handlers
+
+import (
+	"kasplex-executor/api/models"
+	"kasplex-executor/storage"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// GetMarketOrders returns the open list orders of the tick and/or the address
+func GetMarketOrders(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		sendResponse(w, http.StatusMethodNotAllowed, false, nil, "Method not allowed")
+		return
+	}
+
+	tick := ""
+	if tickParam := r.URL.Query().Get("tick"); tickParam != "" {
+		tick = sanitizeString(tickParam)
+		if !validateTick(tick) {
+			sendResponse(w, http.StatusBadRequest, false, nil, "Invalid tick parameter")
+			return
+		}
+	}
+	address := r.URL.Query().Get("address")
+	if tick == "" && address == "" {
+		sendResponse(w, http.StatusBadRequest, false, nil, "Tick or address parameter is required")
+		return
+	}
+	decimal, err := parseFormatDecimal(r)
+	if err != nil {
+		sendResponse(w, http.StatusBadRequest, false, nil, "Invalid format parameter")
+		return
+	}
+
+	// Parse pagination parameters
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	if page < 1 {
+		page = 1
+	}
+	pageSize, _ := strconv.Atoi(r.URL.Query().Get("pageSize"))
+	if pageSize < 1 || pageSize > 2000 {
+		pageSize = 500
+	}
+
+	orders, err := storage.GetMarketOrders(tick, address)
+	if err != nil {
+		sendResponse(w, http.StatusInternalServerError, false, nil, "Failed to fetch market orders: "+err.Error())
+		return
+	}
+
+	total := len(orders)
+	start := (page - 1) * pageSize
+	if start > total {
+		start = total
+	}
+	end := start + pageSize
+	if end > total {
+		end = total
+	}
+	orders = orders[start:end]
+
+	if decimal {
+		cache := tokenDecCache{}
+		for i := range orders {
+			orders[i].UAmtFormatted = storage.FormatAmount(orders[i].UAmt, storage.DecKas)
+			if dec, ok := cache.get(orders[i].Tick); ok {
+				orders[i].TAmtFormatted = storage.FormatAmount(orders[i].TAmt, dec)
+			}
+		}
+	}
+
+	paginationInfo := &models.PaginationInfo{
+		CurrentPage:  page,
+		PageSize:     pageSize,
+		TotalPages:   (total + pageSize - 1) / pageSize,
+		TotalRecords: total,
+	}
+
+	sendPaginatedResponse(w, http.StatusOK, true, orders, paginationInfo, "")
+}
+
+// Intervals of the trade candles.
+var candleIntervalMap = map[string]int64{
+	"1m":  60000,
+	"5m":  300000,
+	"15m": 900000,
+	"30m": 1800000,
+	"1h":  3600000,
+	"4h":  14400000,
+	"12h": 43200000,
+	"1d":  86400000,
+	"1w":  604800000,
+}
+
+// Max number of the candle intervals in the request.
+const lenCandleMax = 1000
+
+// Window of the trade volume, 24 hours.
+const mtsVolumeWindow = 86400000
+
+// GetMarketTrades returns the recent trades of the tick with cursor pagination, or the cancels if event=cancel
+func GetMarketTrades(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		sendResponse(w, http.StatusMethodNotAllowed, false, nil, "Method not allowed")
+		return
+	}
+
+	tick := sanitizeString(r.URL.Query().Get("tick"))
+	if !validateTick(tick) {
+		sendResponse(w, http.StatusBadRequest, false, nil, "Invalid tick parameter")
+		return
+	}
+	event := r.URL.Query().Get("event")
+	switch event {
+	case "":
+		event = storage.OpEventTrade
+	case storage.OpEventTrade, storage.OpEventCancel:
+	default:
+		sendResponse(w, http.StatusBadRequest, false, nil, "Invalid event parameter: must be trade or cancel")
+		return
+	}
+
+	// Parse pagination parameters
+	pageSize, _ := strconv.Atoi(r.URL.Query().Get("pageSize"))
+	if pageSize < 1 || pageSize > 500 {
+		pageSize = 50
+	}
+	cursor := uint64(0)
+	if cursorStr := r.URL.Query().Get("cursor"); cursorStr != "" {
+		score, err := strconv.ParseUint(cursorStr, 10, 64)
+		if err != nil {
+			sendResponse(w, http.StatusBadRequest, false, nil, "Invalid cursor parameter")
+			return
+		}
+		cursor = score
+	}
+
+	trades, hasMore, err := storage.GetTrades(tick, event, cursor, pageSize)
+	if err != nil {
+		sendResponse(w, http.StatusInternalServerError, false, nil, "Failed to fetch trades: "+err.Error())
+		return
+	}
+
+	paginationInfo := &models.PaginationInfo{
+		PageSize: pageSize,
+		HasMore:  hasMore,
+	}
+	if hasMore {
+		paginationInfo.NextCursor = trades[len(trades)-1].OpScore
+	}
+
+	sendPaginatedResponse(w, http.StatusOK, true, trades, paginationInfo, "")
+}
+
+// GetMarketCandles returns the OHLC candles of the tick over the interval
+func GetMarketCandles(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		sendResponse(w, http.StatusMethodNotAllowed, false, nil, "Method not allowed")
+		return
+	}
+
+	tick := sanitizeString(r.URL.Query().Get("tick"))
+	if !validateTick(tick) {
+		sendResponse(w, http.StatusBadRequest, false, nil, "Invalid tick parameter")
+		return
+	}
+	intervalStr := r.URL.Query().Get("interval")
+	if intervalStr == "" {
+		intervalStr = "1h"
+	}
+	interval, ok := candleIntervalMap[intervalStr]
+	if !ok {
+		sendResponse(w, http.StatusBadRequest, false, nil, "Invalid interval parameter: must be one of 1m, 5m, 15m, 30m, 1h, 4h, 12h, 1d, 1w")
+		return
+	}
+
+	// Parse the time range in milliseconds, the last 100 intervals by default
+	mtsTo := time.Now().UnixMilli()
+	if toStr := r.URL.Query().Get("to"); toStr != "" {
+		value, err := strconv.ParseInt(toStr, 10, 64)
+		if err != nil || value <= 0 {
+			sendResponse(w, http.StatusBadRequest, false, nil, "Invalid to parameter")
+			return
+		}
+		mtsTo = value
+	}
+	mtsFrom := mtsTo - interval*100
+	if fromStr := r.URL.Query().Get("from"); fromStr != "" {
+		value, err := strconv.ParseInt(fromStr, 10, 64)
+		if err != nil || value < 0 || value >= mtsTo {
+			sendResponse(w, http.StatusBadRequest, false, nil, "Invalid from parameter")
+			return
+		}
+		mtsFrom = value
+	}
+	mtsFrom -= mtsFrom % interval
+	if (mtsTo-mtsFrom)/interval > lenCandleMax {
+		sendResponse(w, http.StatusBadRequest, false, nil, "Time range too large for the interval")
+		return
+	}
+
+	candles, err := storage.GetTradeCandles(tick, interval, mtsFrom, mtsTo)
+	if err != nil {
+		sendResponse(w, http.StatusInternalServerError, false, nil, "Failed to fetch candles: "+err.Error())
+		return
+	}
+
+	sendResponse(w, http.StatusOK, true, candles, "")
+}
+
+// GetMarketVolume returns the trade volume of the tick in the last 24 hours
+func GetMarketVolume(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		sendResponse(w, http.StatusMethodNotAllowed, false, nil, "Method not allowed")
+		return
+	}
+
+	tick := sanitizeString(r.URL.Query().Get("tick"))
+	if !validateTick(tick) {
+		sendResponse(w, http.StatusBadRequest, false, nil, "Invalid tick parameter")
+		return
+	}
+
+	mtsTo := time.Now().UnixMilli()
+	volume, err := storage.GetTradeVolume(tick, mtsTo-mtsVolumeWindow, mtsTo)
+	if err != nil {
+		sendResponse(w, http.StatusInternalServerError, false, nil, "Failed to fetch volume: "+err.Error())
+		return
+	}
+
+	sendResponse(w, http.StatusOK, true, volume, "")
+}
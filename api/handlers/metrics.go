@@ -0,0 +1,17 @@
+package handlers
+
+import (
+	"kasplex-executor/metrics"
+	"net/http"
+)
+
+// GetMetrics exposes the metrics of the scan, the storage and the api in the prometheus text format.
+func GetMetrics(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		sendResponse(w, http.StatusMethodNotAllowed, false, nil, "Method not allowed")
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	metrics.Write(w)
+}
@@ -0,0 +1,162 @@
+package handlers
+
+import (
+	"kasplex-executor/api/models"
+	"kasplex-executor/storage"
+	"net/http"
+	"strconv"
+)
+
+// validateTickCollection ensures the tick of the KRC-721 collection is valid:
+// - 1-10 characters
+// - Only uppercase alphabetical characters (A-Z) or digits (0-9)
+func validateTickCollection(tick string) bool {
+	length := len(tick)
+	if length < 1 || length > 10 {
+		return false
+	}
+	for _, r := range tick {
+		if (r < 'A' || r > 'Z') && (r < '0' || r > '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// parsePage returns the page and the page size of the request, the page size is 500 by default and 2000 at most.
+func parsePage(r *http.Request) (int, int) {
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	if page < 1 {
+		page = 1
+	}
+	pageSize, _ := strconv.Atoi(r.URL.Query().Get("pageSize"))
+	if pageSize < 1 || pageSize > 2000 {
+		pageSize = 500
+	}
+	return page, pageSize
+}
+
+// makePagination returns the range of the page in the total and the pagination info.
+func makePagination(page int, pageSize int, total int) (int, int, *models.PaginationInfo) {
+	start := (page - 1) * pageSize
+	if start > total {
+		start = total
+	}
+	end := start + pageSize
+	if end > total {
+		end = total
+	}
+	return start, end, &models.PaginationInfo{
+		CurrentPage:  page,
+		PageSize:     pageSize,
+		TotalPages:   (total + pageSize - 1) / pageSize,
+		TotalRecords: total,
+	}
+}
+
+// GetCollections returns all the KRC-721 collections, the newest deployed first
+func GetCollections(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		sendResponse(w, http.StatusMethodNotAllowed, false, nil, "Method not allowed")
+		return
+	}
+
+	page, pageSize := parsePage(r)
+	collectionList, err := storage.GetCollectionList()
+	if err != nil {
+		sendResponse(w, http.StatusInternalServerError, false, nil, "Failed to fetch collections: "+err.Error())
+		return
+	}
+
+	start, end, paginationInfo := makePagination(page, pageSize, len(collectionList))
+	sendPaginatedResponse(w, http.StatusOK, true, collectionList[start:end], paginationInfo, "")
+}
+
+// GetCollectionInfo returns the KRC-721 collection of the tick
+func GetCollectionInfo(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		sendResponse(w, http.StatusMethodNotAllowed, false, nil, "Method not allowed")
+		return
+	}
+
+	tick := sanitizeString(r.URL.Query().Get("tick"))
+	if !validateTickCollection(tick) {
+		sendResponse(w, http.StatusBadRequest, false, nil, "Invalid tick parameter: must be 1-10 uppercase letters or digits")
+		return
+	}
+
+	collection, err := storage.GetCollection(tick)
+	if err != nil {
+		sendResponse(w, http.StatusInternalServerError, false, nil, "Failed to fetch collection: "+err.Error())
+		return
+	}
+	if collection == nil {
+		sendResponse(w, http.StatusNotFound, false, nil, "Collection not found")
+		return
+	}
+
+	sendResponse(w, http.StatusOK, true, collection, "")
+}
+
+// GetNftOwner returns the owner of the KRC-721 token by the tick and the tokenId
+func GetNftOwner(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		sendResponse(w, http.StatusMethodNotAllowed, false, nil, "Method not allowed")
+		return
+	}
+
+	tick := sanitizeString(r.URL.Query().Get("tick"))
+	if !validateTickCollection(tick) {
+		sendResponse(w, http.StatusBadRequest, false, nil, "Invalid tick parameter: must be 1-10 uppercase letters or digits")
+		return
+	}
+	tokenId := r.URL.Query().Get("tokenId")
+	if id, err := strconv.ParseUint(tokenId, 10, 64); err != nil || id == 0 || strconv.FormatUint(id, 10) != tokenId {
+		sendResponse(w, http.StatusBadRequest, false, nil, "Invalid tokenId parameter")
+		return
+	}
+
+	nft, err := storage.GetNft(tick, tokenId)
+	if err != nil {
+		sendResponse(w, http.StatusInternalServerError, false, nil, "Failed to fetch token: "+err.Error())
+		return
+	}
+	if nft == nil {
+		sendResponse(w, http.StatusNotFound, false, nil, "Token not found")
+		return
+	}
+
+	sendResponse(w, http.StatusOK, true, nft, "")
+}
+
+// GetAddressNfts returns the KRC-721 tokens held by the address, optionally of the tick only
+func GetAddressNfts(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		sendResponse(w, http.StatusMethodNotAllowed, false, nil, "Method not allowed")
+		return
+	}
+
+	address := r.URL.Query().Get("address")
+	if address == "" {
+		sendResponse(w, http.StatusBadRequest, false, nil, "Address parameter is required")
+		return
+	}
+	tick := ""
+	if tickParam := r.URL.Query().Get("tick"); tickParam != "" {
+		tick = sanitizeString(tickParam)
+		if !validateTickCollection(tick) {
+			sendResponse(w, http.StatusBadRequest, false, nil, "Invalid tick parameter: must be 1-10 uppercase letters or digits")
+			return
+		}
+	}
+
+	page, pageSize := parsePage(r)
+	nftList, err := storage.GetNftListByOwner(address, tick)
+	if err != nil {
+		sendResponse(w, http.StatusInternalServerError, false, nil, "Failed to fetch tokens: "+err.Error())
+		return
+	}
+
+	start, end, paginationInfo := makePagination(page, pageSize, len(nftList))
+	sendPaginatedResponse(w, http.StatusOK, true, nftList[start:end], paginationInfo, "")
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"kasplex-executor/api/models"
 	"kasplex-executor/storage"
@@ -22,6 +23,11 @@ func GetTransaction(w http.ResponseWriter, r *http.Request) {
 		sendResponse(w, http.StatusBadRequest, false, nil, "Hash parameter is required")
 		return
 	}
+	decimal, err := parseFormatDecimal(r)
+	if err != nil {
+		sendResponse(w, http.StatusBadRequest, false, nil, "Invalid format parameter")
+		return
+	}
 
 	// Get operation details from storage
 	operation, err := storage.GetOperationByHash(hash)
@@ -34,6 +40,9 @@ func GetTransaction(w http.ResponseWriter, r *http.Request) {
 		sendResponse(w, http.StatusNotFound, false, nil, "Transaction not found")
 		return
 	}
+	if decimal {
+		tokenDecCache{}.formatOperation(operation)
+	}
 
 	// Process operation to extract data from opAccept
 	if operation.OpAccept != "" {
@@ -73,6 +82,12 @@ func GetAllTransactions(w http.ResponseWriter, r *http.Request) {
 		pageSize = 5000
 	}
 
+	decimal, err := parseFormatDecimal(r)
+	if err != nil {
+		sendResponse(w, http.StatusBadRequest, false, nil, "Invalid format parameter")
+		return
+	}
+
 	// Parse lastScore if provided
 	var lastScore *uint64
 	if lastScoreStr := r.URL.Query().Get("lastScore"); lastScoreStr != "" {
@@ -87,6 +102,9 @@ func GetAllTransactions(w http.ResponseWriter, r *http.Request) {
 		sendResponse(w, http.StatusInternalServerError, false, nil, "Failed to fetch transactions: "+err.Error())
 		return
 	}
+	if decimal {
+		formatOperations(operations)
+	}
 
 	// Create pagination info with requested pageSize
 	paginationInfo := &models.PaginationInfo{
@@ -96,3 +114,78 @@ func GetAllTransactions(w http.ResponseWriter, r *http.Request) {
 
 	sendPaginatedResponse(w, http.StatusOK, true, operations, paginationInfo, "")
 }
+
+// GetTokenOperations returns the operations of a token with cursor pagination in both directions
+func GetTokenOperations(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		sendResponse(w, http.StatusMethodNotAllowed, false, nil, "Method not allowed")
+		return
+	}
+
+	tick := sanitizeString(r.URL.Query().Get("tick"))
+	if !validateTick(tick) {
+		sendResponse(w, http.StatusBadRequest, false, nil, "Invalid tick parameter")
+		return
+	}
+
+	// Optional filters
+	op := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("op")))
+	var accepted *bool
+	if acceptedStr := r.URL.Query().Get("accepted"); acceptedStr != "" {
+		value, err := strconv.ParseBool(acceptedStr)
+		if err != nil {
+			sendResponse(w, http.StatusBadRequest, false, nil, "Invalid accepted parameter")
+			return
+		}
+		accepted = &value
+	}
+
+	// Parse pagination parameters, "next" for the older page and "prev" for the newer page
+	pageSize, _ := strconv.Atoi(r.URL.Query().Get("pageSize"))
+	if pageSize < 1 || pageSize > 500 {
+		pageSize = 50
+	}
+	cursor := uint64(0)
+	if cursorStr := r.URL.Query().Get("cursor"); cursorStr != "" {
+		score, err := strconv.ParseUint(cursorStr, 10, 64)
+		if err != nil {
+			sendResponse(w, http.StatusBadRequest, false, nil, "Invalid cursor parameter")
+			return
+		}
+		cursor = score
+	}
+	forward := false
+	switch r.URL.Query().Get("direction") {
+	case "", "next":
+	case "prev":
+		forward = true
+	default:
+		sendResponse(w, http.StatusBadRequest, false, nil, "Invalid direction parameter")
+		return
+	}
+	decimal, err := parseFormatDecimal(r)
+	if err != nil {
+		sendResponse(w, http.StatusBadRequest, false, nil, "Invalid format parameter")
+		return
+	}
+
+	operations, hasMore, err := storage.GetTokenOperations(tick, op, accepted, cursor, forward, pageSize)
+	if err != nil {
+		sendResponse(w, http.StatusInternalServerError, false, nil, "Failed to fetch token operations: "+err.Error())
+		return
+	}
+	if decimal {
+		formatOperations(operations)
+	}
+
+	paginationInfo := &models.PaginationInfo{
+		PageSize: pageSize,
+		HasMore:  hasMore,
+	}
+	if len(operations) > 0 {
+		paginationInfo.PrevCursor = operations[0].OpScore
+		paginationInfo.NextCursor = operations[len(operations)-1].OpScore
+	}
+
+	sendPaginatedResponse(w, http.StatusOK, true, operations, paginationInfo, "")
+}
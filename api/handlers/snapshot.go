@@ -5,7 +5,6 @@ import (
 	"log"
 	"math/big"
 	"net/http"
-	"strconv"
 	"time"
 
 	"kasplex-executor/api/models"
@@ -28,6 +27,11 @@ func GetTokenSnapshot(w http.ResponseWriter, r *http.Request) {
 		sendResponse(w, http.StatusBadRequest, false, nil, "Invalid tick parameter: must be 4-6 uppercase letters (A-Z)")
 		return
 	}
+	decimal, err := parseFormatDecimal(r)
+	if err != nil {
+		sendResponse(w, http.StatusBadRequest, false, nil, "Invalid format parameter")
+		return
+	}
 
 	// Get token info first to get the max supply
 	tokenInfo, err := storage.GetTokenInfo(tick)
@@ -49,11 +53,11 @@ func GetTokenSnapshot(w http.ResponseWriter, r *http.Request) {
 	log.Printf("DEBUG: Found %d balances for tick: %s", len(balances), tick)
 
 	// Process the snapshot
-	snapshot := processSnapshot(tick, balances, tokenInfo)
+	snapshot := processSnapshot(tick, balances, tokenInfo, decimal)
 	sendResponse(w, http.StatusOK, true, snapshot, "")
 }
 
-func processSnapshot(tick string, balances []*models.TokenBalance, tokenInfo *models.TokenInfo) *models.TokenSnapshot {
+func processSnapshot(tick string, balances []*models.TokenBalance, tokenInfo *models.TokenInfo, decimal bool) *models.TokenSnapshot {
 	log.Printf("DEBUG: Processing snapshot for tick %s with %d balances", tick, len(balances))
 
 	snapshot := &models.TokenSnapshot{
@@ -80,14 +84,19 @@ func processSnapshot(tick string, balances []*models.TokenBalance, tokenInfo *mo
 
 	log.Printf("DEBUG: Max supply (string): %s", maxStr)
 
-	var lockedTokens uint64
-	var totalBalance uint64
+	lockedInt := new(big.Int)
+	totalBalance := new(big.Int)
+	totalList := make([]*big.Int, 0, len(balances))
 
 	// Process each balance
 	for _, balance := range balances {
-		if balance.Balance > 0 || balance.Locked > 0 {
-			lockedTokens += balance.Locked
-			totalBalance += balance.Balance + balance.Locked
+		bal := storage.ParseAmount(balance.Balance)
+		lock := storage.ParseAmount(balance.Locked)
+		if bal.Sign() > 0 || lock.Sign() > 0 {
+			lockedInt.Add(lockedInt, lock)
+			total := new(big.Int).Add(bal, lock)
+			totalBalance.Add(totalBalance, total)
+			totalList = append(totalList, total)
 
 			holder := models.TokenHolder{
 				Address: balance.Address,
@@ -99,44 +108,45 @@ func processSnapshot(tick string, balances []*models.TokenBalance, tokenInfo *mo
 		}
 	}
 
-	log.Printf("DEBUG: Found %d holders, total balance: %d, locked: %d", len(snapshot.Holders), totalBalance, lockedTokens)
+	log.Printf("DEBUG: Found %d holders, total balance: %s, locked: %s", len(snapshot.Holders), totalBalance.String(), lockedInt.String())
 
-	// Calculate shares and summary
+	// Calculate exact shares of the max supply
+	maxInt := storage.ParseAmount(maxStr)
 	for i := range snapshot.Holders {
-		total := snapshot.Holders[i].Balance + snapshot.Holders[i].Locked
-		// Parse maxStr as big.Int for accurate share calculation
-		maxInt := new(big.Int)
-		maxInt.SetString(maxStr, 10)
-		if maxInt.Sign() > 0 {
-			totalBig := new(big.Int).SetUint64(total)
-			share := new(big.Float).Quo(
-				new(big.Float).SetInt(totalBig),
-				new(big.Float).SetInt(maxInt),
-			)
-			share = share.Mul(share, new(big.Float).SetInt64(100))
-			shareFloat, _ := share.Float64()
-			snapshot.Holders[i].Share = shareFloat
-		}
+		snapshot.Holders[i].Share = storage.ShareOf(totalList[i], maxInt)
 	}
 
-	// Convert locked tokens to string
-	lockedStr := strconv.FormatUint(lockedTokens, 10)
+	lockedStr := lockedInt.String()
+	burned := storage.MetaBurned(metaData)
 
-	// Calculate circulating supply using big.Int for accuracy
-	maxInt := new(big.Int)
-	maxInt.SetString(maxStr, 10)
-	lockedInt := new(big.Int)
-	lockedInt.SetString(lockedStr, 10)
+	// Calculate circulating supply using big.Int for accuracy, the burned supply is excluded
 	circulatingInt := new(big.Int).Sub(maxInt, lockedInt)
+	circulatingInt.Sub(circulatingInt, storage.ParseAmount(burned))
 
 	// Fill summary with string values
 	snapshot.Summary = models.SnapshotSummary{
 		TotalSupply:       maxStr,
 		HoldersCount:      len(snapshot.Holders),
 		LockedTokens:      lockedStr,
+		BurnedTokens:      burned,
 		CirculatingSupply: circulatingInt.String(),
 	}
 
+	if decimal {
+		dec := 0
+		if decFloat, ok := metaData["dec"].(float64); ok {
+			dec = int(decFloat)
+		}
+		for i := range snapshot.Holders {
+			snapshot.Holders[i].BalanceFormatted = storage.FormatAmount(snapshot.Holders[i].Balance, dec)
+			snapshot.Holders[i].LockedFormatted = storage.FormatAmount(snapshot.Holders[i].Locked, dec)
+		}
+		snapshot.Summary.TotalSupplyFormatted = storage.FormatAmount(maxStr, dec)
+		snapshot.Summary.LockedTokensFormatted = storage.FormatAmount(lockedStr, dec)
+		snapshot.Summary.BurnedTokensFormatted = storage.FormatAmount(burned, dec)
+		snapshot.Summary.CirculatingSupplyFormatted = storage.FormatAmount(circulatingInt.String(), dec)
+	}
+
 	log.Printf("DEBUG: Final summary - Total: %s, Holders: %d, Locked: %s, Circulating: %s",
 		maxStr, len(snapshot.Holders), lockedStr, circulatingInt.String())
 
@@ -156,6 +166,11 @@ func GetTokenCirculatingSupply(w http.ResponseWriter, r *http.Request) {
 		sendResponse(w, http.StatusBadRequest, false, nil, "Invalid tick parameter: must be 4-6 uppercase letters (A-Z)")
 		return
 	}
+	decimal, err := parseFormatDecimal(r)
+	if err != nil {
+		sendResponse(w, http.StatusBadRequest, false, nil, "Invalid format parameter")
+		return
+	}
 
 	// Get token info to get the max supply
 	tokenInfo, err := storage.GetTokenInfo(tick)
@@ -185,25 +200,27 @@ func GetTokenCirculatingSupply(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Calculate total locked tokens
-	var lockedTokens uint64
+	lockedInt := new(big.Int)
 	for _, balance := range balances {
-		lockedTokens += balance.Locked
+		lockedInt.Add(lockedInt, storage.ParseAmount(balance.Locked))
 	}
 
-	// Convert locked tokens to string for big number operations
-	lockedStr := strconv.FormatUint(lockedTokens, 10)
-
-	// Calculate circulating supply using big.Int for accuracy
-	maxInt := new(big.Int)
-	maxInt.SetString(maxStr, 10)
-	lockedInt := new(big.Int)
-	lockedInt.SetString(lockedStr, 10)
+	// Calculate circulating supply using big.Int for accuracy, the burned supply is excluded
+	maxInt := storage.ParseAmount(maxStr)
 	circulatingInt := new(big.Int).Sub(maxInt, lockedInt)
+	circulatingInt.Sub(circulatingInt, storage.ParseAmount(storage.MetaBurned(metaData)))
 
 	// Create response with just the circulating supply
 	response := map[string]string{
 		"circulatingSupply": circulatingInt.String(),
 	}
+	if decimal {
+		dec := 0
+		if decFloat, ok := metaData["dec"].(float64); ok {
+			dec = int(decFloat)
+		}
+		response["circulatingSupplyFormatted"] = storage.FormatAmount(circulatingInt.String(), dec)
+	}
 
 	sendResponse(w, http.StatusOK, true, response, "")
 }
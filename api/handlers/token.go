@@ -5,7 +5,6 @@ import (
 	"kasplex-executor/storage"
 	"math/big"
 	"net/http"
-	"strconv"
 )
 
 func GetTokenBalances(w http.ResponseWriter, r *http.Request) {
@@ -19,6 +18,11 @@ func GetTokenBalances(w http.ResponseWriter, r *http.Request) {
 		sendResponse(w, http.StatusBadRequest, false, nil, "Invalid tick parameter")
 		return
 	}
+	decimal, err := parseFormatDecimal(r)
+	if err != nil {
+		sendResponse(w, http.StatusBadRequest, false, nil, "Invalid format parameter")
+		return
+	}
 
 	// Query storage for balances
 	balances, err := storage.GetTokenBalances(tick)
@@ -26,6 +30,12 @@ func GetTokenBalances(w http.ResponseWriter, r *http.Request) {
 		sendResponse(w, http.StatusInternalServerError, false, nil, "Failed to fetch balances")
 		return
 	}
+	if decimal {
+		for _, balance := range balances {
+			balance.BalanceFormatted = storage.FormatAmount(balance.Balance, balance.Dec)
+			balance.LockedFormatted = storage.FormatAmount(balance.Locked, balance.Dec)
+		}
+	}
 
 	sendResponse(w, http.StatusOK, true, balances, "")
 }
@@ -41,6 +51,11 @@ func GetTokenInfo(w http.ResponseWriter, r *http.Request) {
 		sendResponse(w, http.StatusBadRequest, false, nil, "Invalid tick parameter")
 		return
 	}
+	decimal, err := parseFormatDecimal(r)
+	if err != nil {
+		sendResponse(w, http.StatusBadRequest, false, nil, "Invalid format parameter")
+		return
+	}
 
 	// Query storage for token info
 	info, err := storage.GetTokenInfo(tick)
@@ -70,14 +85,11 @@ func GetTokenInfo(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Calculate total locked tokens
-	var lockedTokens uint64
+	lockedInt := new(big.Int)
 	for _, balance := range balances {
-		lockedTokens += balance.Locked
+		lockedInt.Add(lockedInt, storage.ParseAmount(balance.Locked))
 	}
 
-	// Convert locked tokens to string for big number operations
-	lockedStr := strconv.FormatUint(lockedTokens, 10)
-
 	// Get max supply from metadata
 	maxStr, ok := metaData["max"].(string)
 	if !ok {
@@ -85,12 +97,11 @@ func GetTokenInfo(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Calculate circulating supply using big.Int for accuracy
-	maxInt := new(big.Int)
-	maxInt.SetString(maxStr, 10)
-	lockedInt := new(big.Int)
-	lockedInt.SetString(lockedStr, 10)
+	// Calculate circulating supply using big.Int for accuracy, the burned supply is excluded
+	burned := storage.MetaBurned(metaData)
+	maxInt := storage.ParseAmount(maxStr)
 	circulatingInt := new(big.Int).Sub(maxInt, lockedInt)
+	circulatingInt.Sub(circulatingInt, storage.ParseAmount(burned))
 
 	// Create a new response structure
 	response := map[string]interface{}{
@@ -105,11 +116,29 @@ func GetTokenInfo(w http.ResponseWriter, r *http.Request) {
 		"opadd":             metaData["opadd"],
 		"mtsadd":            metaData["mtsadd"],
 		"minted":            info.Minted,
+		"burned":            burned,
+		"mod":               storage.MetaMod(metaData),
+		"owner":             storage.MetaOwner(metaData),
 		"op_mod":            info.OpMod,
 		"mts_mod":           info.MtsMod,
-		"lockedTokens":      lockedStr,
+		"lockedTokens":      lockedInt.String(),
 		"circulatingSupply": circulatingInt.String(),
 	}
+	if decimal {
+		dec := 0
+		if decFloat, ok := metaData["dec"].(float64); ok {
+			dec = int(decFloat)
+		}
+		for _, key := range []string{"max", "lim", "pre"} {
+			if value, ok := metaData[key].(string); ok {
+				response[key+"Formatted"] = storage.FormatAmount(value, dec)
+			}
+		}
+		response["mintedFormatted"] = storage.FormatAmount(info.Minted, dec)
+		response["burnedFormatted"] = storage.FormatAmount(burned, dec)
+		response["lockedTokensFormatted"] = storage.FormatAmount(lockedInt.String(), dec)
+		response["circulatingSupplyFormatted"] = storage.FormatAmount(circulatingInt.String(), dec)
+	}
 
 	sendResponse(w, http.StatusOK, true, response, "")
 }
@@ -120,11 +149,26 @@ func GetAllTokens(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	decimal, err := parseFormatDecimal(r)
+	if err != nil {
+		sendResponse(w, http.StatusBadRequest, false, nil, "Invalid format parameter")
+		return
+	}
+
 	tokens, err := storage.GetAllTokens()
 	if err != nil {
 		sendResponse(w, http.StatusInternalServerError, false, nil, "Failed to fetch tokens: "+err.Error())
 		return
 	}
+	if decimal {
+		for i := range tokens {
+			tokens[i].MaxFormatted = storage.FormatAmount(tokens[i].Max, tokens[i].Dec)
+			tokens[i].LimFormatted = storage.FormatAmount(tokens[i].Lim, tokens[i].Dec)
+			tokens[i].PreFormatted = storage.FormatAmount(tokens[i].Pre, tokens[i].Dec)
+			tokens[i].MintedFormatted = storage.FormatAmount(tokens[i].Minted, tokens[i].Dec)
+			tokens[i].BurnedFormatted = storage.FormatAmount(tokens[i].Burned, tokens[i].Dec)
+		}
+	}
 
 	sendResponse(w, http.StatusOK, true, tokens, "")
 }
@@ -0,0 +1,171 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"kasplex-executor/api/models"
+	"kasplex-executor/storage"
+	"kasplex-executor/webhook"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Max size of the webhook registration body.
+const lenWebhookBodyMax = 65536
+
+// Webhooks lists (GET), registers (POST) or removes (DELETE ?id=) the webhooks.
+// The admin token configured in webhook.token is required as "Authorization: Bearer <token>".
+func Webhooks(w http.ResponseWriter, r *http.Request) {
+	if !authorizeWebhook(w, r) {
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		webhookList := webhook.GetList()
+		result := make([]models.Webhook, 0, len(webhookList))
+		for _, data := range webhookList {
+			result = append(result, makeWebhook(&data))
+		}
+		sendResponse(w, http.StatusOK, true, result, "")
+
+	case http.MethodPost:
+		request := models.WebhookRequest{}
+		decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, lenWebhookBodyMax))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&request); err != nil {
+			sendResponse(w, http.StatusBadRequest, false, nil, "Invalid request body")
+			return
+		}
+		data := storage.DataWebhookType{
+			Url:     strings.TrimSpace(request.Url),
+			Receive: request.Receive,
+		}
+		for _, tick := range request.Tick {
+			tick = sanitizeString(tick)
+			if !validateTick(tick) {
+				sendResponse(w, http.StatusBadRequest, false, nil, "Invalid tick parameter")
+				return
+			}
+			data.TickList = append(data.TickList, tick)
+		}
+		for _, address := range request.Address {
+			if address = strings.TrimSpace(address); address != "" {
+				data.AddressList = append(data.AddressList, address)
+			}
+		}
+		for _, op := range request.Op {
+			if op = strings.ToLower(strings.TrimSpace(op)); op != "" {
+				data.OpList = append(data.OpList, op)
+			}
+		}
+		if data.Receive && len(data.AddressList) == 0 {
+			sendResponse(w, http.StatusBadRequest, false, nil, "Address is required with receive")
+			return
+		}
+		registered, err := webhook.Register(data)
+		if err != nil {
+			sendWebhookError(w, err)
+			return
+		}
+		result := makeWebhook(registered)
+		result.Secret = registered.Secret
+		sendResponse(w, http.StatusCreated, true, result, "")
+
+	case http.MethodDelete:
+		if err := webhook.Remove(r.URL.Query().Get("id")); err != nil {
+			sendWebhookError(w, err)
+			return
+		}
+		sendResponse(w, http.StatusOK, true, nil, "")
+
+	default:
+		sendResponse(w, http.StatusMethodNotAllowed, false, nil, "Method not allowed")
+	}
+}
+
+// WebhookDeadLetters lists (GET) or clears (DELETE) the dead letters of the webhook by ?id=.
+// The list is in descending order, limited by the optional limit parameter (default 100, max 1000).
+func WebhookDeadLetters(w http.ResponseWriter, r *http.Request) {
+	if !authorizeWebhook(w, r) {
+		return
+	}
+	id := r.URL.Query().Get("id")
+
+	switch r.Method {
+	case http.MethodGet:
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		if limit < 1 || limit > 1000 {
+			limit = 100
+		}
+		deadList, err := webhook.GetDeadList(id, limit)
+		if err != nil {
+			sendWebhookError(w, err)
+			return
+		}
+		result := make([]models.WebhookDeadLetter, 0, len(deadList))
+		for _, dead := range deadList {
+			result = append(result, models.WebhookDeadLetter{
+				WebhookId:  dead.WebhookId,
+				DeliveryId: dead.DeliveryId,
+				Payload:    json.RawMessage(dead.Payload),
+				Attempt:    dead.Attempt,
+				Error:      dead.Error,
+				MtsAdd:     dead.MtsAdd,
+			})
+		}
+		sendResponse(w, http.StatusOK, true, result, "")
+
+	case http.MethodDelete:
+		count, err := webhook.ClearDeadList(id)
+		if err != nil {
+			sendWebhookError(w, err)
+			return
+		}
+		sendResponse(w, http.StatusOK, true, map[string]int{"deleted": count}, "")
+
+	default:
+		sendResponse(w, http.StatusMethodNotAllowed, false, nil, "Method not allowed")
+	}
+}
+
+// authorizeWebhook checks the bearer token, the response is sent if failed.
+func authorizeWebhook(w http.ResponseWriter, r *http.Request) bool {
+	if !webhook.Enabled() {
+		sendResponse(w, http.StatusServiceUnavailable, false, nil, "Webhook disabled")
+		return false
+	}
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || !webhook.Authorize(token) {
+		sendResponse(w, http.StatusUnauthorized, false, nil, "Unauthorized")
+		return false
+	}
+	return true
+}
+
+func sendWebhookError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, webhook.ErrDisabled):
+		sendResponse(w, http.StatusServiceUnavailable, false, nil, "Webhook disabled")
+	case errors.Is(err, webhook.ErrNotFound):
+		sendResponse(w, http.StatusNotFound, false, nil, "Webhook not found")
+	case errors.Is(err, webhook.ErrInvalidUrl), errors.Is(err, webhook.ErrTooMany):
+		sendResponse(w, http.StatusBadRequest, false, nil, "Failed to register webhook: "+err.Error())
+	default:
+		sendResponse(w, http.StatusInternalServerError, false, nil, "Webhook error: "+err.Error())
+	}
+}
+
+func makeWebhook(data *storage.DataWebhookType) models.Webhook {
+	return models.Webhook{
+		Id:         data.Id,
+		Url:        data.Url,
+		Tick:       data.TickList,
+		Address:    data.AddressList,
+		Op:         data.OpList,
+		Receive:    data.Receive,
+		OpScoreAdd: data.OpScoreAdd,
+		MtsAdd:     data.MtsAdd,
+	}
+}
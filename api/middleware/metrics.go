@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"kasplex-executor/metrics"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type MetricsMiddleware struct {
+	mux *http.ServeMux
+}
+
+// NewMetricsMiddleware records the latency and status of each request, by the route registered in the mux.
+func NewMetricsMiddleware(mux *http.ServeMux) *MetricsMiddleware {
+	return &MetricsMiddleware{
+		mux: mux,
+	}
+}
+
+func (m *MetricsMiddleware) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+
+		rw := &responseWriter{w, http.StatusOK}
+
+		next.ServeHTTP(rw, r)
+
+		// Use the route pattern instead of the path, the unknown paths are counted as "other".
+		_, route := m.mux.Handler(r)
+		if route == "" {
+			route = "other"
+		}
+		metrics.ApiRequestTotal.Inc(route, r.Method, strconv.Itoa(rw.status))
+		metrics.ApiRequestSeconds.Observe(time.Since(start).Seconds(), route)
+	})
+}
@@ -1,10 +1,12 @@
 package models
 
 type AddressBalance struct {
-	Tick    string `json:"tick"`
-	Balance uint64 `json:"balance"`
-	Locked  uint64 `json:"locked"`
-	Dec     int    `json:"decimals"`
+	Tick             string `json:"tick"`
+	Balance          string `json:"balance"`
+	Locked           string `json:"locked"`
+	Dec              int    `json:"decimals"`
+	BalanceFormatted string `json:"balanceFormatted,omitempty"`
+	LockedFormatted  string `json:"lockedFormatted,omitempty"`
 }
 
 // AddressPortfolio represents an address and all its token balances
@@ -12,3 +14,24 @@ type AddressPortfolio struct {
 	Address  string            `json:"address"`
 	Balances []*AddressBalance `json:"balances"`
 }
+
+// AddressOperation represents an operation affecting the address
+type AddressOperation struct {
+	P        string `json:"p"`
+	Op       string `json:"op"`
+	Tick     string `json:"tick"`
+	Amt      string `json:"amt"`
+	From     string `json:"from"`
+	To       string `json:"to"`
+	Balance  string `json:"balance"`
+	OpScore  string `json:"opScore"`
+	HashRev  string `json:"hashRev"`
+	FeeRev   string `json:"feeRev"`
+	OpAccept string `json:"opAccept"`
+	OpError  string `json:"opError"`
+	OpEvent  string `json:"opEvent,omitempty"`
+	MtsAdd   string `json:"mtsAdd"`
+	// Rendered with the token decimals if format=decimal
+	AmtFormatted     string `json:"amtFormatted,omitempty"`
+	BalanceFormatted string `json:"balanceFormatted,omitempty"`
+}
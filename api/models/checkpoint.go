@@ -0,0 +1,27 @@
+package models
+
+// CheckpointInfo is the checkpoint of the last accepted op at the opScore or daaScore
+type CheckpointInfo struct {
+	OpScore    uint64 `json:"opScore"`
+	DaaScore   uint64 `json:"daaScore"`
+	HashRev    string `json:"hashRev,omitempty"`
+	Checkpoint string `json:"checkpoint"`
+}
+
+// PeerStatus is the last comparison result with a peer indexer
+type PeerStatus struct {
+	Peer           string `json:"peer"`
+	State          string `json:"state"` // consistent, diverged, unreachable, pending
+	DaaScore       uint64 `json:"daaScore,omitempty"`
+	Checkpoint     string `json:"checkpoint,omitempty"`
+	CheckpointPeer string `json:"checkpointPeer,omitempty"`
+	Error          string `json:"error,omitempty"`
+	MtsCheck       int64  `json:"mtsCheck,omitempty"`
+}
+
+// ConsensusStatus is raised to alert if any peer diverged
+type ConsensusStatus struct {
+	Alert    bool         `json:"alert"`
+	MtsAlert int64        `json:"mtsAlert,omitempty"`
+	Peers    []PeerStatus `json:"peers"`
+}
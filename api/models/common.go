@@ -10,11 +10,13 @@ type Response struct {
 // PaginationInfo provides standardized pagination information across all endpoints.
 // Some fields are optional depending on the pagination type:
 // - For total-based pagination: TotalPages and TotalRecords are used
-// - For cursor-based pagination: HasMore is used
+// - For cursor-based pagination: HasMore, NextCursor and PrevCursor are used
 type PaginationInfo struct {
-	CurrentPage  int  `json:"currentPage,omitempty"`
-	PageSize     int  `json:"pageSize"`
-	TotalPages   int  `json:"totalPages,omitempty"`
-	TotalRecords int  `json:"totalRecords,omitempty"`
-	HasMore      bool `json:"hasMore,omitempty"`
+	CurrentPage  int    `json:"currentPage,omitempty"`
+	PageSize     int    `json:"pageSize"`
+	TotalPages   int    `json:"totalPages,omitempty"`
+	TotalRecords int    `json:"totalRecords,omitempty"`
+	HasMore      bool   `json:"hasMore,omitempty"`
+	NextCursor   string `json:"nextCursor,omitempty"`
+	PrevCursor   string `json:"prevCursor,omitempty"`
 }
@@ -0,0 +1,25 @@
+package models
+
+// ComponentHealth is the reachability of a storage
+type ComponentHealth struct {
+	Ok    bool   `json:"ok"`
+	Error string `json:"error,omitempty"`
+}
+
+// HealthStatus is the state of the storages and the scan, the lag is the daaScore behind the node source
+type HealthStatus struct {
+	Status       string          `json:"status"` // ok, unhealthy, ready, unready
+	Version      string          `json:"version"`
+	Cassandra    ComponentHealth `json:"cassandra"`
+	Rocksdb      ComponentHealth `json:"rocksdb"`
+	DaaScore     uint64          `json:"daaScore"`
+	DaaScoreTip  uint64          `json:"daaScoreTip"`
+	Lag          uint64          `json:"lag"`
+	LagMax       uint64          `json:"lagMax,omitempty"`
+	OpScore      uint64          `json:"opScore"`
+	Synced       bool            `json:"synced"`
+	CatchingUp   bool            `json:"catchingUp"`
+	ScanError    string          `json:"scanError,omitempty"`
+	MtsBatchLast int64           `json:"mtsBatchLast,omitempty"`
+	Reasons      []string        `json:"reasons,omitempty"`
+}
@@ -6,9 +6,11 @@ type HoldersResponse struct {
 }
 
 type HolderInfo struct {
-	Address string  `json:"address"`
-	Balance uint64  `json:"balance"`
-	Locked  uint64  `json:"locked"`
-	Share   float64 `json:"share"`
-	Rank    int     `json:"rank"`
+	Address          string `json:"address"`
+	Balance          string `json:"balance"`
+	Locked           string `json:"locked"`
+	Share            string `json:"share"` // Percentage of total supply
+	Rank             int    `json:"rank"`
+	BalanceFormatted string `json:"balanceFormatted,omitempty"`
+	LockedFormatted  string `json:"lockedFormatted,omitempty"`
 }
@@ -0,0 +1,53 @@
+package models
+
+// MarketOrder represents an open list order in the marketplace
+type MarketOrder struct {
+	Tick        string `json:"tick"`
+	Address     string `json:"address"`     // Address of the seller
+	P2shAddress string `json:"p2shAddress"` // Address holding the KAS of the order
+	UTxId       string `json:"utxid"`       // Transaction id of the list op
+	UAmt        string `json:"uamt"`        // KAS price in sompi
+	TAmt        string `json:"tamt"`        // Token amount in base units
+	UScript     string `json:"uscript"`
+	OpScore     string `json:"opScore"` // opScore of the list op
+	// Rendered with the decimals if format=decimal
+	UAmtFormatted string `json:"uamtFormatted,omitempty"`
+	TAmtFormatted string `json:"tamtFormatted,omitempty"`
+}
+
+// Trade represents an order filled or canceled by the send op
+type Trade struct {
+	Tick      string `json:"tick"`
+	Event     string `json:"event"` // "trade" or "cancel"
+	HashRev   string `json:"hashRev"`
+	Seller    string `json:"seller"`
+	Buyer     string `json:"buyer"`
+	Amt       string `json:"amt"`       // Token amount in base units
+	Price     string `json:"price"`     // KAS paid in sompi
+	UnitPrice string `json:"unitPrice"` // KAS per whole token
+	OpScore   string `json:"opScore"`
+	MtsAdd    int64  `json:"mtsAdd"`
+}
+
+// TradeCandle represents the OHLC of the unit price in an interval, in KAS per whole token
+type TradeCandle struct {
+	MtsOpen int64  `json:"mtsOpen"` // Start of the interval in milliseconds
+	Open    string `json:"open"`
+	High    string `json:"high"`
+	Low     string `json:"low"`
+	Close   string `json:"close"`
+	Volume  string `json:"volume"` // KAS paid in sompi
+	Amt     string `json:"amt"`    // Token amount in base units
+	Trades  int    `json:"trades"`
+}
+
+// TradeVolume represents the trade volume of the tick in the window
+type TradeVolume struct {
+	Tick      string `json:"tick"`
+	MtsFrom   int64  `json:"mtsFrom"`
+	MtsTo     int64  `json:"mtsTo"`
+	Volume    string `json:"volume"`    // KAS paid in sompi
+	VolumeKas string `json:"volumeKas"` // KAS paid
+	Amt       string `json:"amt"`       // Token amount in base units
+	Trades    int    `json:"trades"`
+}
@@ -0,0 +1,23 @@
+package models
+
+// Collection represents a KRC-721 collection
+type Collection struct {
+	Tick       string `json:"tick"`
+	Max        string `json:"max"`
+	Buri       string `json:"buri"` // Base uri of the token metadata
+	Deployer   string `json:"deployer"`
+	Minted     string `json:"minted"` // Count of the tokens minted, also the last token id
+	HashRev    string `json:"hashRev"`
+	OpScoreAdd uint64 `json:"opScoreAdd"`
+	OpScoreMod uint64 `json:"opScoreMod"`
+	MtsAdd     int64  `json:"mtsAdd"`
+	MtsMod     int64  `json:"mtsMod"`
+}
+
+// Nft represents a KRC-721 token and its owner
+type Nft struct {
+	Tick    string `json:"tick"`
+	TokenId string `json:"tokenId"`
+	Owner   string `json:"owner"`
+	OpScore string `json:"opScore"` // opScore of the last mint or transfer
+}
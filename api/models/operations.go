@@ -21,7 +21,10 @@ type Operation struct {
 	BlockAccept string `json:"blockAccept"`
 	OpAccept    string `json:"opAccept"`
 	OpError     string `json:"opError"`
+	OpEvent     string `json:"opEvent,omitempty"`
 	Checkpoint  string `json:"checkpoint"`
 	MtsAdd      string `json:"mtsAdd"`
 	MtsMod      string `json:"mtsMod"`
+	// Rendered with the token decimals if format=decimal
+	AmtFormatted string `json:"amtFormatted,omitempty"`
 }
@@ -1,16 +1,18 @@
 package models
 
 type PortfolioHolding struct {
-	Tick    string `json:"tick"`
-	Balance uint64 `json:"balance"`
-	Locked  uint64 `json:"locked"`
-	Dec     int    `json:"decimals"`
+	Tick             string `json:"tick"`
+	Balance          string `json:"balance"`
+	Locked           string `json:"locked"`
+	Dec              int    `json:"decimals"`
+	BalanceFormatted string `json:"balanceFormatted,omitempty"`
+	LockedFormatted  string `json:"lockedFormatted,omitempty"`
 }
 
 type HolderPortfolio struct {
 	Address    string             `json:"address"`
 	TokenCount int                `json:"tokenCount"`
-	TotalValue uint64             `json:"totalValue"`
+	TotalValue string             `json:"totalValue"`
 	Holdings   []PortfolioHolding `json:"holdings"`
 }
 
@@ -1,16 +1,18 @@
 package models
 
 type TokenBalance struct {
-	Address string `json:"address"`
-	Balance uint64 `json:"balance"`
-	Locked  uint64 `json:"locked"`
-	Dec     int    `json:"decimals"`
+	Address          string `json:"address"`
+	Balance          string `json:"balance"`
+	Locked           string `json:"locked"`
+	Dec              int    `json:"decimals"`
+	BalanceFormatted string `json:"balanceFormatted,omitempty"`
+	LockedFormatted  string `json:"lockedFormatted,omitempty"`
 }
 
 type TokenInfo struct {
 	Tick   string `json:"tick"`
 	Meta   string `json:"meta"`
-	Minted uint64 `json:"minted"`
+	Minted string `json:"minted"`
 	OpMod  int64  `json:"op_mod"`
 	MtsMod int64  `json:"mts_mod"`
 }
@@ -32,10 +34,12 @@ type TokenSnapshot struct {
 
 // TokenHolder represents a single holder's balance
 type TokenHolder struct {
-	Address string  `json:"address"`
-	Balance uint64  `json:"balance"`
-	Locked  uint64  `json:"locked"`
-	Share   float64 `json:"share"` // Percentage of total supply
+	Address          string `json:"address"`
+	Balance          string `json:"balance"`
+	Locked           string `json:"locked"`
+	Share            string `json:"share"` // Percentage of total supply
+	BalanceFormatted string `json:"balanceFormatted,omitempty"`
+	LockedFormatted  string `json:"lockedFormatted,omitempty"`
 }
 
 // SnapshotSummary provides overview statistics
@@ -43,7 +47,13 @@ type SnapshotSummary struct {
 	TotalSupply       string `json:"totalSupply"`
 	HoldersCount      int    `json:"holdersCount"`
 	LockedTokens      string `json:"lockedTokens"`
+	BurnedTokens      string `json:"burnedTokens"`
 	CirculatingSupply string `json:"circulatingSupply"`
+	// Rendered with the token decimals if format=decimal
+	TotalSupplyFormatted       string `json:"totalSupplyFormatted,omitempty"`
+	LockedTokensFormatted      string `json:"lockedTokensFormatted,omitempty"`
+	BurnedTokensFormatted      string `json:"burnedTokensFormatted,omitempty"`
+	CirculatingSupplyFormatted string `json:"circulatingSupplyFormatted,omitempty"`
 }
 
 type TokenListItem struct {
@@ -54,11 +64,20 @@ type TokenListItem struct {
 	To         string `json:"to"`
 	Dec        int    `json:"dec"`
 	Minted     string `json:"minted"`
+	Burned     string `json:"burned"`
 	OpScoreAdd uint64 `json:"opScoreAdd"`
 	OpScoreMod uint64 `json:"opScoreMod"`
 	State      string `json:"state"`
 	HashRev    string `json:"hashRev"`
 	MtsAdd     int64  `json:"mtsAdd"`
+	Mod        string `json:"mod"`             // "mint" or "issue"
+	Owner      string `json:"owner,omitempty"` // the issuer in the issue mode
+	// Rendered with the token decimals if format=decimal
+	MaxFormatted    string `json:"maxFormatted,omitempty"`
+	LimFormatted    string `json:"limFormatted,omitempty"`
+	PreFormatted    string `json:"preFormatted,omitempty"`
+	MintedFormatted string `json:"mintedFormatted,omitempty"`
+	BurnedFormatted string `json:"burnedFormatted,omitempty"`
 }
 
 type TokenListResponse struct {
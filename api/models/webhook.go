@@ -0,0 +1,36 @@
+package models
+
+import "encoding/json"
+
+// WebhookRequest registers a webhook, each filter list matches any if empty.
+// With receive set, only the ops increasing the balance of the addresses are delivered.
+type WebhookRequest struct {
+	Url     string   `json:"url"`
+	Tick    []string `json:"tick,omitempty"`
+	Address []string `json:"address,omitempty"`
+	Op      []string `json:"op,omitempty"`
+	Receive bool     `json:"receive,omitempty"`
+}
+
+// Webhook is a registered webhook, the secret is only returned on registration
+type Webhook struct {
+	Id         string   `json:"id"`
+	Url        string   `json:"url"`
+	Secret     string   `json:"secret,omitempty"`
+	Tick       []string `json:"tick,omitempty"`
+	Address    []string `json:"address,omitempty"`
+	Op         []string `json:"op,omitempty"`
+	Receive    bool     `json:"receive"`
+	OpScoreAdd uint64   `json:"opScoreAdd"`
+	MtsAdd     int64    `json:"mtsAdd"`
+}
+
+// WebhookDeadLetter is a delivery failed after all the attempts
+type WebhookDeadLetter struct {
+	WebhookId  string          `json:"webhookId"`
+	DeliveryId string          `json:"deliveryId"`
+	Payload    json.RawMessage `json:"payload"`
+	Attempt    int             `json:"attempt"`
+	Error      string          `json:"error"`
+	MtsAdd     int64           `json:"mtsAdd"`
+}
@@ -16,16 +16,18 @@ import (
 type Server struct {
 	port           int
 	allowedOrigins []string
+	readyLagMax    uint64
 	logger         *log.Logger
 	server         *http.Server
 }
 
-func NewServer(port int, allowedOrigins []string) *Server {
+func NewServer(port int, allowedOrigins []string, readyLagMax uint64) *Server {
 	logger := log.New(os.Stdout, "[API] ", log.LstdFlags)
 
 	return &Server{
 		port:           port,
 		allowedOrigins: allowedOrigins,
+		readyLagMax:    readyLagMax,
 		logger:         logger,
 	}
 }
@@ -37,6 +39,7 @@ func (s *Server) Start(stop chan struct{}) error {
 	cors := middleware.NewCorsMiddleware(s.allowedOrigins)
 	rateLimiter := middleware.NewRateLimiter(100, time.Minute)
 	logger := middleware.NewLogMiddleware(s.logger)
+	metrics := middleware.NewMetricsMiddleware(mux)
 
 	// Debug logging
 	s.logger.Printf("Starting route registration...")
@@ -46,25 +49,49 @@ func (s *Server) Start(stop chan struct{}) error {
 	mux.HandleFunc("/api/v1/token/balances", handlers.GetTokenBalances)
 	mux.HandleFunc("/api/v1/token/info", handlers.GetTokenInfo)
 	mux.HandleFunc("/api/v1/address/balances", handlers.GetAddressBalances)
+	mux.HandleFunc("/api/v1/address/operations", handlers.GetAddressOperations)
 	mux.HandleFunc("/api/v1/token/holders", handlers.GetTokenHolders)
+	mux.HandleFunc("/api/v1/token/operations", handlers.GetTokenOperations)
 	mux.HandleFunc("/api/v1/tokens", handlers.GetAllTokens)
 	mux.HandleFunc("/api/v1/holders/top", handlers.GetTopHolders)
 	mux.HandleFunc("/api/v1/transaction", handlers.GetTransaction)
 	mux.HandleFunc("/api/v1/transactions", handlers.GetAllTransactions)
 	mux.HandleFunc("/api/v1/addresses/balances", handlers.GetAllAddressesBalances)
+	mux.HandleFunc("/api/v1/market/orders", handlers.GetMarketOrders)
+	mux.HandleFunc("/api/v1/market/trades", handlers.GetMarketTrades)
+	mux.HandleFunc("/api/v1/market/candles", handlers.GetMarketCandles)
+	mux.HandleFunc("/api/v1/market/volume", handlers.GetMarketVolume)
+	mux.HandleFunc("/api/v1/krc721/collections", handlers.GetCollections)
+	mux.HandleFunc("/api/v1/krc721/collection", handlers.GetCollectionInfo)
+	mux.HandleFunc("/api/v1/krc721/token", handlers.GetNftOwner)
+	mux.HandleFunc("/api/v1/krc721/address", handlers.GetAddressNfts)
+	mux.HandleFunc("/api/v1/events", handlers.StreamEvents)
+	mux.HandleFunc("/api/v1/webhooks", handlers.Webhooks)
+	mux.HandleFunc("/api/v1/webhooks/deadletters", handlers.WebhookDeadLetters)
+	mux.HandleFunc("/api/v1/checkpoint", handlers.GetCheckpoint)
+	mux.HandleFunc("/api/v1/checkpoint/consensus", handlers.GetCheckpointConsensus)
 
 	s.logger.Printf("All routes registered")
 
 	// Apply middleware chain
-	handler := logger.Handler(
-		rateLimiter.Handler(
-			cors.Handler(mux),
+	handler := metrics.Handler(
+		logger.Handler(
+			rateLimiter.Handler(
+				cors.Handler(mux),
+			),
 		),
 	)
 
+	// The scrape and the probes of the monitoring are served outside the rate limit, the rest of the routes behind it
+	root := http.NewServeMux()
+	root.HandleFunc("/metrics", handlers.GetMetrics)
+	root.HandleFunc("/healthz", handlers.Healthz)
+	root.HandleFunc("/readyz", handlers.Readyz(s.readyLagMax))
+	root.Handle("/", handler)
+
 	s.server = &http.Server{
 		Addr:    fmt.Sprintf(":%d", s.port),
-		Handler: handler,
+		Handler: root,
 	}
 
 	// Graceful shutdown goroutine
@@ -0,0 +1,26 @@
+// //////////////////////////////
+package main
+
+import (
+	"log"
+	"sort"
+	"strings"
+)
+
+// //////////////////////////////
+// Commands registered, run as "kpexecutor <command> [flags]".
+var command_Registered = map[string]func([]string){}
+
+// //////////////////////////////
+func runCommand(name string, args []string) {
+	fRun := command_Registered[name]
+	if fRun == nil {
+		nameList := make([]string, 0, len(command_Registered))
+		for nameRegistered := range command_Registered {
+			nameList = append(nameList, nameRegistered)
+		}
+		sort.Strings(nameList)
+		log.Fatalln("main fatal: unknown command \""+name+"\", available:", strings.Join(nameList, " "))
+	}
+	fRun(args)
+}
@@ -0,0 +1,72 @@
+// //////////////////////////////
+package main
+
+import (
+	"flag"
+	"fmt"
+	"kasplex-executor/config"
+	"kasplex-executor/storage"
+	"log"
+)
+
+// //////////////////////////////
+func init() {
+	command_Registered["bootstrap"] = runBootstrap
+}
+
+// //////////////////////////////
+// Load the snapshot file into the empty local db, the executor resumes the scan from the daaScore of the snapshot.
+// The state data and the runtime checkpoint are also written in the cluster db, the executor must be stopped.
+func runBootstrap(args []string) {
+	flagSet := flag.NewFlagSet("bootstrap", flag.ExitOnError)
+	path := flagSet.String("file", "", "snapshot file written by the executor")
+	verify := flagSet.Bool("verify", false, "check the hash of the snapshot file only")
+	flagSet.Parse(args)
+	if *path == "" {
+		log.Fatalln("main.runBootstrap fatal: -file required")
+	}
+	if *verify {
+		header, hash, err := storage.VerifySnapshot(*path)
+		if err != nil {
+			log.Fatalln("main.runBootstrap fatal:", err.Error())
+		}
+		fmt.Printf("snapshot passed, daaScore %d, opScore %d, checkpoint %s, hash %s\n", header.DaaScore, header.OpScore, header.Checkpoint, hash)
+		return
+	}
+
+	unlock := lockStartup()
+	defer unlock()
+	var cfg config.Config
+	config.Load(&cfg)
+	setLogLevel(cfg.Debug)
+	storage.Init(cfg.Cassandra, cfg.Rocksdb)
+
+	// Refuse if the local db is in use.
+	vspcList, err := storage.GetRuntimeVspcLast()
+	if err != nil {
+		log.Fatalln("main.runBootstrap fatal:", err.Error())
+	}
+	if len(vspcList) > 0 {
+		log.Fatalln("main.runBootstrap fatal: local db not empty, last daaScore", vspcList[len(vspcList)-1].DaaScore)
+	}
+
+	// Load the local db, then rewrite the cluster db from it.
+	header, hash, err := storage.LoadSnapshot(*path, cfg.Testnet)
+	if err != nil {
+		log.Fatalln("main.runBootstrap fatal:", err.Error())
+	}
+	stateMap, err := storage.GetStateMapRocksAll()
+	if err != nil {
+		log.Fatalln("main.runBootstrap fatal:", err.Error())
+	}
+	_, err = storage.SaveStateBatchCassa(stateMap)
+	if err != nil {
+		log.Fatalln("main.runBootstrap fatal:", err.Error())
+	}
+	err = storage.SetRuntimeCheckpoint(header.Checkpoint, header.OpScore, header.DaaScore)
+	if err != nil {
+		log.Fatalln("main.runBootstrap fatal:", err.Error())
+	}
+	storage.SetRuntimeSynced(false, header.OpScore, header.DaaScore)
+	fmt.Printf("bootstrap loaded, daaScore %d, opScore %d, checkpoint %s, hash %s\n", header.DaaScore, header.OpScore, header.Checkpoint, hash)
+}
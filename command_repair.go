@@ -0,0 +1,105 @@
+// //////////////////////////////
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"kasplex-executor/config"
+	"kasplex-executor/storage"
+	"log"
+	"os"
+	"sort"
+	"strings"
+)
+
+// //////////////////////////////
+func init() {
+	command_Registered["repair"] = runRepair
+}
+
+// //////////////////////////////
+// Diff the state data in the local db and the cluster db key by key, and rewrite one side from the other if specified.
+// The executor must be stopped, the mismatch is reported with exit code 1 if not fixed.
+func runRepair(args []string) {
+	flagSet := flag.NewFlagSet("repair", flag.ExitOnError)
+	fix := flagSet.String("fix", "", "side to rewrite: \"cassa\" from the local db, \"rocks\" from the cluster db, report only if empty")
+	limit := flagSet.Int("limit", 100, "max mismatch lines printed, all if 0")
+	flagSet.Parse(args)
+	if *fix != "" && *fix != "cassa" && *fix != "rocks" {
+		log.Fatalln("main.runRepair fatal: invalid fix side", *fix)
+	}
+
+	unlock := lockStartup()
+	defer unlock()
+	var cfg config.Config
+	config.Load(&cfg)
+	setLogLevel(cfg.Debug)
+	storage.Init(cfg.Cassandra, cfg.Rocksdb)
+
+	// Diff the state data.
+	stateMapRocks, err := storage.GetStateMapRocksAll()
+	if err != nil {
+		log.Fatalln("main.runRepair fatal:", err.Error())
+	}
+	stateMapCassa, err := storage.GetStateMapCassaAll()
+	if err != nil {
+		log.Fatalln("main.runRepair fatal:", err.Error())
+	}
+	diffRocks, diffCassa := storage.DiffStateMap(stateMapRocks, stateMapCassa)
+	countList := []string{}
+	for _, kind := range storage.GetStateKindList() {
+		countList = append(countList, fmt.Sprintf("%s %d/%d/%d", kind.Name(), kind.Len(stateMapRocks), kind.Len(stateMapCassa), kind.Len(diffRocks)))
+	}
+	fmt.Printf("repair %s (rocks/cassa/mismatch)\n", strings.Join(countList, ", "))
+
+	// Print the mismatch.
+	lineList := []string{}
+	for _, kind := range storage.GetStateKindList() {
+		valueMapRocks := kind.ValueMap(diffRocks)
+		valueMapCassa := kind.ValueMap(diffCassa)
+		for key := range valueMapRocks {
+			lineList = append(lineList, makeRepairLine(kind.KeyPrefix()+key, valueMapRocks[key], valueMapCassa[key]))
+		}
+	}
+	sort.Strings(lineList)
+	for i, line := range lineList {
+		if *limit > 0 && i >= *limit {
+			fmt.Printf("... %d more\n", len(lineList)-i)
+			break
+		}
+		fmt.Println(line)
+	}
+	if len(lineList) == 0 {
+		fmt.Println("repair passed, no mismatch")
+		return
+	}
+
+	// Rewrite the mismatch.
+	if *fix == "cassa" {
+		_, err = storage.SaveStateBatchCassa(diffRocks)
+	} else if *fix == "rocks" {
+		err = storage.SaveStateBatchRocks(diffCassa)
+	} else {
+		unlock()
+		os.Exit(1)
+	}
+	if err != nil {
+		log.Fatalln("main.runRepair fatal:", err.Error())
+	}
+	fmt.Printf("repair fixed %d mismatch in %s\n", len(lineList), *fix)
+}
+
+// //////////////////////////////
+func makeRepairLine(key string, stRocks interface{}, stCassa interface{}) string {
+	return key + "\n  rocks: " + makeRepairValue(stRocks) + "\n  cassa: " + makeRepairValue(stCassa)
+}
+
+// //////////////////////////////
+func makeRepairValue(st interface{}) string {
+	valueJson, _ := json.Marshal(st)
+	if string(valueJson) == "null" {
+		return "missing"
+	}
+	return string(valueJson)
+}
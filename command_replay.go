@@ -0,0 +1,96 @@
+// //////////////////////////////
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"kasplex-executor/config"
+	"kasplex-executor/explorer"
+	"kasplex-executor/storage"
+	"log"
+	"os"
+	"sync"
+)
+
+// //////////////////////////////
+type replayResultType struct {
+	DaaScore   uint64                     `json:"daaScore"`
+	OpScore    uint64                     `json:"opScore"`
+	Checkpoint string                     `json:"checkpoint"`
+	State      map[string]json.RawMessage `json:"state"`
+}
+
+// //////////////////////////////
+func init() {
+	command_Registered["replay"] = runReplay
+}
+
+// //////////////////////////////
+// Replay the recorded fixture data into a temporary local db, without the cluster db.
+// The final checkpoint and the state dump are written as json, for the deterministic test.
+func runReplay(args []string) {
+	flagSet := flag.NewFlagSet("replay", flag.ExitOnError)
+	dir := flagSet.String("dir", "./fixture", "fixture directory, with vspc.json and transaction/<txid>.json")
+	out := flagSet.String("out", "", "result file path, stdout if empty")
+	testnet := flagSet.Bool("testnet", false, "use the testnet rules, the daaScore range is the fixture range")
+	debug := flagSet.Int("debug", 0, "log level: 1:Warn 2:Info 3:Debug")
+	flagSet.Parse(args)
+	setLogLevel(*debug)
+
+	// Use the temporary local db only.
+	pathRocks, err := os.MkdirTemp("", "kpexecutor-replay-")
+	if err != nil {
+		log.Fatalln("main.runReplay fatal:", err.Error())
+	}
+	defer os.RemoveAll(pathRocks)
+	result, err := replayFixture(*dir, pathRocks, *testnet)
+	if err != nil {
+		log.Fatalln("main.runReplay fatal:", err.Error())
+	}
+
+	// Write the result.
+	resultJson, _ := json.MarshalIndent(result, "", "  ")
+	resultJson = append(resultJson, '\n')
+	if *out == "" {
+		os.Stdout.Write(resultJson)
+		return
+	}
+	err = os.WriteFile(*out, resultJson, 0644)
+	if err != nil {
+		log.Fatalln("main.runReplay fatal:", err.Error())
+	}
+}
+
+// //////////////////////////////
+// Replay the fixture directory into the local db of the path, the testnet rules use the fixture range as the daaScore range,
+// the first vspc is the start and not executed. The mainnet rules use the hardcoded daaScore range.
+func replayFixture(dir string, pathRocks string, testnet bool) (*replayResultType, error) {
+	source, err := storage.LoadNodeSourceFixture(dir)
+	if err != nil {
+		return nil, err
+	}
+	daaScoreFirst, daaScoreEnd := source.GetDaaScoreRange()
+	storage.InitRocks(config.RocksConfig{Path: pathRocks})
+
+	// Scan all the fixture vspc without hysteresis.
+	cfgStartup := config.StartupConfig{}
+	if testnet {
+		cfgStartup.DaaScoreRange = [][2]uint64{{daaScoreFirst, daaScoreEnd}}
+	}
+	explorer.Init(context.Background(), &sync.WaitGroup{}, cfgStartup, source, testnet)
+	rollback, err := explorer.Replay(daaScoreEnd)
+	if err != nil {
+		return nil, err
+	}
+	result := &replayResultType{
+		DaaScore:   rollback.DaaScoreEnd,
+		OpScore:    rollback.OpScoreLast,
+		Checkpoint: rollback.CheckpointAfter,
+	}
+	result.State, err = storage.GetStateRocksAll()
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,37 @@
+// //////////////////////////////
+package main
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// //////////////////////////////
+// The fixture in testdata/replay: a deploy with the premine at 1001, a mint and a mint with the fee not enough at 1002,
+// and a transaction not recorded at 1003. The input transactions of the fee are recorded.
+func TestReplayFixture(t *testing.T) {
+	result, err := replayFixture("testdata/replay", t.TempDir(), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.DaaScore != 1003 || result.OpScore != 10020001 {
+		t.Fatalf("daaScore/opScore = %d/%d, want 1003/10020001", result.DaaScore, result.OpScore)
+	}
+	if result.Checkpoint != "f319966a383ca32ed4ce81364797916ff2df6aa1f3d7d0a44c4975da9e53b300" {
+		t.Fatalf("checkpoint = %s", result.Checkpoint)
+	}
+	stToken := struct {
+		Minted string `json:"minted"`
+		OpMod  uint64 `json:"opmod"`
+	}{}
+	err = json.Unmarshal(result.State["sttoken_TFIX"], &stToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stToken.Minted != "150000000000" || stToken.OpMod != 10020000 {
+		t.Fatalf("token minted/opmod = %s/%d, want 150000000000/10020000", stToken.Minted, stToken.OpMod)
+	}
+	if len(result.State) != 3 {
+		t.Fatalf("len state = %d, want 3", len(result.State))
+	}
+}
@@ -0,0 +1,40 @@
+// //////////////////////////////
+package main
+
+import (
+	"flag"
+	"fmt"
+	"kasplex-executor/config"
+	"kasplex-executor/explorer"
+	"kasplex-executor/storage"
+	"log"
+)
+
+// //////////////////////////////
+func init() {
+	command_Registered["rewind"] = runRewind
+}
+
+// //////////////////////////////
+// Rewind the state to the daaScore by the persisted rollback records, the executor must be stopped.
+func runRewind(args []string) {
+	flagSet := flag.NewFlagSet("rewind", flag.ExitOnError)
+	daaScoreTo := flagSet.Uint64("to-daascore", 0, "target daaScore, rewind to the last batch end at or before it")
+	flagSet.Parse(args)
+	if *daaScoreTo == 0 {
+		log.Fatalln("main.runRewind fatal: --to-daascore required")
+	}
+
+	unlock := lockStartup()
+	defer unlock()
+	var cfg config.Config
+	config.Load(&cfg)
+	setLogLevel(cfg.Debug)
+	storage.Init(cfg.Cassandra, cfg.Rocksdb)
+
+	rollback, nRewind, err := explorer.Rewind(*daaScoreTo)
+	if err != nil {
+		log.Fatalln("main.runRewind fatal:", err.Error())
+	}
+	fmt.Printf("rewind %d batches, daaScore %d, opScore %d, checkpoint %s\n", nRewind, rollback.DaaScoreEnd, rollback.OpScoreLast, rollback.CheckpointAfter)
+}
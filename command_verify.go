@@ -0,0 +1,132 @@
+// //////////////////////////////
+package main
+
+import (
+	"flag"
+	"fmt"
+	"kasplex-executor/config"
+	"kasplex-executor/explorer"
+	"kasplex-executor/operation"
+	"kasplex-executor/storage"
+	"log"
+	"log/slog"
+	"os"
+)
+
+// //////////////////////////////
+const lenRangeVerify = 1000
+
+// //////////////////////////////
+func init() {
+	command_Registered["verify"] = runVerify
+}
+
+// //////////////////////////////
+// Recompute the checkpoint of each accepted op in oplist by opScore order, same as operation.ExecuteBatch.
+// Only the cluster db is used, the first mismatch is reported with exit code 1.
+func runVerify(args []string) {
+	flagSet := flag.NewFlagSet("verify", flag.ExitOnError)
+	opScoreFrom := flagSet.Uint64("from", 0, "start opScore, the first available daaScore if 0")
+	opScoreTo := flagSet.Uint64("to", 0, "end opScore, the last checkpoint if 0")
+	checkpointFrom := flagSet.String("checkpoint", "", "checkpoint before the start opScore, looked up in oplist if empty")
+	flagSet.Parse(args)
+
+	var cfg config.Config
+	config.Load(&cfg)
+	setLogLevel(cfg.Debug)
+	storage.InitCassa(cfg.Cassandra)
+
+	// Get the opScore range and the checkpoint before.
+	daaScoreRange := explorer.GetDaaScoreRange(cfg.Startup, cfg.Testnet)
+	opScoreFirst := daaScoreRange[0][0] * 10000
+	if *opScoreFrom < opScoreFirst {
+		*opScoreFrom = opScoreFirst
+	}
+	if *opScoreTo == 0 {
+		checkpoint, err := storage.GetCheckpointLast()
+		if err != nil {
+			log.Fatalln("main.runVerify fatal:", err.Error())
+		}
+		if checkpoint == nil {
+			log.Fatalln("main.runVerify fatal: checkpoint not found")
+		}
+		*opScoreTo = checkpoint.OpScore
+	}
+	checkpointLast := *checkpointFrom
+	if checkpointLast == "" && *opScoreFrom > opScoreFirst {
+		checkpoint, err := storage.GetCheckpointByOpScore(*opScoreFrom - 1)
+		if err != nil {
+			log.Fatalln("main.runVerify fatal:", err.Error())
+		}
+		if checkpoint == nil {
+			slog.Warn("main.runVerify checkpoint before not found, start with empty.", "opScore", *opScoreFrom)
+		} else {
+			checkpointLast = checkpoint.Checkpoint
+		}
+	}
+	fmt.Printf("verify opScore %d - %d\n", *opScoreFrom, *opScoreTo)
+
+	// Walk the oplist by opRange, skip the unavailable daaScore range.
+	nOp := 0
+	nAccept := 0
+	for _, dRange := range daaScoreRange {
+		opScoreStart := dRange[0] * 10000
+		if opScoreStart < *opScoreFrom {
+			opScoreStart = *opScoreFrom
+		}
+		opScoreEnd := *opScoreTo
+		if dRange[1] < *opScoreTo/10000 {
+			opScoreEnd = dRange[1]*10000 + 9999
+		}
+		if opScoreStart > opScoreEnd {
+			continue
+		}
+		for opRange := opScoreStart / storage.OpRangeBy; opRange <= opScoreEnd/storage.OpRangeBy; opRange += lenRangeVerify {
+			opListRange, _, err := storage.GetOpListByRange(opRange, lenRangeVerify)
+			if err != nil {
+				log.Fatalln("main.runVerify fatal:", err.Error())
+			}
+			opList := make([]storage.DataOpListType, 0, len(opListRange))
+			for _, opData := range opListRange {
+				if opData.OpScore < opScoreStart || opData.OpScore > opScoreEnd {
+					continue
+				}
+				opList = append(opList, opData)
+			}
+			checkpointLast, nOp, nAccept = verifyOpList(opList, checkpointLast, nOp, nAccept, cfg.Testnet)
+			slog.Info("main.runVerify", "opRange", opRange, "lenOperation", nOp, "lenAccepted", nAccept)
+		}
+	}
+	fmt.Printf("verify passed, %d operations, %d accepted, checkpoint %s\n", nOp, nAccept, checkpointLast)
+}
+
+// //////////////////////////////
+// Verify the op list in order, exit if mismatch.
+func verifyOpList(opList []storage.DataOpListType, checkpointLast string, nOp int, nAccept int, testnet bool) (string, int, int) {
+	_, err := storage.GetOpDataStAfterList(opList)
+	if err != nil {
+		log.Fatalln("main.runVerify fatal:", err.Error())
+	}
+	for _, opData := range opList {
+		nOp++
+		if testnet && (opData.OpScore/10000)%100000 <= 9 {
+			checkpointLast = ""
+		}
+		if opData.State.OpAccept != 1 {
+			continue
+		}
+		nAccept++
+		checkpoint := operation.MakeCheckpoint(checkpointLast, opData.OpScore, opData.TxId, opData.State.BlockAccept, opData.Script.P, opData.Script.Op, opData.StAfter)
+		if checkpoint != opData.State.Checkpoint || checkpoint != opData.StateOpData.Checkpoint {
+			fmt.Printf("verify mismatch at opScore %d\n", opData.OpScore)
+			fmt.Printf("  txid:       %s\n", opData.TxId)
+			fmt.Printf("  expected:   %s\n", checkpoint)
+			fmt.Printf("  oplist:     %s\n", opData.State.Checkpoint)
+			fmt.Printf("  opdata:     %s\n", opData.StateOpData.Checkpoint)
+			fmt.Printf("  checkpoint before: %s\n", checkpointLast)
+			os.Exit(1)
+		}
+		checkpointLast = checkpoint
+	}
+	return checkpointLast, nOp, nAccept
+}
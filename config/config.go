@@ -9,9 +9,13 @@ import (
 
 // //////////////////////////////
 type StartupConfig struct {
-	Hysteresis    int         `json:"hysteresis"`
-	DaaScoreRange [][2]uint64 `json:"daaScoreRange"`
-	TickReserved  []string    `json:"tickReserved"`
+	Hysteresis        int         `json:"hysteresis"`
+	DaaScoreRange     [][2]uint64 `json:"daaScoreRange"`
+	TickReserved      []string    `json:"tickReserved"`
+	RollbackRetention uint64      `json:"rollbackRetention"`
+	SnapshotDir       string      `json:"snapshotDir"`
+	SnapshotInterval  uint64      `json:"snapshotInterval"`
+	SnapshotKeep      int         `json:"snapshotKeep"`
 }
 type CassaConfig struct {
 	Host  string `json:"host"`
@@ -24,16 +28,33 @@ type CassaConfig struct {
 type RocksConfig struct {
 	Path string `json:"path"`
 }
+type KaspadConfig struct {
+	Enabled bool   `json:"enabled"`
+	Host    string `json:"host"`
+	Start   string `json:"start"`
+}
 type ApiConfig struct {
 	Enabled        bool     `json:"enabled"`
 	Port           int      `json:"port"`
 	AllowedOrigins []string `json:"allowedOrigins"`
+	Peers          []string `json:"peers"`
+	PeerInterval   int      `json:"peerInterval"`
+	ReadyLagMax    uint64   `json:"readyLagMax"`
+}
+type WebhookConfig struct {
+	Enabled    bool   `json:"enabled"`
+	Token      string `json:"token"`
+	AttemptMax int    `json:"attemptMax"`
+	BackoffMax int    `json:"backoffMax"`
+	Timeout    int    `json:"timeout"`
 }
 type Config struct {
 	Startup   StartupConfig `json:"startup"`
 	Cassandra CassaConfig   `json:"cassandra"`
 	Rocksdb   RocksConfig   `json:"rocksdb"`
+	Kaspad    KaspadConfig  `json:"kaspad"`
 	Api       ApiConfig     `json:"api"`
+	Webhook   WebhookConfig `json:"webhook"`
 	Debug     int           `json:"debug"`
 	Testnet   bool          `json:"testnet"`
 }
@@ -0,0 +1,295 @@
+
+////////////////////////////////
+package events
+
+import (
+    "sync"
+    "context"
+    "errors"
+    "strings"
+    "math/big"
+    "log/slog"
+    "encoding/json"
+    "kasplex-executor/storage"
+)
+
+////////////////////////////////
+const lenSubscriberMax = 1000
+const lenQueueMax = 1000
+
+////////////////////////////////
+const EventTypeOp = "op"
+const EventTypeRetract = "retract"
+
+////////////////////////////////
+// The balance affected by the op, the delta is the change of balance+locked.
+type DataEventBalanceType struct {
+    Address string `json:"address"`
+    Tick string `json:"tick"`
+    Balance string `json:"balance"`
+    Delta string `json:"delta"`
+}
+
+////////////////////////////////
+// The op accepted after the batch committed, or retracted by the rollback.
+type DataEventType struct {
+    Type string `json:"type"`
+    DaaScore uint64 `json:"daaScore"`
+    OpScore uint64 `json:"opScore"`
+    TxId string `json:"txId"`
+    BlockAccept string `json:"blockAccept,omitempty"`
+    Op *storage.DataScriptType `json:"op"`
+    OpEvent string `json:"opEvent,omitempty"`
+    Checkpoint string `json:"checkpoint"`
+    BalanceList []DataEventBalanceType `json:"balanceList,omitempty"`
+    MtsAdd int64 `json:"mtsAdd,omitempty"`
+    Json []byte `json:"-"`
+}
+
+////////////////////////////////
+// The filter of the subscriber, each list matches any if empty.
+type FilterType struct {
+    TickList []string
+    AddressList []string
+    OpList []string
+}
+
+////////////////////////////////
+type Subscriber struct {
+    filter FilterType
+    chEvent chan *DataEventType
+    closed bool
+    ctxBlocking context.Context  // never dropped if set, the publish waits for the queue until done.
+}
+
+////////////////////////////////
+type hubType struct {
+    mutex sync.RWMutex
+    subscriberMap map[*Subscriber]bool
+}
+var hub = hubType{
+    subscriberMap: map[*Subscriber]bool{},
+}
+
+////////////////////////////////
+// Subscribe the events matching the filter, the channel is closed if the queue overflows.
+func Subscribe(filter FilterType) (*Subscriber, error) {
+    hub.mutex.Lock()
+    defer hub.mutex.Unlock()
+    if len(hub.subscriberMap) >= lenSubscriberMax {
+        return nil, errors.New("too many subscribers")
+    }
+    sub := &Subscriber{
+        filter: filter,
+        chEvent: make(chan *DataEventType, lenQueueMax),
+    }
+    hub.subscriberMap[sub] = true
+    return sub, nil
+}
+
+////////////////////////////////
+// Subscribe the events matching the filter without the drop, the publish waits for the queue until the context done.
+// The subscriber must not block in receiving, it's used for the delivery that can't lose any event.
+func SubscribeBlocking(ctx context.Context, filter FilterType) (*Subscriber, error) {
+    sub, err := Subscribe(filter)
+    if err != nil {
+        return nil, err
+    }
+    sub.ctxBlocking = ctx
+    return sub, nil
+}
+
+////////////////////////////////
+func (sub *Subscriber) Unsubscribe() {
+    hub.mutex.Lock()
+    defer hub.mutex.Unlock()
+    delete(hub.subscriberMap, sub)
+    if !sub.closed {
+        sub.closed = true
+        close(sub.chEvent)
+    }
+}
+
+////////////////////////////////
+func (sub *Subscriber) C() (<-chan *DataEventType) {
+    return sub.chEvent
+}
+
+////////////////////////////////
+// Check if any subscriber, the events are not built if none.
+func Subscribed() (bool) {
+    hub.mutex.RLock()
+    defer hub.mutex.RUnlock()
+    return len(hub.subscriberMap) > 0
+}
+
+////////////////////////////////
+// Deliver the events to the subscribers matched, the slow subscriber is dropped unless blocking.
+func publish(eventList []*DataEventType) {
+    if len(eventList) <= 0 {
+        return
+    }
+    for _, event := range eventList {
+        event.Json, _ = json.Marshal(event)
+    }
+    hub.mutex.Lock()
+    defer hub.mutex.Unlock()
+    for sub := range hub.subscriberMap {
+        for _, event := range eventList {
+            if !sub.filter.Match(event) {
+                continue
+            }
+            if sub.ctxBlocking != nil {
+                select {
+                    case sub.chEvent <- event:
+                    case <-sub.ctxBlocking.Done():
+                }
+                continue
+            }
+            select {
+                case sub.chEvent <- event:
+                default:
+                    slog.Warn("events.publish queue full, subscriber dropped.")
+                    delete(hub.subscriberMap, sub)
+                    sub.closed = true
+                    close(sub.chEvent)
+            }
+            if sub.closed {
+                break
+            }
+        }
+    }
+}
+
+////////////////////////////////
+// Check if the event matches the filter, the address matches the from, to or any balance affected.
+func (filter *FilterType) Match(event *DataEventType) (bool) {
+    if (len(filter.TickList) > 0 && !matchList(filter.TickList, strings.ToUpper(event.Op.Tick))) {
+        return false
+    }
+    if (len(filter.OpList) > 0 && !matchList(filter.OpList, event.Op.Op)) {
+        return false
+    }
+    if len(filter.AddressList) > 0 {
+        if (matchList(filter.AddressList, event.Op.From) || matchList(filter.AddressList, event.Op.To)) {
+            return true
+        }
+        for _, balance := range event.BalanceList {
+            if matchList(filter.AddressList, balance.Address) {
+                return true
+            }
+        }
+        return false
+    }
+    return true
+}
+
+////////////////////////////////
+func matchList(list []string, value string) (bool) {
+    if value == "" {
+        return false
+    }
+    for _, item := range list {
+        if item == value {
+            return true
+        }
+    }
+    return false
+}
+
+////////////////////////////////
+// Publish the ops accepted in the batch committed.
+func PublishOpList(opDataList []storage.DataOperationType) {
+    if !Subscribed() {
+        return
+    }
+    eventList := []*DataEventType{}
+    for i := range opDataList {
+        opData := &opDataList[i]
+        if opData.OpAccept != 1 {
+            continue
+        }
+        eventList = append(eventList, &DataEventType{
+            Type: EventTypeOp,
+            DaaScore: opData.DaaScore,
+            OpScore: opData.OpScore,
+            TxId: opData.TxId,
+            BlockAccept: opData.BlockAccept,
+            Op: opData.OpScript[0],
+            OpEvent: opData.OpEvent,
+            Checkpoint: opData.Checkpoint,
+            BalanceList: getBalanceList(opData),
+            MtsAdd: opData.MtsAdd,
+        })
+    }
+    publish(eventList)
+}
+
+////////////////////////////////
+// Publish the retraction of the ops accepted in the batch rolled back, the checkpoint is the one restored.
+func PublishRollback(opList []storage.DataOpListType, checkpointBefore string) {
+    eventList := []*DataEventType{}
+    for i := len(opList)-1; i >= 0; i -- {
+        if opList[i].State.OpAccept != 1 {
+            continue
+        }
+        script := opList[i].Script
+        eventList = append(eventList, &DataEventType{
+            Type: EventTypeRetract,
+            DaaScore: opList[i].OpScore / 10000,
+            OpScore: opList[i].OpScore,
+            TxId: opList[i].TxId,
+            BlockAccept: opList[i].State.BlockAccept,
+            Op: &script,
+            OpEvent: opList[i].State.OpEvent,
+            Checkpoint: checkpointBefore,
+        })
+    }
+    publish(eventList)
+}
+
+////////////////////////////////
+// Get the balances affected with the delta, from the address affected and the state before.
+func getBalanceList(opData *storage.DataOperationType) ([]DataEventBalanceType) {
+    if (opData.SsInfo == nil || len(opData.SsInfo.AddressAffc) <= 0) {
+        return nil
+    }
+    beforeMap := map[string]*big.Int{}
+    for _, line := range opData.StBefore {
+        // The nft owned before is the balance 1 of the owner, keyed by the tick and the token id.
+        if strings.HasPrefix(line, storage.KeyPrefixStateNft) {
+            list := strings.Split(strings.TrimPrefix(line, storage.KeyPrefixStateNft), ",")
+            if len(list) >= 2 {
+                beforeMap[list[1]+"_"+list[0]] = big.NewInt(1)
+            }
+            continue
+        }
+        key, stBalance := storage.ParseStLineBalance(line)
+        if (key == "" || beforeMap[key] != nil) {
+            continue
+        }
+        beforeMap[key] = new(big.Int)
+        if stBalance != nil {
+            beforeMap[key].Add(storage.ParseAmount(stBalance.Balance), storage.ParseAmount(stBalance.Locked))
+        }
+    }
+    balanceList := []DataEventBalanceType{}
+    for _, affc := range opData.SsInfo.AddressAffc {
+        list := strings.SplitN(affc, "=", 2)
+        key := strings.SplitN(list[0], "_", 2)
+        if (len(list) < 2 || len(key) < 2) {
+            continue
+        }
+        delta := storage.ParseAmount(list[1])
+        if beforeMap[list[0]] != nil {
+            delta.Sub(delta, beforeMap[list[0]])
+        }
+        balanceList = append(balanceList, DataEventBalanceType{
+            Address: key[0],
+            Tick: key[1],
+            Balance: list[1],
+            Delta: delta.String(),
+        })
+    }
+    return balanceList
+}
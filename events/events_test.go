@@ -0,0 +1,121 @@
+
+////////////////////////////////
+package events
+
+import (
+    "time"
+    "context"
+    "testing"
+    "kasplex-executor/storage"
+)
+
+////////////////////////////////
+// The subscriber is dropped if the queue is full, the blocking subscriber waits and receives all the events.
+func TestPublishOverflow(t *testing.T) {
+    ctx, cancel := context.WithCancel(context.Background())
+    defer cancel()
+    sub, err := Subscribe(FilterType{})
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer sub.Unsubscribe()
+    subBlocking, err := SubscribeBlocking(ctx, FilterType{})
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer subBlocking.Unsubscribe()
+    lenEvent := lenQueueMax + 10
+    eventList := make([]*DataEventType, 0, lenEvent)
+    for i := 0; i < lenEvent; i ++ {
+        eventList = append(eventList, &DataEventType{Type: EventTypeOp, OpScore: uint64(i), Op: &storage.DataScriptType{}})
+    }
+    done := make(chan struct{})
+    go func() {
+        publish(eventList)
+        close(done)
+    }()
+    for i := 0; i < lenEvent; i ++ {
+        select {
+            case event := <-subBlocking.C():
+                if event.OpScore != uint64(i) {
+                    t.Fatalf("blocking event %d opScore = %d", i, event.OpScore)
+                }
+            case <-time.After(5*time.Second):
+                t.Fatalf("blocking event %d not received", i)
+        }
+    }
+    <-done
+    n := 0
+    for range sub.C() {
+        n ++
+    }
+    if n != lenQueueMax {
+        t.Fatalf("dropped subscriber received %d, want %d", n, lenQueueMax)
+    }
+}
+
+////////////////////////////////
+// The publish to the blocking subscriber returns after the context done.
+func TestPublishBlockingDone(t *testing.T) {
+    ctx, cancel := context.WithCancel(context.Background())
+    sub, err := SubscribeBlocking(ctx, FilterType{})
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer sub.Unsubscribe()
+    eventList := make([]*DataEventType, 0, lenQueueMax+1)
+    for i := 0; i <= lenQueueMax; i ++ {
+        eventList = append(eventList, &DataEventType{Type: EventTypeOp, OpScore: uint64(i), Op: &storage.DataScriptType{}})
+    }
+    done := make(chan struct{})
+    go func() {
+        publish(eventList)
+        close(done)
+    }()
+    cancel()
+    select {
+        case <-done:
+        case <-time.After(5*time.Second):
+            t.Fatal("publish blocked after the context done")
+    }
+}
+
+////////////////////////////////
+func TestFilterMatch(t *testing.T) {
+    event := &DataEventType{
+        Type: EventTypeOp,
+        Op: &storage.DataScriptType{P: "KRC-20", Op: "transfer", Tick: "abcd", From: "kaspa:qa", To: "kaspa:qb"},
+        BalanceList: []DataEventBalanceType{{Address: "kaspa:qa", Tick: "ABCD"}, {Address: "kaspa:qc", Tick: "ABCD"}},
+    }
+    eventNoTo := &DataEventType{
+        Type: EventTypeRetract,
+        Op: &storage.DataScriptType{P: "KRC-20", Op: "mint", Tick: "EFGH", From: "kaspa:qa"},
+    }
+    testList := []struct{
+        name string
+        filter FilterType
+        event *DataEventType
+        want bool
+    }{
+        {"empty", FilterType{}, event, true},
+        {"tick", FilterType{TickList: []string{"XYZW", "ABCD"}}, event, true},
+        {"tick other", FilterType{TickList: []string{"XYZW"}}, event, false},
+        {"op", FilterType{OpList: []string{"mint", "transfer"}}, event, true},
+        {"op other", FilterType{OpList: []string{"mint"}}, event, false},
+        {"address from", FilterType{AddressList: []string{"kaspa:qa"}}, event, true},
+        {"address to", FilterType{AddressList: []string{"kaspa:qb"}}, event, true},
+        {"address balance", FilterType{AddressList: []string{"kaspa:qc"}}, event, true},
+        {"address other", FilterType{AddressList: []string{"kaspa:qd"}}, event, false},
+        {"address empty to", FilterType{AddressList: []string{""}}, eventNoTo, false},
+        {"all", FilterType{TickList: []string{"ABCD"}, OpList: []string{"transfer"}, AddressList: []string{"kaspa:qc"}}, event, true},
+        {"all but op", FilterType{TickList: []string{"ABCD"}, OpList: []string{"mint"}, AddressList: []string{"kaspa:qc"}}, event, false},
+        {"all but address", FilterType{TickList: []string{"ABCD"}, OpList: []string{"transfer"}, AddressList: []string{"kaspa:qd"}}, event, false},
+        {"retract", FilterType{TickList: []string{"EFGH"}, AddressList: []string{"kaspa:qa"}}, eventNoTo, true},
+    }
+    for _, test := range testList {
+        got := test.filter.Match(test.event)
+        if got != test.want {
+            t.Errorf("%s: match = %v, want %v", test.name, got, test.want)
+        }
+    }
+}
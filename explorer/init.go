@@ -4,9 +4,12 @@ package explorer
 
 import (
     "sync"
+    "sync/atomic"
     "context"
     "time"
     "log"
+    "errors"
+    "strconv"
     "log/slog"
     "kasplex-executor/config"
     "kasplex-executor/storage"
@@ -18,14 +21,26 @@ type runtimeType struct {
     ctx context.Context
     wg *sync.WaitGroup
     cfg config.StartupConfig
+    source storage.NodeSource
     vspcList []storage.DataVspcType
     rollbackList []storage.DataRollbackType
     opScoreLast uint64
     synced bool
     testnet bool
+    errScan error
+    reloadRollback bool
+    daaScoreSnapshot uint64
+    snapshotWriting atomic.Bool
 }
 var eRuntime runtimeType
 
+// Default retention of the rollback records, about 1 day.
+const nDaaScoreRetentionRollback = 864000
+
+// Default interval and count kept of the snapshot files.
+const nDaaScoreIntervalSnapshot = 864000
+const nSnapshotKeep = 3
+
 // Available daaScore range.
 var daaScoreRange = [][2]uint64{
     {83441551, 83525600},
@@ -33,21 +48,32 @@ var daaScoreRange = [][2]uint64{
 }
 
 ////////////////////////////////
-func Init(ctx context.Context, wg *sync.WaitGroup, cfg config.StartupConfig, testnet bool) {
+func Init(ctx context.Context, wg *sync.WaitGroup, cfg config.StartupConfig, source storage.NodeSource, testnet bool) {
     slog.Info("explorer.Init start.")
     var err error
     eRuntime.synced = false
     eRuntime.ctx = ctx
     eRuntime.wg = wg
     eRuntime.cfg = cfg
+    eRuntime.source = source
+    if eRuntime.source == nil {
+        eRuntime.source = storage.NodeSourceCassa{}
+    }
     eRuntime.testnet = testnet
     if eRuntime.cfg.Hysteresis < 0 {
         eRuntime.cfg.Hysteresis = 0
     } else if eRuntime.cfg.Hysteresis > 10 {
         eRuntime.cfg.Hysteresis = 10
     }
-    if (!testnet || len(eRuntime.cfg.DaaScoreRange) <= 0) {
-        eRuntime.cfg.DaaScoreRange = daaScoreRange
+    eRuntime.cfg.DaaScoreRange = GetDaaScoreRange(eRuntime.cfg, testnet)
+    if eRuntime.cfg.RollbackRetention <= 0 {
+        eRuntime.cfg.RollbackRetention = nDaaScoreRetentionRollback
+    }
+    if eRuntime.cfg.SnapshotInterval <= 0 {
+        eRuntime.cfg.SnapshotInterval = nDaaScoreIntervalSnapshot
+    }
+    if eRuntime.cfg.SnapshotKeep <= 0 {
+        eRuntime.cfg.SnapshotKeep = nSnapshotKeep
     }
     if (testnet && len(eRuntime.cfg.TickReserved) > 0) {
         operation.ApplyTickReserved(eRuntime.cfg.TickReserved)
@@ -67,20 +93,33 @@ func Init(ctx context.Context, wg *sync.WaitGroup, cfg config.StartupConfig, tes
     if len(eRuntime.vspcList) > 0 {
         lenVspc := len(eRuntime.vspcList)
         vspcLast := eRuntime.vspcList[lenVspc-1]
+        eRuntime.daaScoreSnapshot = vspcLast.DaaScore
         slog.Info("explorer.Init", "lastVspcDaaScore", vspcLast.DaaScore, "lastVspcBlockHash", vspcLast.Hash)
         storage.SetRuntimeSynced(false, eRuntime.opScoreLast, vspcLast.DaaScore)
+        updateStatusDaaScore(vspcLast.DaaScore)
     } else {
         slog.Info("explorer.Init", "lastVspcDaaScore", eRuntime.cfg.DaaScoreRange[0][0], "lastVspcBlockHash", "")
         storage.SetRuntimeSynced(false, eRuntime.opScoreLast, eRuntime.cfg.DaaScoreRange[0][0])
+        updateStatusDaaScore(eRuntime.cfg.DaaScoreRange[0][0])
     }
     storage.SetRuntimeVersion(config.Version)
     slog.Info("explorer ready.")
 }
 
+////////////////////////////////
+// Get the available daaScore range, configurable only when testnet.
+func GetDaaScoreRange(cfg config.StartupConfig, testnet bool) ([][2]uint64) {
+    if (!testnet || len(cfg.DaaScoreRange) <= 0) {
+        return daaScoreRange
+    }
+    return cfg.DaaScoreRange
+}
+
 ////////////////////////////////
 func Run() {
     eRuntime.wg.Add(1)
     defer eRuntime.wg.Done()
+    go runStatusTip()
 loop:
     for {
         select {
@@ -89,8 +128,38 @@ loop:
                 break loop
             default:
                 scan()
+                updateStatusScan()
                 // Basic loop delay.
                 time.Sleep(100*time.Millisecond)
         }
     }
 }
+
+////////////////////////////////
+// Scan until the daaScore end reached without the loop, used for the replay of the fixture data.
+func Replay(daaScoreEnd uint64) (storage.DataRollbackType, error) {
+    daaScoreLast := uint64(0)
+    for {
+        scan()
+        if eRuntime.errScan != nil {
+            return storage.DataRollbackType{}, eRuntime.errScan
+        }
+        daaScoreNow := uint64(0)
+        lenVspc := len(eRuntime.vspcList)
+        if lenVspc > 0 {
+            daaScoreNow = eRuntime.vspcList[lenVspc-1].DaaScore
+        }
+        if daaScoreNow >= daaScoreEnd {
+            break
+        }
+        if daaScoreNow == daaScoreLast {
+            return storage.DataRollbackType{}, errors.New("replay stalled at daaScore " + strconv.FormatUint(daaScoreNow, 10))
+        }
+        daaScoreLast = daaScoreNow
+    }
+    lenRollback := len(eRuntime.rollbackList)
+    if lenRollback <= 0 {
+        return storage.DataRollbackType{}, nil
+    }
+    return eRuntime.rollbackList[lenRollback-1], nil
+}
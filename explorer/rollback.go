@@ -0,0 +1,97 @@
+
+////////////////////////////////
+package explorer
+
+import (
+    "errors"
+    "strconv"
+    "kasplex-executor/storage"
+)
+
+////////////////////////////////
+// Reload the runtime rollback and vspc list from the persisted rollback records.
+func loadRollbackRecord() (error) {
+    recordList, err := storage.GetRollbackRecordLast(lenRollbackListRuntimeMax)
+    if err != nil {
+        return err
+    }
+    if len(recordList) <= 0 {
+        return nil
+    }
+    rollbackList := make([]storage.DataRollbackType, 0, len(recordList))
+    vspcList := []storage.DataVspcType{}
+    for _, record := range recordList {
+        rollbackList = append(rollbackList, record.Rollback)
+        vspcList = append(vspcList, record.VspcList...)
+    }
+    lenStart := len(vspcList) - lenVspcListRuntimeMax
+    if lenStart > 0 {
+        vspcList = vspcList[lenStart:]
+    }
+    eRuntime.rollbackList = rollbackList
+    eRuntime.vspcList = vspcList
+    return nil
+}
+
+////////////////////////////////
+// Persist the runtime rollback list and the checkpoint of the last batch.
+func saveRuntimeRollback() {
+    storage.SetRuntimeRollbackLast(eRuntime.rollbackList)
+    lenRollback := len(eRuntime.rollbackList)
+    if lenRollback <= 0 {
+        storage.SetRuntimeCheckpoint("", 0, 0)
+        return
+    }
+    rollbackLast := eRuntime.rollbackList[lenRollback-1]
+    storage.SetRuntimeCheckpoint(rollbackLast.CheckpointAfter, rollbackLast.OpScoreLast, rollbackLast.DaaScoreEnd)
+    updateStatusDaaScore(rollbackLast.DaaScoreEnd)
+}
+
+////////////////////////////////
+// Rewind the state to the last batch end at or before the daaScore, by the persisted rollback records.
+// The executor must be stopped, the runtime data is reset to the batch and the scan resumes from it.
+func Rewind(daaScoreTo uint64) (storage.DataRollbackType, int, error) {
+    recordFirst, err := storage.GetRollbackRecordFirst()
+    if err != nil {
+        return storage.DataRollbackType{}, 0, err
+    }
+    if recordFirst == nil {
+        return storage.DataRollbackType{}, 0, errors.New("rollback record empty")
+    }
+    if recordFirst.Rollback.DaaScoreEnd > daaScoreTo {
+        return storage.DataRollbackType{}, 0, errors.New("daaScore out of retention, earliest " + strconv.FormatUint(recordFirst.Rollback.DaaScoreEnd, 10))
+    }
+    nRewind := 0
+    for {
+        recordList, err := storage.GetRollbackRecordLast(1)
+        if err != nil {
+            return storage.DataRollbackType{}, nRewind, err
+        }
+        if (len(recordList) <= 0 || recordList[0].Rollback.DaaScoreEnd <= daaScoreTo) {
+            break
+        }
+        _, err = storage.RollbackOpStateBatch(recordList[0].Rollback)
+        if err != nil {
+            return storage.DataRollbackType{}, nRewind, err
+        }
+        nRewind ++
+    }
+    
+    // Reset the runtime data to the last batch retained.
+    err = loadRollbackRecord()
+    if err != nil {
+        return storage.DataRollbackType{}, nRewind, err
+    }
+    rollbackLast := eRuntime.rollbackList[len(eRuntime.rollbackList)-1]
+    err = storage.SetRuntimeVspcLast(eRuntime.vspcList)
+    if err != nil {
+        return storage.DataRollbackType{}, nRewind, err
+    }
+    err = storage.SetRuntimeRollbackLast(eRuntime.rollbackList)
+    if err != nil {
+        return storage.DataRollbackType{}, nRewind, err
+    }
+    storage.SetRuntimeSynced(false, rollbackLast.OpScoreLast, rollbackLast.DaaScoreEnd)
+    storage.SetRuntimeCheckpoint(rollbackLast.CheckpointAfter, rollbackLast.OpScoreLast, rollbackLast.DaaScoreEnd)
+    return rollbackLast, nRewind, nil
+}
@@ -6,6 +6,8 @@ import (
     "time"
     "strconv"
     "log/slog"
+    "kasplex-executor/events"
+    "kasplex-executor/metrics"
     "kasplex-executor/storage"
     "kasplex-executor/operation"
 )
@@ -19,6 +21,21 @@ const lenRollbackListRuntimeMax = 350
 ////////////////////////////////
 func scan() {
     mtss := time.Now().UnixMilli()
+    eRuntime.errScan = nil
+    
+    // Reload the runtime list from the persisted rollback records if failed after the last rollback.
+    if eRuntime.reloadRollback {
+        err := loadRollbackRecord()
+        if err != nil {
+            slog.Warn("explorer.loadRollbackRecord failed, sleep 3s.", "error", err.Error())
+            eRuntime.errScan = err
+            time.Sleep(3000*time.Millisecond)
+            return
+        }
+        eRuntime.reloadRollback = false
+        saveRuntimeRollback()
+        storage.SetRuntimeVspcLast(eRuntime.vspcList)
+    }
     
     // Get the next vspc data list.
     vspcLast := storage.DataVspcType{
@@ -35,13 +52,16 @@ func scan() {
         }
     }
     //_, daaScoreStart = checkDaaScoreRange(daaScoreStart)
-    // Get next vspc data list from cluster db.
-    vspcListNext, mtsBatchVspc, err := storage.GetNodeVspcList(daaScoreStart, lenVspcListMax)
+    // Get next vspc data list from the node source.
+    vspcListNext, mtsBatchVspc, err := eRuntime.source.GetVspcList(daaScoreStart, lenVspcListMax)
     if err != nil {
         slog.Warn("storage.GetNodeVspcList failed, sleep 3s.", "daaScore", daaScoreStart, "error", err.Error())
+        eRuntime.errScan = err
         time.Sleep(3000*time.Millisecond)
         return
     }
+    metrics.ScanSeconds.ObserveMts(mtsBatchVspc, "vspc")
+    updateDaaScoreTip(vspcListNext)
     // Ignore the last reserved vspc data, reduce the probability of vspc-reorg.
     lenVspcNext := len(vspcListNext)
     lenVspcNext -= eRuntime.cfg.Hysteresis
@@ -58,16 +78,41 @@ func scan() {
     if daaScoreRollback > 0 {
         daaScoreLast := uint64(0)
         mtsRollback := int64(0)
+        // Reload from the persisted rollback records if the runtime list exhausted.
+        if len(eRuntime.rollbackList) <= 0 {
+            err = loadRollbackRecord()
+            if err != nil {
+                slog.Warn("explorer.loadRollbackRecord failed, sleep 3s.", "error", err.Error())
+                eRuntime.errScan = err
+                time.Sleep(3000*time.Millisecond)
+                return
+            }
+        }
         // Rollback to the last state data batch.
         lenRollback := len(eRuntime.rollbackList) - 1
         if (lenRollback >= 0 && eRuntime.rollbackList[lenRollback].DaaScoreEnd >= daaScoreRollback) {
             daaScoreLast = eRuntime.rollbackList[lenRollback].DaaScoreStart
+            // Read the ops rolled back for the retraction events, before removed.
+            opListRetract := []storage.DataOpListType{}
+            if events.Subscribed() {
+                opListRetract, err = storage.GetOpListByScore(eRuntime.rollbackList[lenRollback].OpScoreList)
+                if err != nil {
+                    slog.Warn("storage.GetOpListByScore failed, sleep 3s.", "error", err.Error())
+                    eRuntime.errScan = err
+                    time.Sleep(3000*time.Millisecond)
+                    return
+                }
+            }
             mtsRollback, err = storage.RollbackOpStateBatch(eRuntime.rollbackList[lenRollback])
             if err != nil {
                 slog.Warn("storage.RollbackOpStateBatch failed, sleep 3s.", "error", err.Error())
+                eRuntime.errScan = err
                 time.Sleep(3000*time.Millisecond)
                 return
             }
+            metrics.RollbackTotal.Inc()
+            metrics.ScanSeconds.ObserveMts(mtsRollback, "rollback")
+            events.PublishRollback(opListRetract, eRuntime.rollbackList[lenRollback].CheckpointBefore)
             // Remove the vspc data of rollback.
             for {
                 lenVspcRuntime = len(eRuntime.vspcList)
@@ -85,9 +130,20 @@ func scan() {
                 }
                 break
             }
-            // Remove the last rollback data.
+            // Remove the last rollback data, the persisted record is deleted with the state.
             eRuntime.rollbackList = eRuntime.rollbackList[:lenRollback]
-            storage.SetRuntimeRollbackLast(eRuntime.rollbackList)
+            if (lenRollback <= 0 || len(eRuntime.vspcList) <= 0) {
+                err = loadRollbackRecord()
+                if err != nil {
+                    // Keep the persisted runtime data, the rollback is redone or the list reloaded in the next loop.
+                    slog.Warn("explorer.loadRollbackRecord failed, sleep 3s.", "error", err.Error())
+                    eRuntime.errScan = err
+                    eRuntime.reloadRollback = true
+                    time.Sleep(3000*time.Millisecond)
+                    return
+                }
+            }
+            saveRuntimeRollback()
         } else {
             eRuntime.vspcList = vspcListNext
         }
@@ -117,23 +173,27 @@ func scan() {
             })
         }
     }
-    // Get the transaction data list from cluster db.
+    // Get the transaction data list from the node source.
     lenTxData := len(txDataList)
-    txDataList, mtsBatchTx, err := storage.GetNodeTransactionDataList(txDataList)
+    txDataList, mtsBatchTx, err := storage.GetNodeTransactionDataList(eRuntime.source, txDataList)
     if err != nil {
         slog.Warn("storage.GetNodeTransactionDataList failed, sleep 3s.", "lenTransaction", lenTxData, "error", err.Error())
+        eRuntime.errScan = err
         time.Sleep(3000*time.Millisecond)
         return
     }
+    metrics.ScanSeconds.ObserveMts(mtsBatchTx, "tx")
     slog.Info("storage.GetNodeTransactionDataList", "lenTransaction/mSecond", strconv.Itoa(lenTxData)+"/"+strconv.Itoa(int(mtsBatchTx)))
     
     // Parse the transaction and calculate fee for OP.
     opDataList, mtsBatchOp, err := ParseOpDataList(txDataList)
     if err != nil {
         slog.Warn("explorer.ParseOpDataList failed, sleep 3s.", "error", err.Error())
+        eRuntime.errScan = err
         time.Sleep(3000*time.Millisecond)
         return
     }
+    metrics.ScanSeconds.ObserveMts(mtsBatchOp, "op")
     lenOpData := len(opDataList)
     slog.Info("explorer.ParseOpDataList", "lenOperation/mSecond", strconv.Itoa(lenOpData)+"/"+strconv.Itoa(int(mtsBatchOp)))
     
@@ -141,9 +201,11 @@ func scan() {
     stateMap, mtsBatchSt, err := operation.PrepareStateBatch(opDataList)
     if err != nil {
         slog.Warn("operation.PrepareStateBatch failed, sleep 3s.", "error", err.Error())
+        eRuntime.errScan = err
         time.Sleep(3000*time.Millisecond)
         return
     }
+    metrics.ScanSeconds.ObserveMts(mtsBatchSt, "st")
     slog.Debug("operation.PrepareStateBatch", "lenToken/lenBalance/mSecond", strconv.Itoa(len(stateMap.StateTokenMap))+"/"+strconv.Itoa(len(stateMap.StateBalanceMap))+"/"+strconv.Itoa(int(mtsBatchSt)))
     
     // Execute the op list and generate the rollback data.
@@ -154,6 +216,7 @@ func scan() {
     rollback, mtsBatchExe, err := operation.ExecuteBatch(opDataList, stateMap, checkpointLast, eRuntime.testnet)
     if err != nil {
         slog.Warn("operation.ExecuteBatch failed, sleep 3s.", "error", err.Error())
+        eRuntime.errScan = err
         time.Sleep(3000*time.Millisecond)
         return
     }
@@ -167,23 +230,38 @@ func scan() {
     } else {
         eRuntime.opScoreLast = rollback.OpScoreLast
     }
+    metrics.ScanSeconds.ObserveMts(mtsBatchExe, "exe")
     slog.Debug("operation.ExecuteBatch", "checkpoint", rollback.CheckpointAfter, "lenOperation/mSecond", strconv.Itoa(lenOpData)+"/"+strconv.Itoa(int(mtsBatchExe)))
     
-    // Save the op/state result data list.
-    mtsBatchList, err := storage.SaveOpStateBatch(opDataList, stateMap)
+    // Save the op/state result data list and the rollback record.
+    mtsBatchList, err := storage.SaveOpStateBatch(opDataList, stateMap, rollback, vspcListNext)
     if err != nil {
         slog.Warn("storage.SaveOpStateBatch failed, sleep 3s.", "error", err.Error())
+        eRuntime.errScan = err
         time.Sleep(3000*time.Millisecond)
         return
     }
+    metrics.ScanSeconds.ObserveMts(mtsBatchList[0], "save_rocks_begin")
+    metrics.ScanSeconds.ObserveMts(mtsBatchList[1], "save_state_cassa")
+    metrics.ScanSeconds.ObserveMts(mtsBatchList[2], "save_opdata_cassa")
+    metrics.ScanSeconds.ObserveMts(mtsBatchList[3], "save_rocks_commit")
     slog.Debug("operation.SaveOpStateBatch", "mSecondList", strconv.Itoa(int(mtsBatchList[0]))+"/"+strconv.Itoa(int(mtsBatchList[1]))+"/"+strconv.Itoa(int(mtsBatchList[2]))+"/"+strconv.Itoa(int(mtsBatchList[3])))
     
+    // Remove the rollback records out of retention.
+    if rollback.DaaScoreEnd > eRuntime.cfg.RollbackRetention {
+        _, err = storage.DeleteRollbackRecordBefore(rollback.DaaScoreEnd - eRuntime.cfg.RollbackRetention)
+        if err != nil {
+            slog.Warn("storage.DeleteRollbackRecordBefore failed.", "error", err.Error())
+        }
+    }
+    
     // Update the runtime data.
     eRuntime.synced = false
     if (lenVspcNext < 50) {
         eRuntime.synced = true
     }
     storage.SetRuntimeSynced(eRuntime.synced, eRuntime.opScoreLast, vspcListNext[lenVspcNext-1].DaaScore)
+    storage.SetRuntimeCheckpoint(rollback.CheckpointAfter, rollback.OpScoreLast, rollback.DaaScoreEnd)
     eRuntime.vspcList = append(eRuntime.vspcList, vspcListNext...)
     lenStart := len(eRuntime.vspcList) - lenVspcListRuntimeMax
     if lenStart > 0 {
@@ -196,9 +274,17 @@ func scan() {
         eRuntime.rollbackList = eRuntime.rollbackList[lenStart:]
     }
     storage.SetRuntimeRollbackLast(eRuntime.rollbackList)
+    updateStatusBatch(opDataList, vspcListNext[lenVspcNext-1].DaaScore)
+    
+    // Publish the ops accepted to the subscribers.
+    events.PublishOpList(opDataList)
+    
+    // Write the snapshot file if the interval reached.
+    saveSnapshot(rollback)
         
     // Additional delay if state synced.
     mtsLoop := time.Now().UnixMilli() - mtss
+    metrics.ScanSeconds.ObserveMts(mtsLoop, "loop")
     slog.Info("explorer.scan", "lenRuntimeVspc", len(eRuntime.vspcList), "lenRuntimeRollback", len(eRuntime.rollbackList), "lenOperation", lenOpData, "mSecondLoop", mtsLoop)
     if (eRuntime.synced) {
         mtsLoop = 1650 - mtsLoop
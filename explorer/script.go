@@ -295,8 +295,12 @@ func parseOpData(txData *storage.DataTransactionType) (*storage.DataOperationTyp
         if (!ValidateP(&decoded.P) || !ValidateOp(&decoded.Op) || !ValidateAscii(&decoded.To)) {
             continue
         }
-        operation.Method_Registered[decoded.Op].ScriptCollectEx(i, &decoded, txData, eRuntime.testnet)
-        if !operation.Method_Registered[decoded.Op].Validate(&decoded, txData.DaaScore, eRuntime.testnet) {
+        method := operation.Method_Registered[decoded.P+"_"+decoded.Op]
+        if method == nil {
+            continue
+        }
+        method.ScriptCollectEx(i, &decoded, txData, eRuntime.testnet)
+        if !method.Validate(&decoded, txData.DaaScore, eRuntime.testnet) {
             continue
         }
         if i == 0 {
@@ -305,7 +309,7 @@ func parseOpData(txData *storage.DataTransactionType) (*storage.DataOperationTyp
             scriptSig = scriptInfo[4]
             continue
         }
-        if !operation.OpRecycle_Registered[decoded.Op] {
+        if !operation.OpRecycle_Registered[decoded.P+"_"+decoded.Op] {
             continue
         }
         opScript = append(opScript, &decoded)
@@ -342,7 +346,7 @@ func ParseOpDataList(txDataList []storage.DataTransactionType) ([]storage.DataOp
         }
         mutex.Lock()
         opDataMap[opData.TxId] = opData
-        opDataMap[opData.TxId].FeeLeast = operation.Method_Registered[opData.OpScript[0].Op].FeeLeast(opData.DaaScore)
+        opDataMap[opData.TxId].FeeLeast = operation.Method_Registered[opData.OpScript[0].P+"_"+opData.OpScript[0].Op].FeeLeast(opData.DaaScore)
         if opDataMap[opData.TxId].FeeLeast > 0 {
             for _, input := range txDataList[i].Data.Inputs {
                 txIdMap[input.PreviousOutpoint.TransactionId] = true
@@ -355,7 +359,7 @@ func ParseOpDataList(txDataList []storage.DataTransactionType) ([]storage.DataOp
     for txId := range txIdMap {
         txDataListInput = append(txDataListInput, storage.DataTransactionType{TxId: txId})
     }
-    txDataMapInput, _, err := storage.GetNodeTransactionDataMap(txDataListInput)
+    txDataMapInput, _, err := eRuntime.source.GetTransactionDataMap(txDataListInput)
     if err != nil {
         return nil, 0, err
     }
@@ -377,6 +381,7 @@ func ParseOpDataList(txDataList []storage.DataTransactionType) ([]storage.DataOp
             for _, output := range txData.Data.Outputs {
                 amountOut += output.Amount
             }
+            // The input missing in the archive is skipped as the reference executor, the kaspad source reports it as an error instead.
             for _, input := range txData.Data.Inputs {
                 if txDataMapInput[input.PreviousOutpoint.TransactionId] == nil {
                     continue
@@ -0,0 +1,76 @@
+
+////////////////////////////////
+package explorer
+
+import (
+    "os"
+    "sort"
+    "time"
+    "log/slog"
+    "path/filepath"
+    "kasplex-executor/config"
+    "kasplex-executor/storage"
+)
+
+////////////////////////////////
+// Write the snapshot file after the batch if the daaScore interval reached, the old files out of the count kept are removed.
+// The state is taken as the point-in-time view in the scan loop, and the file is written in the background.
+// The interval is skipped if the last file is still being written.
+func saveSnapshot(rollback storage.DataRollbackType) {
+    if (eRuntime.cfg.SnapshotDir == "" || len(eRuntime.vspcList) <= 0) {
+        return
+    }
+    daaScore := eRuntime.vspcList[len(eRuntime.vspcList)-1].DaaScore
+    if daaScore/eRuntime.cfg.SnapshotInterval <= eRuntime.daaScoreSnapshot/eRuntime.cfg.SnapshotInterval {
+        eRuntime.daaScoreSnapshot = daaScore
+        return
+    }
+    eRuntime.daaScoreSnapshot = daaScore
+    if !eRuntime.snapshotWriting.CompareAndSwap(false, true) {
+        slog.Warn("explorer.saveSnapshot skipped, the last one is still being written.", "daaScore", daaScore)
+        return
+    }
+    err := os.MkdirAll(eRuntime.cfg.SnapshotDir, 0755)
+    if err != nil {
+        slog.Warn("explorer.saveSnapshot failed.", "error", err.Error())
+        eRuntime.snapshotWriting.Store(false)
+        return
+    }
+    // The runtime lists are copied, they change with the next batch.
+    header := storage.DataSnapshotHeaderType{
+        Version: config.Version,
+        Testnet: eRuntime.testnet,
+        DaaScore: daaScore,
+        OpScore: rollback.OpScoreLast,
+        Checkpoint: rollback.CheckpointAfter,
+        VspcList: append([]storage.DataVspcType{}, eRuntime.vspcList...),
+        RollbackList: append([]storage.DataRollbackType{}, eRuntime.rollbackList...),
+        MtsAdd: time.Now().UnixMilli(),
+    }
+    snapshotRocks := storage.NewSnapshotRocks()
+    eRuntime.wg.Add(1)
+    go func() {
+        defer eRuntime.wg.Done()
+        defer eRuntime.snapshotWriting.Store(false)
+        defer storage.ReleaseSnapshotRocks(snapshotRocks)
+        writeSnapshot(header, snapshotRocks)
+    }()
+}
+
+////////////////////////////////
+// Write the snapshot file of the point-in-time view, the old files out of the count kept are removed.
+func writeSnapshot(header storage.DataSnapshotHeaderType, snapshotRocks *storage.SnapshotRocksType) {
+    mtss := time.Now().UnixMilli()
+    path := storage.MakeSnapshotPath(eRuntime.cfg.SnapshotDir, header.DaaScore)
+    hash, count, err := storage.WriteSnapshot(path, header, snapshotRocks)
+    if err != nil {
+        slog.Warn("storage.WriteSnapshot failed.", "error", err.Error())
+        return
+    }
+    slog.Info("explorer.saveSnapshot", "daaScore", header.DaaScore, "lenState", count, "hash", hash, "mSecond", time.Now().UnixMilli()-mtss)
+    pathList, _ := filepath.Glob(filepath.Join(eRuntime.cfg.SnapshotDir, storage.PatternSnapshotFile))
+    sort.Strings(pathList)
+    for i := 0; i < len(pathList)-eRuntime.cfg.SnapshotKeep; i ++ {
+        os.Remove(pathList[i])
+    }
+}
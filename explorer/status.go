@@ -0,0 +1,133 @@
+
+////////////////////////////////
+package explorer
+
+import (
+    "sync"
+    "time"
+    "kasplex-executor/metrics"
+    "kasplex-executor/storage"
+)
+
+////////////////////////////////
+// The scan status read by the health check, the daaScore tip is the last vspc got if the node source has no tip.
+type StatusType struct {
+    DaaScore uint64
+    DaaScoreTip uint64
+    OpScoreLast uint64
+    Synced bool
+    CatchingUp bool
+    ErrScan string
+    MtsBatchLast int64
+}
+
+////////////////////////////////
+const mtsRefreshStatusTip = 3000
+
+////////////////////////////////
+var status = struct {
+    mutex sync.RWMutex
+    data StatusType
+}{}
+
+////////////////////////////////
+// Get the scan status, safe to call from other goroutines.
+func GetStatus() (StatusType) {
+    status.mutex.RLock()
+    defer status.mutex.RUnlock()
+    return status.data
+}
+
+////////////////////////////////
+func setStatus(fSet func(*StatusType)) {
+    status.mutex.Lock()
+    defer status.mutex.Unlock()
+    fSet(&status.data)
+}
+
+////////////////////////////////
+// Update the scan error after each loop.
+func updateStatusScan() {
+    errScan := ""
+    if eRuntime.errScan != nil {
+        errScan = eRuntime.errScan.Error()
+        metrics.ScanErrorTotal.Inc()
+    }
+    setStatus(func(data *StatusType) {
+        data.ErrScan = errScan
+    })
+}
+
+////////////////////////////////
+// Update the daaScore tip of the node source periodically, the tip of the archive is probed not in the scan loop.
+func runStatusTip() {
+    for {
+        select {
+            case <-eRuntime.ctx.Done():
+                return
+            case <-time.After(mtsRefreshStatusTip*time.Millisecond):
+                source, ok := eRuntime.source.(interface{ GetDaaScoreTip() (uint64) })
+                if !ok {
+                    return
+                }
+                setStatusTip(source.GetDaaScoreTip())
+        }
+    }
+}
+
+////////////////////////////////
+// Update the catching up after the vspc list got, the full list means the tip is beyond the list.
+// The last vspc got is the tip if the node source has no tip.
+func updateDaaScoreTip(vspcList []storage.DataVspcType) {
+    if _, ok := eRuntime.source.(interface{ GetDaaScoreTip() (uint64) }); (!ok && len(vspcList) > 0) {
+        setStatusTip(vspcList[len(vspcList)-1].DaaScore)
+    }
+    setStatus(func(data *StatusType) {
+        data.CatchingUp = len(vspcList) >= lenVspcListMax
+    })
+}
+
+////////////////////////////////
+func setStatusTip(daaScoreTip uint64) {
+    if daaScoreTip > 0 {
+        metrics.DaaScoreTip.Set(float64(daaScoreTip))
+    }
+    setStatus(func(data *StatusType) {
+        if daaScoreTip > 0 {
+            data.DaaScoreTip = daaScoreTip
+        }
+    })
+}
+
+////////////////////////////////
+// Update the daaScore processed, after the batch committed or rolled back.
+func updateStatusDaaScore(daaScore uint64) {
+    metrics.DaaScore.Set(float64(daaScore))
+    metrics.OpScoreLast.Set(float64(eRuntime.opScoreLast))
+    synced := float64(0)
+    if eRuntime.synced {
+        synced = 1
+    }
+    metrics.Synced.Set(synced)
+    setStatus(func(data *StatusType) {
+        data.DaaScore = daaScore
+        data.OpScoreLast = eRuntime.opScoreLast
+        data.Synced = eRuntime.synced
+    })
+}
+
+////////////////////////////////
+// Update the status after the batch committed, the ops are counted by the result and the error.
+func updateStatusBatch(opDataList []storage.DataOperationType, daaScore uint64) {
+    for _, opData := range opDataList {
+        if opData.OpAccept == 1 {
+            metrics.OpTotal.Inc(opData.OpScript[0].Op, "accept", "")
+        } else {
+            metrics.OpTotal.Inc(opData.OpScript[0].Op, "reject", opData.OpError)
+        }
+    }
+    updateStatusDaaScore(daaScore)
+    setStatus(func(data *StatusType) {
+        data.MtsBatchLast = time.Now().UnixMilli()
+    })
+}
@@ -0,0 +1,273 @@
+
+////////////////////////////////
+package kaspad
+
+import (
+    "sync"
+    "time"
+    "errors"
+    "context"
+    "log/slog"
+    "google.golang.org/grpc"
+    "google.golang.org/grpc/credentials/insecure"
+    "kasplex-executor/protowire"
+)
+
+////////////////////////////////
+const mtsTimeoutRequest = 30000
+const lenMsgMax = 1024 * 1024 * 1024
+
+////////////////////////////////
+type pendingType struct {
+    id uint64
+    chResponse chan *protowire.KaspadResponse
+}
+
+////////////////////////////////
+// Client of the kaspad RPC.MessageStream service.
+type Client struct {
+    conn *grpc.ClientConn
+    stream protowire.RPC_MessageStreamClient
+    cancel context.CancelFunc
+    fNotify func(*protowire.KaspadResponse)
+    mutex sync.Mutex
+    idNext uint64
+    pendingList []pendingType
+    errStream error
+    chDone chan struct{}
+}
+
+////////////////////////////////
+// Dial the kaspad node and open the message stream, the notifications are passed to fNotify.
+func Dial(ctx context.Context, target string, fNotify func(*protowire.KaspadResponse), opts ...grpc.DialOption) (*Client, error) {
+    optList := []grpc.DialOption{
+        grpc.WithTransportCredentials(insecure.NewCredentials()),
+        grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(lenMsgMax), grpc.MaxCallSendMsgSize(lenMsgMax)),
+    }
+    optList = append(optList, opts...)
+    conn, err := grpc.NewClient(target, optList...)
+    if err != nil {
+        return nil, err
+    }
+    ctxStream, cancel := context.WithCancel(ctx)
+    stream, err := protowire.NewRPCClient(conn).MessageStream(ctxStream)
+    if err != nil {
+        cancel()
+        conn.Close()
+        return nil, err
+    }
+    client := &Client{
+        conn: conn,
+        stream: stream,
+        cancel: cancel,
+        fNotify: fNotify,
+        idNext: 1,
+        chDone: make(chan struct{}),
+    }
+    go client.receive()
+    return client, nil
+}
+
+////////////////////////////////
+// Close the message stream and the connection.
+func (client *Client) Close() {
+    client.cancel()
+    client.conn.Close()
+}
+
+////////////////////////////////
+// Channel closed when the message stream is broken.
+func (client *Client) Done() (<-chan struct{}) {
+    return client.chDone
+}
+
+////////////////////////////////
+// Receive the responses and notifications from the message stream.
+func (client *Client) receive() {
+    for {
+        response, err := client.stream.Recv()
+        if err != nil {
+            client.mutex.Lock()
+            client.errStream = err
+            for _, pending := range client.pendingList {
+                close(pending.chResponse)
+            }
+            client.pendingList = nil
+            client.mutex.Unlock()
+            close(client.chDone)
+            return
+        }
+        if isNotification(response) {
+            if client.fNotify != nil {
+                client.fNotify(response)
+            }
+            continue
+        }
+        // Match the response strictly by id, the one of the request timed out or unknown is dropped.
+        client.mutex.Lock()
+        iPending := -1
+        for i, pending := range client.pendingList {
+            if pending.id == response.Id {
+                iPending = i
+                break
+            }
+        }
+        if iPending >= 0 {
+            client.pendingList[iPending].chResponse <- response
+            client.pendingList = append(client.pendingList[:iPending], client.pendingList[iPending+1:]...)
+        }
+        client.mutex.Unlock()
+        if iPending < 0 {
+            slog.Warn("kaspad.Client response unmatched, dropped.", "id", response.Id)
+        }
+    }
+}
+
+////////////////////////////////
+// Send the request and wait for the response.
+func (client *Client) request(request *protowire.KaspadRequest) (*protowire.KaspadResponse, error) {
+    chResponse := make(chan *protowire.KaspadResponse, 1)
+    client.mutex.Lock()
+    if client.errStream != nil {
+        client.mutex.Unlock()
+        return nil, client.errStream
+    }
+    request.Id = client.idNext
+    client.idNext ++
+    client.pendingList = append(client.pendingList, pendingType{id: request.Id, chResponse: chResponse})
+    err := client.stream.Send(request)
+    client.mutex.Unlock()
+    if err != nil {
+        return nil, err
+    }
+    select {
+        case response, ok := <-chResponse:
+            if !ok {
+                return nil, errors.New("kaspad stream closed")
+            }
+            return response, nil
+        case <-time.After(mtsTimeoutRequest*time.Millisecond):
+            client.mutex.Lock()
+            for i, pending := range client.pendingList {
+                if pending.id == request.Id {
+                    client.pendingList = append(client.pendingList[:i], client.pendingList[i+1:]...)
+                    break
+                }
+            }
+            client.mutex.Unlock()
+            return nil, errors.New("kaspad request timeout")
+    }
+}
+
+////////////////////////////////
+// Subscribe the virtual chain changed notifications, with the accepted transaction ids.
+func (client *Client) NotifyVirtualChainChanged() (error) {
+    response, err := client.request(&protowire.KaspadRequest{
+        Payload: &protowire.KaspadRequest_NotifyVirtualChainChangedRequest{
+            NotifyVirtualChainChangedRequest: &protowire.NotifyVirtualChainChangedRequestMessage{
+                IncludeAcceptedTransactionIds: true,
+                Command: protowire.RpcNotifyCommand_NOTIFY_START,
+            },
+        },
+    })
+    if err != nil {
+        return err
+    }
+    result := response.GetNotifyVirtualChainChangedResponse()
+    if result == nil {
+        return errors.New("kaspad response invalid")
+    }
+    return errRpc(result.Error)
+}
+
+////////////////////////////////
+// Get the virtual chain from the start block, with the accepted transaction ids.
+func (client *Client) GetVirtualChainFromBlock(hashStart string) (*protowire.GetVirtualChainFromBlockResponseMessage, error) {
+    response, err := client.request(&protowire.KaspadRequest{
+        Payload: &protowire.KaspadRequest_GetVirtualChainFromBlockRequest{
+            GetVirtualChainFromBlockRequest: &protowire.GetVirtualChainFromBlockRequestMessage{
+                StartHash: hashStart,
+                IncludeAcceptedTransactionIds: true,
+            },
+        },
+    })
+    if err != nil {
+        return nil, err
+    }
+    result := response.GetGetVirtualChainFromBlockResponse()
+    if result == nil {
+        return nil, errors.New("kaspad response invalid")
+    }
+    return result, errRpc(result.Error)
+}
+
+////////////////////////////////
+// Get the block by hash, with or without the transaction data.
+func (client *Client) GetBlock(hash string, includeTransactions bool) (*protowire.RpcBlock, error) {
+    response, err := client.request(&protowire.KaspadRequest{
+        Payload: &protowire.KaspadRequest_GetBlockRequest{
+            GetBlockRequest: &protowire.GetBlockRequestMessage{
+                Hash: hash,
+                IncludeTransactions: includeTransactions,
+            },
+        },
+    })
+    if err != nil {
+        return nil, err
+    }
+    result := response.GetGetBlockResponse()
+    if result == nil {
+        return nil, errors.New("kaspad response invalid")
+    }
+    err = errRpc(result.Error)
+    if err != nil {
+        return nil, err
+    }
+    if (result.Block == nil || result.Block.Header == nil || result.Block.VerboseData == nil) {
+        return nil, errors.New("kaspad block invalid")
+    }
+    return result.Block, nil
+}
+
+////////////////////////////////
+// Get the dag info, used for the pruning point and the virtual daaScore.
+func (client *Client) GetBlockDagInfo() (*protowire.GetBlockDagInfoResponseMessage, error) {
+    response, err := client.request(&protowire.KaspadRequest{
+        Payload: &protowire.KaspadRequest_GetBlockDagInfoRequest{
+            GetBlockDagInfoRequest: &protowire.GetBlockDagInfoRequestMessage{},
+        },
+    })
+    if err != nil {
+        return nil, err
+    }
+    result := response.GetGetBlockDagInfoResponse()
+    if result == nil {
+        return nil, errors.New("kaspad response invalid")
+    }
+    return result, errRpc(result.Error)
+}
+
+////////////////////////////////
+func isNotification(response *protowire.KaspadResponse) (bool) {
+    switch response.Payload.(type) {
+        case *protowire.KaspadResponse_VirtualChainChangedNotification,
+            *protowire.KaspadResponse_BlockAddedNotification,
+            *protowire.KaspadResponse_FinalityConflictNotification,
+            *protowire.KaspadResponse_FinalityConflictResolvedNotification,
+            *protowire.KaspadResponse_UtxosChangedNotification,
+            *protowire.KaspadResponse_SinkBlueScoreChangedNotification,
+            *protowire.KaspadResponse_VirtualDaaScoreChangedNotification,
+            *protowire.KaspadResponse_PruningPointUtxoSetOverrideNotification,
+            *protowire.KaspadResponse_NewBlockTemplateNotification:
+            return true
+    }
+    return false
+}
+
+////////////////////////////////
+func errRpc(errRpc *protowire.RPCError) (error) {
+    if errRpc == nil {
+        return nil
+    }
+    return errors.New("kaspad: " + errRpc.Message)
+}
@@ -0,0 +1,459 @@
+
+////////////////////////////////
+package kaspad
+
+import (
+    "sync"
+    "sort"
+    "time"
+    "errors"
+    "context"
+    "log/slog"
+    "google.golang.org/grpc"
+    "kasplex-executor/storage"
+    "kasplex-executor/protowire"
+)
+
+////////////////////////////////
+const lenVspcBufferMax = 2000
+const lenNotifyQueueMax = 10000
+const nDaaScoreKeepVspc = 3000
+const nDaaScoreKeepTx = 36000
+const mtsDelayReconnect = 3000
+
+////////////////////////////////
+type txCacheType struct {
+    daaScore uint64
+    data *protowire.RpcTransaction
+}
+
+////////////////////////////////
+// The notifications received, the catch-up is signaled instead if the queue is full.
+type notifyQueueType struct {
+    chChanged chan *protowire.VirtualChainChangedNotificationMessage
+    chCatchUp chan struct{}
+}
+
+////////////////////////////////
+// Read the vspc and transaction data from the kaspad node, instead of the node archive db.
+// The transactions are cached from the mergeset of each chain block, the ones before the first
+// chain block ingested are looked up back to the bottom of the cache window, such as the inputs
+// after restarted. The transaction out of the cache window is reported as an error.
+type NodeSource struct {
+    ctx context.Context
+    target string
+    opts []grpc.DialOption
+    mutex sync.RWMutex
+    client *Client
+    hashLookup string
+    lookupStarted bool
+    vspcMap map[uint64]*storage.DataVspcType
+    daaScoreMap map[string]uint64
+    txDataMap map[string]*txCacheType
+    hashLast string
+    daaScoreStart uint64
+    daaScoreTip uint64
+    errLast error
+}
+
+////////////////////////////////
+// Connect the kaspad node and start the ingestion from the last vspc seeded, or the start hash.
+func NewNodeSource(ctx context.Context, target string, hashStart string, vspcListSeed []storage.DataVspcType, opts ...grpc.DialOption) (*NodeSource, error) {
+    source := &NodeSource{
+        ctx: ctx,
+        target: target,
+        opts: opts,
+        vspcMap: map[uint64]*storage.DataVspcType{},
+        daaScoreMap: map[string]uint64{},
+        txDataMap: map[string]*txCacheType{},
+        hashLast: hashStart,
+    }
+    // Seed the vspc processed, the reorg of them will be found by the explorer.
+    for _, vspc := range vspcListSeed {
+        vspcData := vspc
+        source.vspcMap[vspc.DaaScore] = &vspcData
+        source.daaScoreMap[vspc.Hash] = vspc.DaaScore
+        source.hashLast = vspc.Hash
+    }
+    client, queue, err := source.connect()
+    if err != nil {
+        return nil, err
+    }
+    go source.run(client, queue)
+    return source, nil
+}
+
+////////////////////////////////
+func (source *NodeSource) GetVspcList(daaScoreStart uint64, lenBlock int) ([]storage.DataVspcType, int64, error) {
+    mtss := time.Now().UnixMilli()
+    source.mutex.Lock()
+    defer source.mutex.Unlock()
+    source.daaScoreStart = daaScoreStart
+    // Remove the vspc and transaction data out of the cache window.
+    for daaScore, vspc := range source.vspcMap {
+        if (daaScore+nDaaScoreKeepVspc >= daaScoreStart || vspc.Hash == source.hashLast) {
+            continue
+        }
+        delete(source.daaScoreMap, vspc.Hash)
+        delete(source.vspcMap, daaScore)
+    }
+    for txId, txData := range source.txDataMap {
+        if txData.daaScore+nDaaScoreKeepTx < daaScoreStart {
+            delete(source.txDataMap, txId)
+        }
+    }
+    vspcList := []storage.DataVspcType{}
+    for i := daaScoreStart; i < daaScoreStart+uint64(lenBlock); i ++ {
+        if source.vspcMap[i] == nil {
+            continue
+        }
+        vspcList = append(vspcList, *source.vspcMap[i])
+    }
+    if (len(vspcList) <= 0 && source.errLast != nil) {
+        return nil, 0, source.errLast
+    }
+    return vspcList, time.Now().UnixMilli() - mtss, nil
+}
+
+////////////////////////////////
+func (source *NodeSource) GetTransactionDataMap(txDataList []storage.DataTransactionType) (map[string]*protowire.RpcTransaction, int64, error) {
+    mtss := time.Now().UnixMilli()
+    txIdMissing := map[string]bool{}
+    source.mutex.RLock()
+    for _, txData := range txDataList {
+        if source.txDataMap[txData.TxId] == nil {
+            txIdMissing[txData.TxId] = true
+        }
+    }
+    source.mutex.RUnlock()
+    if len(txIdMissing) > 0 {
+        err := source.lookupTxData(txIdMissing)
+        if err != nil {
+            return nil, 0, err
+        }
+    }
+    // The transaction missing is an error, the op spending it is never executed without the fee.
+    txDataMap := map[string]*protowire.RpcTransaction{}
+    source.mutex.RLock()
+    defer source.mutex.RUnlock()
+    for _, txData := range txDataList {
+        if source.txDataMap[txData.TxId] == nil {
+            return nil, 0, errors.New("kaspad transaction not found: " + txData.TxId)
+        }
+        txDataMap[txData.TxId] = source.txDataMap[txData.TxId].data
+    }
+    return txDataMap, time.Now().UnixMilli() - mtss, nil
+}
+
+////////////////////////////////
+// Look up the transactions in the mergeset of the chain blocks before the earliest one looked up or ingested.
+func (source *NodeSource) lookupTxData(txIdMissing map[string]bool) (error) {
+    for len(txIdMissing) > 0 {
+        source.mutex.RLock()
+        client := source.client
+        hash := source.hashLookup
+        daaScoreStart := source.daaScoreStart
+        source.mutex.RUnlock()
+        if (client == nil || hash == "") {
+            return nil
+        }
+        block, err := getBlock(client, hash, false)
+        if err != nil {
+            return err
+        }
+        if block.Header.DaaScore+nDaaScoreKeepTx < daaScoreStart {
+            return nil
+        }
+        txDataMap, err := getMergesetTxData(client, block, nil)
+        if err != nil {
+            return err
+        }
+        source.mutex.Lock()
+        for txId, txData := range txDataMap {
+            delete(txIdMissing, txId)
+            if source.txDataMap[txId] != nil {
+                continue
+            }
+            source.txDataMap[txId] = txData
+        }
+        source.hashLookup = block.VerboseData.SelectedParentHash
+        source.mutex.Unlock()
+    }
+    return nil
+}
+
+////////////////////////////////
+// Get the transactions in the mergeset of the chain block, only the accepted ones if txIdMap not nil.
+func getMergesetTxData(client *Client, block *protowire.RpcBlock, txIdMap map[string]bool) (map[string]*txCacheType, error) {
+    txDataMap := map[string]*txCacheType{}
+    hashMergeList := append(append([]string{}, block.VerboseData.MergeSetBluesHashes...), block.VerboseData.MergeSetRedsHashes...)
+    for _, hashMerge := range hashMergeList {
+        blockMerge, err := getBlock(client, hashMerge, true)
+        if err != nil {
+            return nil, err
+        }
+        for _, tx := range blockMerge.Transactions {
+            if tx.VerboseData == nil {
+                continue
+            }
+            if (txIdMap != nil && !txIdMap[tx.VerboseData.TransactionId]) {
+                continue
+            }
+            txDataMap[tx.VerboseData.TransactionId] = &txCacheType{
+                daaScore: block.Header.DaaScore,
+                data: tx,
+            }
+        }
+    }
+    return txDataMap, nil
+}
+
+////////////////////////////////
+// Get the block by hash, the block of another hash is never used as the one requested.
+func getBlock(client *Client, hash string, includeTransactions bool) (*protowire.RpcBlock, error) {
+    block, err := client.GetBlock(hash, includeTransactions)
+    if err != nil {
+        return nil, err
+    }
+    if block.VerboseData.Hash != hash {
+        return nil, errors.New("kaspad block mismatched: " + hash)
+    }
+    return block, nil
+}
+
+////////////////////////////////
+// Get the daaScore of the last chain block ingested.
+func (source *NodeSource) GetDaaScoreTip() (uint64) {
+    source.mutex.RLock()
+    defer source.mutex.RUnlock()
+    return source.daaScoreTip
+}
+
+////////////////////////////////
+// Dial the node and subscribe the virtual chain changed notifications.
+func (source *NodeSource) connect() (*Client, *notifyQueueType, error) {
+    queue := &notifyQueueType{
+        chChanged: make(chan *protowire.VirtualChainChangedNotificationMessage, lenNotifyQueueMax),
+        chCatchUp: make(chan struct{}, 1),
+    }
+    client, err := Dial(source.ctx, source.target, func(response *protowire.KaspadResponse) {
+        notification := response.GetVirtualChainChangedNotification()
+        if notification == nil {
+            return
+        }
+        queue.push(notification)
+    }, source.opts...)
+    if err != nil {
+        return nil, nil, err
+    }
+    err = client.NotifyVirtualChainChanged()
+    if err != nil {
+        client.Close()
+        return nil, nil, err
+    }
+    if source.hashLast == "" {
+        dagInfo, err := client.GetBlockDagInfo()
+        if err != nil {
+            client.Close()
+            return nil, nil, err
+        }
+        source.hashLast = dagInfo.Sink
+    }
+    source.mutex.Lock()
+    source.client = client
+    source.mutex.Unlock()
+    return client, queue, nil
+}
+
+////////////////////////////////
+// Queue the notification, never block the receive, the notification dropped is fetched again by the catch-up.
+func (queue *notifyQueueType) push(notification *protowire.VirtualChainChangedNotificationMessage) {
+    select {
+        case queue.chChanged <- notification:
+        default:
+            select {
+                case queue.chCatchUp <- struct{}{}:
+                default:
+            }
+    }
+}
+
+////////////////////////////////
+// Keep the ingestion running, reconnect the node if failed.
+func (source *NodeSource) run(client *Client, queue *notifyQueueType) {
+    for {
+        err := source.sync(client, queue)
+        client.Close()
+        if source.ctx.Err() != nil {
+            slog.Info("kaspad.NodeSource stopped.")
+            return
+        }
+        source.mutex.Lock()
+        source.errLast = err
+        source.mutex.Unlock()
+        slog.Warn("kaspad.NodeSource sync failed, reconnect 3s.", "error", err.Error())
+        for {
+            time.Sleep(mtsDelayReconnect*time.Millisecond)
+            if source.ctx.Err() != nil {
+                return
+            }
+            client, queue, err = source.connect()
+            if err == nil {
+                break
+            }
+            slog.Warn("kaspad.NodeSource connect failed, retry 3s.", "error", err.Error())
+        }
+        source.mutex.Lock()
+        source.errLast = nil
+        source.mutex.Unlock()
+    }
+}
+
+////////////////////////////////
+// Catch up the virtual chain from the last chain block, then follow the notifications.
+func (source *NodeSource) sync(client *Client, queue *notifyQueueType) (error) {
+    catchUp := true
+    for {
+        if catchUp {
+            // The notifications queued before are included in the chain, the ones after are applied again and the blocks known skipped.
+            for len(queue.chChanged) > 0 {
+                <-queue.chChanged
+            }
+            select {
+                case <-queue.chCatchUp:
+                default:
+            }
+            source.mutex.RLock()
+            hashLast := source.hashLast
+            source.mutex.RUnlock()
+            chain, err := client.GetVirtualChainFromBlock(hashLast)
+            if err != nil {
+                return err
+            }
+            err = source.applyChainChanged(client, chain.RemovedChainBlockHashes, chain.AddedChainBlockHashes, chain.AcceptedTransactionIds)
+            if err != nil {
+                return err
+            }
+            catchUp = false
+        }
+        select {
+            case <-source.ctx.Done():
+                return nil
+            case <-client.Done():
+                return errors.New("kaspad stream closed")
+            case <-queue.chCatchUp:
+                catchUp = true
+            case notification := <-queue.chChanged:
+                err := source.applyChainChanged(client, notification.RemovedChainBlockHashes, notification.AddedChainBlockHashes, notification.AcceptedTransactionIds)
+                if err != nil {
+                    return err
+                }
+        }
+    }
+}
+
+////////////////////////////////
+// Apply the removed/added chain blocks, fetch the accepted transactions from the mergeset.
+func (source *NodeSource) applyChainChanged(client *Client, hashRemovedList []string, hashAddedList []string, acceptedList []*protowire.RpcAcceptedTransactionIds) (error) {
+    acceptedMap := map[string][]string{}
+    for _, accepted := range acceptedList {
+        acceptedMap[accepted.AcceptingBlockHash] = accepted.AcceptedTransactionIds
+    }
+    source.mutex.Lock()
+    for _, hash := range hashRemovedList {
+        daaScore, exists := source.daaScoreMap[hash]
+        if !exists {
+            continue
+        }
+        delete(source.daaScoreMap, hash)
+        if (source.vspcMap[daaScore] != nil && source.vspcMap[daaScore].Hash == hash) {
+            delete(source.vspcMap, daaScore)
+        }
+    }
+    if len(hashRemovedList) > 0 {
+        source.hashLast = ""
+        daaScoreLast := uint64(0)
+        for daaScore, vspc := range source.vspcMap {
+            if daaScore >= daaScoreLast {
+                daaScoreLast = daaScore
+                source.hashLast = vspc.Hash
+            }
+        }
+    }
+    source.mutex.Unlock()
+    for _, hash := range hashAddedList {
+        err := source.waitBuffer()
+        if err != nil {
+            return err
+        }
+        source.mutex.RLock()
+        _, exists := source.daaScoreMap[hash]
+        source.mutex.RUnlock()
+        if exists {
+            continue
+        }
+        block, err := getBlock(client, hash, false)
+        if err != nil {
+            return err
+        }
+        daaScore := block.Header.DaaScore
+        txIdList := append([]string{}, acceptedMap[hash]...)
+        sort.Strings(txIdList)
+        txIdMap := make(map[string]bool, len(txIdList))
+        for _, txId := range txIdList {
+            txIdMap[txId] = true
+        }
+        txDataMap, err := getMergesetTxData(client, block, txIdMap)
+        if err != nil {
+            return err
+        }
+        source.mutex.Lock()
+        // The lookup starts from the selected parent of the first chain block ingested.
+        if !source.lookupStarted {
+            source.lookupStarted = true
+            source.hashLookup = block.VerboseData.SelectedParentHash
+        }
+        for txId, txData := range txDataMap {
+            if source.txDataMap[txId] != nil {
+                continue
+            }
+            source.txDataMap[txId] = txData
+        }
+        if source.vspcMap[daaScore] != nil {
+            delete(source.daaScoreMap, source.vspcMap[daaScore].Hash)
+        }
+        source.vspcMap[daaScore] = &storage.DataVspcType{
+            DaaScore: daaScore,
+            Hash: hash,
+            TxIdList: txIdList,
+        }
+        source.daaScoreMap[hash] = daaScore
+        source.hashLast = hash
+        source.daaScoreTip = daaScore
+        source.mutex.Unlock()
+    }
+    return nil
+}
+
+////////////////////////////////
+// Wait if the vspc not processed by the explorer reach the buffer limit.
+func (source *NodeSource) waitBuffer() (error) {
+    for {
+        lenBuffer := 0
+        source.mutex.RLock()
+        for daaScore := range source.vspcMap {
+            if daaScore >= source.daaScoreStart {
+                lenBuffer ++
+            }
+        }
+        source.mutex.RUnlock()
+        if lenBuffer < lenVspcBufferMax {
+            return nil
+        }
+        select {
+            case <-source.ctx.Done():
+                return source.ctx.Err()
+            case <-time.After(100*time.Millisecond):
+        }
+    }
+}
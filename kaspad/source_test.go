@@ -0,0 +1,267 @@
+
+////////////////////////////////
+package kaspad
+
+import (
+    "net"
+    "sync"
+    "time"
+    "context"
+    "testing"
+    "google.golang.org/grpc"
+    "google.golang.org/grpc/test/bufconn"
+    "kasplex-executor/storage"
+    "kasplex-executor/protowire"
+)
+
+////////////////////////////////
+// The in-process kaspad node, serving the blocks by hash and the virtual chain after the start hash.
+type fakeNodeType struct {
+    protowire.UnimplementedRPCServer
+    mutex sync.Mutex
+    blockMap map[string]*protowire.RpcBlock
+    chainList []string
+    acceptedMap map[string][]string
+    stream protowire.RPC_MessageStreamServer
+    stray bool  // send a response of another id before each block.
+    hashServedMap map[string]string  // serve the block of another hash.
+}
+
+////////////////////////////////
+func (node *fakeNodeType) MessageStream(stream protowire.RPC_MessageStreamServer) (error) {
+    node.mutex.Lock()
+    node.stream = stream
+    node.mutex.Unlock()
+    for {
+        request, err := stream.Recv()
+        if err != nil {
+            return nil
+        }
+        response := &protowire.KaspadResponse{Id: request.Id}
+        node.mutex.Lock()
+        switch payload := request.Payload.(type) {
+            case *protowire.KaspadRequest_NotifyVirtualChainChangedRequest:
+                response.Payload = &protowire.KaspadResponse_NotifyVirtualChainChangedResponse{
+                    NotifyVirtualChainChangedResponse: &protowire.NotifyVirtualChainChangedResponseMessage{},
+                }
+            case *protowire.KaspadRequest_GetBlockDagInfoRequest:
+                response.Payload = &protowire.KaspadResponse_GetBlockDagInfoResponse{
+                    GetBlockDagInfoResponse: &protowire.GetBlockDagInfoResponseMessage{Sink: node.chainList[len(node.chainList)-1]},
+                }
+            case *protowire.KaspadRequest_GetVirtualChainFromBlockRequest:
+                result := &protowire.GetVirtualChainFromBlockResponseMessage{}
+                found := false
+                for _, hash := range node.chainList {
+                    if found {
+                        result.AddedChainBlockHashes = append(result.AddedChainBlockHashes, hash)
+                        result.AcceptedTransactionIds = append(result.AcceptedTransactionIds, &protowire.RpcAcceptedTransactionIds{
+                            AcceptingBlockHash: hash,
+                            AcceptedTransactionIds: node.acceptedMap[hash],
+                        })
+                    }
+                    if hash == payload.GetVirtualChainFromBlockRequest.StartHash {
+                        found = true
+                    }
+                }
+                response.Payload = &protowire.KaspadResponse_GetVirtualChainFromBlockResponse{GetVirtualChainFromBlockResponse: result}
+            case *protowire.KaspadRequest_GetBlockRequest:
+                result := &protowire.GetBlockResponseMessage{}
+                hash := payload.GetBlockRequest.Hash
+                if node.hashServedMap[hash] != "" {
+                    hash = node.hashServedMap[hash]
+                }
+                block := node.blockMap[hash]
+                if node.stray {
+                    stream.Send(&protowire.KaspadResponse{
+                        Id: request.Id + 1000,
+                        Payload: &protowire.KaspadResponse_GetBlockResponse{
+                            GetBlockResponse: &protowire.GetBlockResponseMessage{Block: node.blockMap[node.chainList[0]]},
+                        },
+                    })
+                }
+                if block == nil {
+                    result.Error = &protowire.RPCError{Message: "block not found"}
+                } else {
+                    result.Block = &protowire.RpcBlock{Header: block.Header, VerboseData: block.VerboseData}
+                    if payload.GetBlockRequest.IncludeTransactions {
+                        result.Block.Transactions = block.Transactions
+                    }
+                }
+                response.Payload = &protowire.KaspadResponse_GetBlockResponse{GetBlockResponse: result}
+        }
+        err = stream.Send(response)
+        node.mutex.Unlock()
+        if err != nil {
+            return nil
+        }
+    }
+}
+
+////////////////////////////////
+// Add the chain block merging the block with the transactions, and notify it if the stream opened.
+func (node *fakeNodeType) addChainBlock(hash string, daaScore uint64, txIdList []string) {
+    node.mutex.Lock()
+    defer node.mutex.Unlock()
+    hashMerge := "m" + hash
+    txList := []*protowire.RpcTransaction{}
+    for _, txId := range txIdList {
+        txList = append(txList, &protowire.RpcTransaction{
+            Outputs: []*protowire.RpcTransactionOutput{{Amount: daaScore}},
+            VerboseData: &protowire.RpcTransactionVerboseData{TransactionId: txId},
+        })
+    }
+    hashParent := ""
+    if len(node.chainList) > 0 {
+        hashParent = node.chainList[len(node.chainList)-1]
+    }
+    node.blockMap[hashMerge] = &protowire.RpcBlock{
+        Header: &protowire.RpcBlockHeader{DaaScore: daaScore},
+        Transactions: txList,
+        VerboseData: &protowire.RpcBlockVerboseData{Hash: hashMerge},
+    }
+    node.blockMap[hash] = &protowire.RpcBlock{
+        Header: &protowire.RpcBlockHeader{DaaScore: daaScore},
+        VerboseData: &protowire.RpcBlockVerboseData{Hash: hash, SelectedParentHash: hashParent, MergeSetBluesHashes: []string{hashMerge}},
+    }
+    node.chainList = append(node.chainList, hash)
+    node.acceptedMap[hash] = txIdList
+    if node.stream == nil {
+        return
+    }
+    node.stream.Send(&protowire.KaspadResponse{
+        Payload: &protowire.KaspadResponse_VirtualChainChangedNotification{
+            VirtualChainChangedNotification: &protowire.VirtualChainChangedNotificationMessage{
+                AddedChainBlockHashes: []string{hash},
+                AcceptedTransactionIds: []*protowire.RpcAcceptedTransactionIds{{AcceptingBlockHash: hash, AcceptedTransactionIds: txIdList}},
+            },
+        },
+    })
+}
+
+////////////////////////////////
+func startFakeNode(t *testing.T, node *fakeNodeType) (grpc.DialOption) {
+    listener := bufconn.Listen(1024 * 1024)
+    server := grpc.NewServer()
+    protowire.RegisterRPCServer(server, node)
+    go server.Serve(listener)
+    t.Cleanup(server.Stop)
+    return grpc.WithContextDialer(func(ctx context.Context, target string) (net.Conn, error) {
+        return listener.DialContext(ctx)
+    })
+}
+
+////////////////////////////////
+func waitVspcList(t *testing.T, source *NodeSource, daaScoreStart uint64, lenVspc int) ([]storage.DataVspcType) {
+    for i := 0; i < 500; i ++ {
+        vspcList, _, err := source.GetVspcList(daaScoreStart, 100)
+        if (err == nil && len(vspcList) >= lenVspc) {
+            return vspcList
+        }
+        time.Sleep(10*time.Millisecond)
+    }
+    t.Fatalf("vspc not ingested, want %d", lenVspc)
+    return nil
+}
+
+////////////////////////////////
+func TestNodeSourceIngest(t *testing.T) {
+    node := &fakeNodeType{blockMap: map[string]*protowire.RpcBlock{}, acceptedMap: map[string][]string{}}
+    node.addChainBlock("h0", 100, []string{"txprev"})
+    node.addChainBlock("h1", 101, []string{"tx1b", "tx1a"})
+    node.addChainBlock("h2", 102, []string{"tx2"})
+    ctx, cancel := context.WithCancel(context.Background())
+    defer cancel()
+    source, err := NewNodeSource(ctx, "passthrough:///fake", "h0", nil, startFakeNode(t, node))
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    // The catch-up from the start hash, the start block is not ingested.
+    vspcList := waitVspcList(t, source, 100, 2)
+    if (len(vspcList) != 2 || vspcList[0].Hash != "h1" || vspcList[1].Hash != "h2" || vspcList[0].DaaScore != 101) {
+        t.Fatalf("vspc list invalid: %+v", vspcList)
+    }
+    if (len(vspcList[0].TxIdList) != 2 || vspcList[0].TxIdList[0] != "tx1a") {
+        t.Fatalf("tx id list not sorted: %v", vspcList[0].TxIdList)
+    }
+
+    // The notification after the catch-up.
+    node.addChainBlock("h3", 103, []string{"tx3"})
+    vspcList = waitVspcList(t, source, 101, 3)
+    if (vspcList[2].Hash != "h3" || source.GetDaaScoreTip() != 103) {
+        t.Fatalf("notification not applied: %+v", vspcList)
+    }
+
+    // The accepted transactions are cached, the ones before the first chain block are looked up.
+    txDataMap, _, err := source.GetTransactionDataMap([]storage.DataTransactionType{{TxId: "tx1a"}, {TxId: "tx3"}, {TxId: "txprev"}})
+    if err != nil {
+        t.Fatal(err)
+    }
+    if (txDataMap["tx1a"].Outputs[0].Amount != 101 || txDataMap["tx3"].Outputs[0].Amount != 103 || txDataMap["txprev"].Outputs[0].Amount != 100) {
+        t.Fatalf("transaction data invalid: %v", txDataMap)
+    }
+
+    // The transaction unknown is an error, never skipped.
+    _, _, err = source.GetTransactionDataMap([]storage.DataTransactionType{{TxId: "txunknown"}})
+    if err == nil {
+        t.Fatal("transaction unknown not reported")
+    }
+}
+
+////////////////////////////////
+func TestNotifyQueuePush(t *testing.T) {
+    queue := &notifyQueueType{
+        chChanged: make(chan *protowire.VirtualChainChangedNotificationMessage, 1),
+        chCatchUp: make(chan struct{}, 1),
+    }
+    done := make(chan struct{})
+    go func() {
+        for i := 0; i < 3; i ++ {
+            queue.push(&protowire.VirtualChainChangedNotificationMessage{})
+        }
+        close(done)
+    }()
+    select {
+        case <-done:
+        case <-time.After(time.Second):
+            t.Fatal("push blocked with the queue full")
+    }
+    if (len(queue.chChanged) != 1 || len(queue.chCatchUp) != 1) {
+        t.Fatalf("queue %d, catch-up %d", len(queue.chChanged), len(queue.chCatchUp))
+    }
+}
+
+////////////////////////////////
+// The response of another id is dropped, the block of another hash is rejected.
+func TestClientResponseMatch(t *testing.T) {
+    node := &fakeNodeType{blockMap: map[string]*protowire.RpcBlock{}, acceptedMap: map[string][]string{}}
+    node.addChainBlock("h0", 100, nil)
+    node.addChainBlock("h1", 101, []string{"tx1"})
+    ctx, cancel := context.WithCancel(context.Background())
+    defer cancel()
+    client, err := Dial(ctx, "passthrough:///fake", nil, startFakeNode(t, node))
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer client.Close()
+    node.mutex.Lock()
+    node.stray = true
+    node.mutex.Unlock()
+    for _, hash := range []string{"h1", "mh1", "h0"} {
+        block, err := getBlock(client, hash, true)
+        if err != nil {
+            t.Fatalf("%s: %v", hash, err)
+        }
+        if block.VerboseData.Hash != hash {
+            t.Fatalf("%s: block %s received", hash, block.VerboseData.Hash)
+        }
+    }
+    node.mutex.Lock()
+    node.stray = false
+    node.hashServedMap = map[string]string{"h1": "h0"}
+    node.mutex.Unlock()
+    _, err = getBlock(client, "h1", false)
+    if err == nil {
+        t.Fatal("block of another hash not rejected")
+    }
+}
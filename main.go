@@ -5,9 +5,12 @@ import (
 	"context"
 	"fmt"
 	"kasplex-executor/api"
+	"kasplex-executor/api/consensus"
 	"kasplex-executor/config"
 	"kasplex-executor/explorer"
+	"kasplex-executor/kaspad"
 	"kasplex-executor/storage"
+	"kasplex-executor/webhook"
 	"log"
 	"log/slog"
 	"net/http"
@@ -34,35 +37,22 @@ func main() {
 		os.Chdir(dir)
 	}
 
-	// Use the file lock for startup.
-	fLock := "./.lockExecutor"
-	lock, err := os.Create(fLock)
-	if err != nil {
-		log.Fatalln("main fatal:", err.Error())
+	// Run the command if specified, instead of the executor.
+	if len(os.Args) > 1 {
+		runCommand(os.Args[1], os.Args[2:])
+		return
 	}
-	defer os.Remove(fLock)
-	defer lock.Close()
-	err = syscall.Flock(int(lock.Fd()), syscall.LOCK_EX|syscall.LOCK_NB)
-	if err != nil {
-		log.Fatalln("main fatal:", err.Error())
-	}
-	defer syscall.Flock(int(lock.Fd()), syscall.LOCK_UN)
+
+	// Use the file lock for startup.
+	unlock := lockStartup()
+	defer unlock()
 
 	// Load config.
 	var cfg config.Config
 	config.Load(&cfg)
 
 	// Set the log level.
-	logOpt := &slog.HandlerOptions{Level: slog.LevelError}
-	if cfg.Debug == 3 {
-		logOpt = &slog.HandlerOptions{Level: slog.LevelDebug}
-	} else if cfg.Debug == 2 {
-		logOpt = &slog.HandlerOptions{Level: slog.LevelInfo}
-	} else if cfg.Debug == 1 {
-		logOpt = &slog.HandlerOptions{Level: slog.LevelWarn}
-	}
-	logHandler := slog.NewTextHandler(os.Stdout, logOpt)
-	slog.SetDefault(slog.New(logHandler))
+	setLogLevel(cfg.Debug)
 
 	// Set exit signal.
 	ctx, cancel := context.WithCancel(context.Background())
@@ -81,10 +71,29 @@ func main() {
 
 	// Init storage driver.
 	storage.Init(cfg.Cassandra, cfg.Rocksdb)
+	storage.SetSnapshotDir(cfg.Startup.SnapshotDir)
+
+	// Use the kaspad node as the data source if enabled, the node archive db by default.
+	var source storage.NodeSource = storage.NodeSourceCassa{}
+	if cfg.Kaspad.Enabled {
+		vspcList, err := storage.GetRuntimeVspcLast()
+		if err != nil {
+			log.Fatalln("main fatal:", err.Error())
+		}
+		source, err = kaspad.NewNodeSource(ctx, cfg.Kaspad.Host, cfg.Kaspad.Start, vspcList)
+		if err != nil {
+			log.Fatalln("main fatal:", err.Error())
+		}
+	}
+
+	// Deliver the webhooks of the events after each batch, before the explorer starts.
+	if cfg.Webhook.Enabled {
+		webhook.Init(ctx, cfg.Webhook)
+	}
 
 	// Init explorer if api server up.
 	if !down {
-		explorer.Init(ctx, wg, cfg.Startup, cfg.Testnet)
+		explorer.Init(ctx, wg, cfg.Startup, source, cfg.Testnet)
 		go explorer.Run()
 	}
 
@@ -97,8 +106,12 @@ func main() {
 		apiServer := api.NewServer(
 			cfg.Api.Port,
 			cfg.Api.AllowedOrigins,
+			cfg.Api.ReadyLagMax,
 		)
 
+		// Compare the checkpoint with the peer indexers if configured.
+		go consensus.Run(ctx, cfg.Api.Peers, time.Duration(cfg.Api.PeerInterval)*time.Second)
+
 		// Start server in goroutine
 		go func() {
 			if err := apiServer.Start(stop); err != nil && err != http.ErrServerClosed {
@@ -127,3 +140,36 @@ func main() {
 
 	slog.Info("Shutdown complete")
 }
+
+// //////////////////////////////
+// Lock the working directory, the local db is used by only one process.
+func lockStartup() func() {
+	fLock := "./.lockExecutor"
+	lock, err := os.Create(fLock)
+	if err != nil {
+		log.Fatalln("main fatal:", err.Error())
+	}
+	err = syscall.Flock(int(lock.Fd()), syscall.LOCK_EX|syscall.LOCK_NB)
+	if err != nil {
+		log.Fatalln("main fatal:", err.Error())
+	}
+	return func() {
+		syscall.Flock(int(lock.Fd()), syscall.LOCK_UN)
+		lock.Close()
+		os.Remove(fLock)
+	}
+}
+
+// //////////////////////////////
+func setLogLevel(debug int) {
+	logOpt := &slog.HandlerOptions{Level: slog.LevelError}
+	if debug == 3 {
+		logOpt = &slog.HandlerOptions{Level: slog.LevelDebug}
+	} else if debug == 2 {
+		logOpt = &slog.HandlerOptions{Level: slog.LevelInfo}
+	} else if debug == 1 {
+		logOpt = &slog.HandlerOptions{Level: slog.LevelWarn}
+	}
+	logHandler := slog.NewTextHandler(os.Stdout, logOpt)
+	slog.SetDefault(slog.New(logHandler))
+}
@@ -0,0 +1,25 @@
+
+////////////////////////////////
+package metrics
+
+////////////////////////////////
+// The scan phases: vspc, tx, op, st, exe, save_rocks_begin, save_state_cassa, save_opdata_cassa, save_rocks_commit, rollback, loop.
+var ScanSeconds = NewHistogram("kasplex_executor_scan_seconds", "Seconds of each scan phase.", BucketListSeconds, "phase")
+
+////////////////////////////////
+var DaaScore = NewGauge("kasplex_executor_daascore", "DaaScore of the last batch executed.")
+var DaaScoreTip = NewGauge("kasplex_executor_daascore_tip", "DaaScore of the last chain block in the node source.")
+var OpScoreLast = NewGauge("kasplex_executor_opscore_last", "OpScore of the last op executed.")
+var Synced = NewGauge("kasplex_executor_synced", "1 if the executor is synced with the node source.")
+var RollbackTotal = NewCounter("kasplex_executor_rollback_total", "Batches rolled back by the vspc reorg.")
+var ScanErrorTotal = NewCounter("kasplex_executor_scan_error_total", "Scan loops failed.")
+
+////////////////////////////////
+var OpTotal = NewCounter("kasplex_executor_op_total", "Ops executed by the op name, the result and the error if rejected.", "op", "result", "error")
+
+////////////////////////////////
+var CassaBatchRetryTotal = NewCounter("kasplex_executor_cassandra_batch_retry_total", "Cassandra batch executions or queries retried, by the kind.", "kind")
+
+////////////////////////////////
+var ApiRequestTotal = NewCounter("kasplex_executor_api_requests_total", "API requests by the route, the method and the status.", "route", "method", "status")
+var ApiRequestSeconds = NewHistogram("kasplex_executor_api_request_seconds", "Seconds of the API requests by the route.", BucketListSeconds, "route")
@@ -0,0 +1,214 @@
+
+////////////////////////////////
+package metrics
+
+import (
+    "io"
+    "sort"
+    "sync"
+    "strconv"
+    "strings"
+)
+
+////////////////////////////////
+const kindCounter = "counter"
+const kindGauge = "gauge"
+const kindHistogram = "histogram"
+
+////////////////////////////////
+// Buckets in seconds of the scan phases and the api requests.
+var BucketListSeconds = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60}
+
+////////////////////////////////
+type seriesType struct {
+    labelValueList []string
+    value float64
+    countList []uint64
+    sum float64
+    count uint64
+}
+
+////////////////////////////////
+// The metric family with the label names, each series is keyed by the label values.
+type Metric struct {
+    name string
+    help string
+    kind string
+    labelList []string
+    bucketList []float64
+    mutex sync.Mutex
+    seriesMap map[string]*seriesType
+}
+
+////////////////////////////////
+var registry = struct {
+    mutex sync.Mutex
+    metricList []*Metric
+}{}
+
+////////////////////////////////
+func newMetric(name string, help string, kind string, bucketList []float64, labelList []string) (*Metric) {
+    metric := &Metric{
+        name: name,
+        help: help,
+        kind: kind,
+        labelList: labelList,
+        bucketList: bucketList,
+        seriesMap: map[string]*seriesType{},
+    }
+    // The series without label is written even if never updated.
+    if len(labelList) <= 0 {
+        metric.getSeries(nil)
+    }
+    registry.mutex.Lock()
+    registry.metricList = append(registry.metricList, metric)
+    registry.mutex.Unlock()
+    return metric
+}
+
+////////////////////////////////
+func NewCounter(name string, help string, labelList ...string) (*Metric) {
+    return newMetric(name, help, kindCounter, nil, labelList)
+}
+
+////////////////////////////////
+func NewGauge(name string, help string, labelList ...string) (*Metric) {
+    return newMetric(name, help, kindGauge, nil, labelList)
+}
+
+////////////////////////////////
+func NewHistogram(name string, help string, bucketList []float64, labelList ...string) (*Metric) {
+    return newMetric(name, help, kindHistogram, bucketList, labelList)
+}
+
+////////////////////////////////
+// Get the series by the label values, the caller must hold the lock after created.
+func (metric *Metric) getSeries(labelValueList []string) (*seriesType) {
+    key := strings.Join(labelValueList, "\xff")
+    series := metric.seriesMap[key]
+    if series == nil {
+        series = &seriesType{
+            labelValueList: append([]string{}, labelValueList...),
+        }
+        if metric.kind == kindHistogram {
+            series.countList = make([]uint64, len(metric.bucketList))
+        }
+        metric.seriesMap[key] = series
+    }
+    return series
+}
+
+////////////////////////////////
+// Add the value to the counter or gauge.
+func (metric *Metric) Add(value float64, labelValueList ...string) {
+    metric.mutex.Lock()
+    defer metric.mutex.Unlock()
+    metric.getSeries(labelValueList).value += value
+}
+
+////////////////////////////////
+func (metric *Metric) Inc(labelValueList ...string) {
+    metric.Add(1, labelValueList...)
+}
+
+////////////////////////////////
+// Set the value of the gauge.
+func (metric *Metric) Set(value float64, labelValueList ...string) {
+    metric.mutex.Lock()
+    defer metric.mutex.Unlock()
+    metric.getSeries(labelValueList).value = value
+}
+
+////////////////////////////////
+// Observe the value in the histogram.
+func (metric *Metric) Observe(value float64, labelValueList ...string) {
+    metric.mutex.Lock()
+    defer metric.mutex.Unlock()
+    series := metric.getSeries(labelValueList)
+    for i, bucket := range metric.bucketList {
+        if value <= bucket {
+            series.countList[i] ++
+        }
+    }
+    series.sum += value
+    series.count ++
+}
+
+////////////////////////////////
+// Observe the milliseconds in the histogram of seconds.
+func (metric *Metric) ObserveMts(mts int64, labelValueList ...string) {
+    metric.Observe(float64(mts)/1000, labelValueList...)
+}
+
+////////////////////////////////
+// Write all the metrics in the prometheus text format.
+func Write(w io.Writer) (error) {
+    registry.mutex.Lock()
+    metricList := append([]*Metric{}, registry.metricList...)
+    registry.mutex.Unlock()
+    builder := &strings.Builder{}
+    for _, metric := range metricList {
+        metric.write(builder)
+    }
+    _, err := io.WriteString(w, builder.String())
+    return err
+}
+
+////////////////////////////////
+func (metric *Metric) write(builder *strings.Builder) {
+    metric.mutex.Lock()
+    defer metric.mutex.Unlock()
+    builder.WriteString("# HELP " + metric.name + " " + metric.help + "\n")
+    builder.WriteString("# TYPE " + metric.name + " " + metric.kind + "\n")
+    keyList := make([]string, 0, len(metric.seriesMap))
+    for key := range metric.seriesMap {
+        keyList = append(keyList, key)
+    }
+    sort.Strings(keyList)
+    for _, key := range keyList {
+        series := metric.seriesMap[key]
+        labels := formatLabels(metric.labelList, series.labelValueList, "")
+        if metric.kind != kindHistogram {
+            builder.WriteString(metric.name + labels + " " + formatValue(series.value) + "\n")
+            continue
+        }
+        for i, bucket := range metric.bucketList {
+            builder.WriteString(metric.name + "_bucket" + formatLabels(metric.labelList, series.labelValueList, formatValue(bucket)) + " " + strconv.FormatUint(series.countList[i], 10) + "\n")
+        }
+        builder.WriteString(metric.name + "_bucket" + formatLabels(metric.labelList, series.labelValueList, "+Inf") + " " + strconv.FormatUint(series.count, 10) + "\n")
+        builder.WriteString(metric.name + "_sum" + labels + " " + formatValue(series.sum) + "\n")
+        builder.WriteString(metric.name + "_count" + labels + " " + strconv.FormatUint(series.count, 10) + "\n")
+    }
+}
+
+////////////////////////////////
+// Format the labels as {name="value",...}, with the "le" label of the histogram bucket if not empty.
+func formatLabels(labelList []string, labelValueList []string, le string) (string) {
+    pairList := []string{}
+    for i, label := range labelList {
+        value := ""
+        if i < len(labelValueList) {
+            value = labelValueList[i]
+        }
+        pairList = append(pairList, label + "=\"" + escapeLabel(value) + "\"")
+    }
+    if le != "" {
+        pairList = append(pairList, "le=\"" + le + "\"")
+    }
+    if len(pairList) <= 0 {
+        return ""
+    }
+    return "{" + strings.Join(pairList, ",") + "}"
+}
+
+////////////////////////////////
+func escapeLabel(value string) (string) {
+    value = strings.ReplaceAll(value, "\\", "\\\\")
+    value = strings.ReplaceAll(value, "\"", "\\\"")
+    return strings.ReplaceAll(value, "\n", "\\n")
+}
+
+////////////////////////////////
+func formatValue(value float64) (string) {
+    return strconv.FormatFloat(value, 'g', -1, 64)
+}
@@ -27,8 +27,8 @@ type OpMethod interface {
 ////////////////////////////////
 var P_Registered = map[string]bool{}
 var Op_Registered = map[string]bool{}
-var Method_Registered = map[string]OpMethod{}
-var OpRecycle_Registered = map[string]bool{}
+var Method_Registered = map[string]OpMethod{}  // keyed by p+"_"+op, the same op name is registered by each p.
+var OpRecycle_Registered = map[string]bool{}  // keyed by p+"_"+op.
 
 ////////////////////////////////
 var TickIgnored = map[string]bool{
@@ -80,30 +80,16 @@ func ApplyTickReserved(reservedList []string) {
 ////////////////////////////////
 func PrepareStateBatch(opDataList []storage.DataOperationType) (storage.DataStateMapType, int64, error) {
     mtss := time.Now().UnixMilli()
-    stateMap := storage.DataStateMapType{
-        StateTokenMap: make(map[string]*storage.StateTokenType),
-        StateBalanceMap: make(map[string]*storage.StateBalanceType),
-        StateMarketMap: make(map[string]*storage.StateMarketType),
-        // StateXxx ...
-    }
+    stateMap := storage.NewDataStateMap()
     for _, opData := range opDataList{
         for _, opScript := range opData.OpScript{
-            Method_Registered[opScript.Op].PrepareStateKey(opScript, stateMap)
+            Method_Registered[opScript.P+"_"+opScript.Op].PrepareStateKey(opScript, stateMap)
         }
     }
-    _, err := storage.GetStateTokenMap(stateMap.StateTokenMap)
-    if err != nil {
-        return storage.DataStateMapType{}, 0, err
-    }
-    _, err = storage.GetStateBalanceMap(stateMap.StateBalanceMap)
-    if err != nil {
-        return storage.DataStateMapType{}, 0, err
-    }
-    _, err = storage.GetStateMarketMap(stateMap.StateMarketMap)
+    _, err := storage.GetStateMap(stateMap)
     if err != nil {
         return storage.DataStateMapType{}, 0, err
     }
-    // GetStateXxx ...
     return stateMap, time.Now().UnixMilli() - mtss, nil
 }
 
@@ -129,7 +115,7 @@ func ExecuteBatch(opDataList []storage.DataOperationType, stateMap storage.DataS
         for iScript, opScript := range opData.OpScript{
             opData.OpAccept = 0
             opData.OpError = ""
-            err := Method_Registered[opScript.Op].Do(iScript, opData, stateMap, testnet)
+            err := Method_Registered[opScript.P+"_"+opScript.Op].Do(iScript, opData, stateMap, testnet)
             if err != nil {
                 return storage.DataRollbackType{}, 0, err
             }
@@ -151,136 +137,80 @@ func ExecuteBatch(opDataList []storage.DataOperationType, stateMap storage.DataS
             opData.OpError = opError
         }
         if opData.OpAccept == 1 {
-            cpHeader := strconv.FormatUint(opData.OpScore,10) +","+ opData.TxId +","+ opData.BlockAccept +","+ opData.OpScript[0].P +","+ opData.OpScript[0].Op
-            sum := blake2b.Sum256([]byte(cpHeader))
-            cpHeader = fmt.Sprintf("%064x", string(sum[:]))
-            cpState := strings.Join(opData.StAfter, ";")
-            sum = blake2b.Sum256([]byte(cpState))
-            cpState = fmt.Sprintf("%064x", string(sum[:]))
-            sum = blake2b.Sum256([]byte(checkpointLast + cpHeader + cpState))
-            opData.Checkpoint = fmt.Sprintf("%064x", string(sum[:]))
+            opData.OpEvent = GetOpEvent(opData.OpScript[0])
+            opData.Checkpoint = MakeCheckpoint(checkpointLast, opData.OpScore, opData.TxId, opData.BlockAccept, opData.OpScript[0].P, opData.OpScript[0].Op, opData.StAfter)
             checkpointLast = opData.Checkpoint
         }
         rollback.OpScoreLast = opData.OpScore
         rollback.OpScoreList = append(rollback.OpScoreList, opData.OpScore)
         rollback.TxIdList = append(rollback.TxIdList, opData.TxId)
     }
+    // The state not prepared as its key unknown before executed, such as the token minted, is deleted by the rollback.
+    storage.MarkDataStateMapCreated(stateMap, &rollback.StateMapBefore)
     rollback.CheckpointAfter = checkpointLast
     return rollback, time.Now().UnixMilli() - mtss, nil
 }
 
 ////////////////////////////////
-func MakeStLineToken(key string, stToken *storage.StateTokenType, isDeploy bool) (string) {
-    stLine := storage.KeyPrefixStateToken + key
-    if stToken == nil {
-        return stLine
+// Get the event of the op accepted, the send reclaiming the order to the lister is the cancel.
+func GetOpEvent(opScript *storage.DataScriptType) (string) {
+    if opScript.Op != "send" {
+        return ""
     }
-    stLine += ","
-    strDec := strconv.Itoa(stToken.Dec)
-    opScore := stToken.OpMod
-    if isDeploy {
-        opScore = stToken.OpAdd
+    if (opScript.Price == "0" && opScript.To == opScript.From) {
+        return storage.OpEventCancel
     }
-    strOpscore := strconv.FormatUint(opScore, 10)
+    return storage.OpEventTrade
+}
+
+////////////////////////////////
+// Chain the checkpoint with the header and the state after of the accepted op.
+func MakeCheckpoint(checkpointLast string, opScore uint64, txId string, blockAccept string, p string, op string, stAfter []string) (string) {
+    cpHeader := strconv.FormatUint(opScore,10) +","+ txId +","+ blockAccept +","+ p +","+ op
+    sum := blake2b.Sum256([]byte(cpHeader))
+    cpHeader = fmt.Sprintf("%064x", string(sum[:]))
+    cpState := strings.Join(stAfter, ";")
+    sum = blake2b.Sum256([]byte(cpState))
+    cpState = fmt.Sprintf("%064x", string(sum[:]))
+    sum = blake2b.Sum256([]byte(checkpointLast + cpHeader + cpState))
+    return fmt.Sprintf("%064x", string(sum[:]))
+}
+
+////////////////////////////////
+// The line of the state in the checkpoint is made by its kind registered in the storage.
+func MakeStLineToken(key string, stToken *storage.StateTokenType, isDeploy bool) (string) {
     if isDeploy {
-        stLine += stToken.Max + ","
-        stLine += stToken.Lim + ","
-        stLine += stToken.Pre + ","
-        stLine += strDec + ","
-        stLine += stToken.From + ","
-        stLine += stToken.To + ","
+        return storage.MakeStLineTokenDeploy(key, stToken)
     }
-    stLine += stToken.Minted + ","
-    stLine += strOpscore
-    return stLine
+    return storage.StateKindToken.MakeStLine(key, stToken)
 }
 func AppendStLineToken(stLine []string, key string, stToken *storage.StateTokenType, isDeploy bool, isAfter bool) ([]string) {
-    keyFull := storage.KeyPrefixStateToken + key
-    iExists := -1
-    list := []string{}
-    for i, line := range stLine {
-        list = strings.SplitN(line, ",", 2)
-        if list[0] == keyFull {
-            iExists = i
-            break
-        }
-    }
-    if iExists < 0 {
-        return append(stLine, MakeStLineToken(key, stToken, isDeploy))
-    }
-    if isAfter {
-        stLine[iExists] = MakeStLineToken(key, stToken, isDeploy)
-    }
-    return stLine
+    return storage.AppendStLine(stLine, storage.KeyPrefixStateToken+key, MakeStLineToken(key, stToken, isDeploy), isAfter)
 }
 
 ////////////////////////////////
-func MakeStLineBalance(key string, stBalance *storage.StateBalanceType) (string) {
-    stLine := storage.KeyPrefixStateBalance + key
-    if stBalance == nil {
-        return stLine
-    }
-    stLine += ","
-    strDec := strconv.Itoa(stBalance.Dec)
-    strOpscore := strconv.FormatUint(stBalance.OpMod, 10)
-    stLine += strDec + ","
-    stLine += stBalance.Balance + ","
-    stLine += stBalance.Locked + ","
-    stLine += strOpscore
-    return stLine
-}
 func AppendStLineBalance(stLine []string, key string, stBalance *storage.StateBalanceType, isAfter bool) ([]string) {
-    keyFull := storage.KeyPrefixStateBalance + key
-    iExists := -1
-    list := []string{}
-    for i, line := range stLine {
-        list = strings.SplitN(line, ",", 2)
-        if list[0] == keyFull {
-            iExists = i
-            break
-        }
-    }
-    if iExists < 0 {
-        return append(stLine, MakeStLineBalance(key, stBalance))
-    }
-    if isAfter {
-        stLine[iExists] = MakeStLineBalance(key, stBalance)
-    }
-    return stLine
+    return storage.StateKindBalance.AppendStLine(stLine, key, stBalance, isAfter)
 }
 
 ////////////////////////////////
-func MakeStLineMarket(key string, stMarket *storage.StateMarketType) (string) {
-    stLine := storage.KeyPrefixStateMarket + key
-    if stMarket == nil {
-        return stLine
-    }
-    stLine += ","
-    strOpscore := strconv.FormatUint(stMarket.OpAdd, 10)
-    stLine += stMarket.UAddr + ","
-    stLine += stMarket.UAmt + ","
-    stLine += stMarket.TAmt + ","
-    stLine += strOpscore
-    return stLine
-}
 func AppendStLineMarket(stLine []string, key string, stMarket *storage.StateMarketType, isAfter bool) ([]string) {
-    keyFull := storage.KeyPrefixStateMarket + key
-    iExists := -1
-    list := []string{}
-    for i, line := range stLine {
-        list = strings.SplitN(line, ",", 2)
-        if list[0] == keyFull {
-            iExists = i
-            break
-        }
-    }
-    if iExists < 0 {
-        return append(stLine, MakeStLineMarket(key, stMarket))
-    }
-    if isAfter {
-        stLine[iExists] = MakeStLineMarket(key, stMarket)
-    }
-    return stLine
+    return storage.StateKindMarket.AppendStLine(stLine, key, stMarket, isAfter)
+}
+
+////////////////////////////////
+func AppendStLineBlacklist(stLine []string, key string, stBlacklist *storage.StateBlacklistType, isAfter bool) ([]string) {
+    return storage.StateKindBlacklist.AppendStLine(stLine, key, stBlacklist, isAfter)
+}
+
+////////////////////////////////
+func AppendStLineCollection(stLine []string, key string, stCollection *storage.StateCollectionType, isAfter bool) ([]string) {
+    return storage.StateKindCollection.AppendStLine(stLine, key, stCollection, isAfter)
+}
+
+////////////////////////////////
+func AppendStLineNft(stLine []string, key string, stNft *storage.StateNftType, isAfter bool) ([]string) {
+    return storage.StateKindNft.AppendStLine(stLine, key, stNft, isAfter)
 }
 
 ////////////////////////////////
@@ -338,6 +268,35 @@ func ValidateTick(tick *string) (bool) {
     return true
 }
 ////////////////////////////////
+// The tick of the KRC-721 collection, 1-10 letters or digits.
+func ValidateTickCollection(tick *string) (bool) {
+    *tick = strings.ToUpper(*tick)
+    lenTick := len(*tick)
+    if (lenTick < 1 || lenTick > 10) {
+        return false
+    }
+    for i := 0; i < lenTick; i++ {
+        if (((*tick)[i] < 65 || (*tick)[i] > 90) && ((*tick)[i] < 48 || (*tick)[i] > 57)) {
+            return false
+        }
+    }
+    return true
+}
+////////////////////////////////
+// The base uri of the KRC-721 collection, printable ascii without the separators of the state line.
+func ValidateBuri(buri *string) (bool) {
+    lenBuri := len(*buri)
+    if (lenBuri < 1 || lenBuri > 256) {
+        return false
+    }
+    for i := 0; i < lenBuri; i++ {
+        if ((*buri)[i] < 33 || (*buri)[i] > 126 || (*buri)[i] == ',' || (*buri)[i] == ';') {
+            return false
+        }
+    }
+    return true
+}
+////////////////////////////////
 func ValidateAmount(amount *string) (bool) {
     if *amount == "" {
         *amount = "0"
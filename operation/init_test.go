@@ -0,0 +1,125 @@
+
+////////////////////////////////
+package operation
+
+import (
+    "strconv"
+    "testing"
+    "kasplex-executor/config"
+    "kasplex-executor/storage"
+)
+
+////////////////////////////////
+const addrTestA = "kaspa:qzrsq2mfj9sf7uye3u5q7juejzlr0axk5jz9fpg4vqe76erdyvxxze84k9nk7"
+const addrTestB = "kaspa:qq8guq855gxkfrj2w25skwgj7cp4hy08x6a8mz70tdtmgv5p2ngwqxpj4cknc"
+const addrTestC = "kaspa:qr8vt54764aaddejhjfwtsh07jcjr49v38vrw2vtmxxtle7j2uepynwy57ufg"
+const daaScoreTestGated = uint64(110165000)
+const scriptSigTest = "207e4875888208c67d59e404231d02b1f1e87f0972afdc98e0e1f139ada5abd132ac"
+
+////////////////////////////////
+// The ops executed in batches through the pipeline, the state is saved to the local db and prepared from it for the next batch.
+type scenarioTest struct {
+    t *testing.T
+    daaScore uint64
+    checkpoint string
+    rollbackList []storage.DataRollbackType
+}
+
+////////////////////////////////
+func newScenarioTest(t *testing.T) (*scenarioTest) {
+    storage.InitRocks(config.RocksConfig{Path: t.TempDir()})
+    return &scenarioTest{t: t, daaScore: daaScoreTestGated}
+}
+
+////////////////////////////////
+// Make the op data list of the scripts in the next daaScore, one op each with the fee enough.
+func (scenario *scenarioTest) makeOpDataList(scriptList []storage.DataScriptType) ([]storage.DataOperationType) {
+    t := scenario.t
+    t.Helper()
+    opDataList := []storage.DataOperationType{}
+    for i := range scriptList {
+        opScript := scriptList[i]
+        method := Method_Registered[opScript.P+"_"+opScript.Op]
+        if (method == nil || !method.Validate(&opScript, scenario.daaScore, false)) {
+            t.Fatalf("script invalid: %+v", scriptList[i])
+        }
+        opDataList = append(opDataList, storage.DataOperationType{
+            TxId: "tx" + strconv.FormatUint(scenario.daaScore, 10) + strconv.Itoa(i),
+            DaaScore: scenario.daaScore,
+            BlockAccept: "block" + strconv.FormatUint(scenario.daaScore, 10),
+            Fee: 1000000000000,
+            FeeLeast: method.FeeLeast(scenario.daaScore),
+            MtsAdd: int64(scenario.daaScore),
+            OpScore: scenario.daaScore*10000 + uint64(i),
+            OpScript: []*storage.DataScriptType{&opScript},
+            ScriptSig: scriptSigTest,
+            SsInfo: &storage.DataStatsType{},
+        })
+    }
+    scenario.daaScore ++
+    return opDataList
+}
+
+////////////////////////////////
+// Execute the scripts as the next batch and save it, the ops and the state after are returned.
+func (scenario *scenarioTest) run(scriptList ...storage.DataScriptType) ([]storage.DataOperationType, storage.DataStateMapType) {
+    t := scenario.t
+    t.Helper()
+    opDataList := scenario.makeOpDataList(scriptList)
+    stateMap, _, err := PrepareStateBatch(opDataList)
+    if err != nil {
+        t.Fatal(err)
+    }
+    rollback, _, err := ExecuteBatch(opDataList, stateMap, scenario.checkpoint, false)
+    if err != nil {
+        t.Fatal(err)
+    }
+    _, err = storage.SaveOpStateBatch(opDataList, stateMap, rollback, nil)
+    if err != nil {
+        t.Fatal(err)
+    }
+    scenario.checkpoint = rollback.CheckpointAfter
+    scenario.rollbackList = append(scenario.rollbackList, rollback)
+    return opDataList, stateMap
+}
+
+////////////////////////////////
+// Roll back the last batch saved.
+func (scenario *scenarioTest) rollback() {
+    t := scenario.t
+    t.Helper()
+    lenRollback := len(scenario.rollbackList) - 1
+    _, err := storage.RollbackOpStateBatch(scenario.rollbackList[lenRollback])
+    if err != nil {
+        t.Fatal(err)
+    }
+    scenario.checkpoint = scenario.rollbackList[lenRollback].CheckpointBefore
+    scenario.rollbackList = scenario.rollbackList[:lenRollback]
+}
+
+////////////////////////////////
+// Load the state of the keys the scripts prepare from the local db, without executing them.
+func (scenario *scenarioTest) load(scriptList ...storage.DataScriptType) (storage.DataStateMapType) {
+    t := scenario.t
+    t.Helper()
+    stateMap, _, err := PrepareStateBatch(scenario.makeOpDataList(scriptList))
+    if err != nil {
+        t.Fatal(err)
+    }
+    return stateMap
+}
+
+////////////////////////////////
+// Check the accepted or the error of each op in the batch.
+func checkOpResult(t *testing.T, opDataList []storage.DataOperationType, wantList ...string) {
+    t.Helper()
+    for i, opData := range opDataList {
+        got := opData.OpError
+        if opData.OpAccept == 1 {
+            got = "accepted"
+        }
+        if got != wantList[i] {
+            t.Errorf("op %d %s: %q, want %q", i, opData.OpScript[0].Op, got, wantList[i])
+        }
+    }
+}
@@ -0,0 +1,127 @@
+
+////////////////////////////////
+package operation
+
+import (
+    "kasplex-executor/misc"
+    "kasplex-executor/storage"
+)
+
+////////////////////////////////
+type OpMethodBlacklist struct {}
+
+////////////////////////////////
+func init() {
+    pName := "KRC-20"
+    opName := "blacklist"
+    P_Registered[pName] = true
+    Op_Registered[opName] = true
+    Method_Registered[pName+"_"+opName] = new(OpMethodBlacklist)
+}
+
+////////////////////////////////
+func (opMethodBlacklist OpMethodBlacklist) FeeLeast(daaScore uint64) (uint64) {
+    return 0
+}
+
+////////////////////////////////
+func (opMethodBlacklist OpMethodBlacklist) ScriptCollectEx(index int, script *storage.DataScriptType, txData *storage.DataTransactionType, testnet bool) {}
+
+////////////////////////////////
+// The mod is "add" or "remove", the address blacklisted is the to.
+func (opMethodBlacklist OpMethodBlacklist) Validate(script *storage.DataScriptType, daaScore uint64, testnet bool) (bool) {
+    if (!testnet && daaScore < 110165000) {
+        return false
+    }
+    if (script.From == "" || script.To == "" || script.P != "KRC-20" || !ValidateTick(&script.Tick)) {
+        return false
+    }
+    if (script.Mod != "add" && script.Mod != "remove") {
+        return false
+    }
+    script.Amt = ""
+    script.Max = ""
+    script.Lim = ""
+    script.Pre = ""
+    script.Dec = ""
+    script.Utxo = ""
+    script.Price = ""
+    script.Buri = ""
+    script.TokenId = ""
+    return true
+}
+
+////////////////////////////////
+func (opMethodBlacklist OpMethodBlacklist) PrepareStateKey(opScript *storage.DataScriptType, stateMap storage.DataStateMapType) {
+    stateMap.StateTokenMap[opScript.Tick] = nil
+    stateMap.StateBlacklistMap[opScript.Tick+"_"+opScript.To] = nil
+}
+
+////////////////////////////////
+func (opMethodBlacklist OpMethodBlacklist) Do(index int, opData *storage.DataOperationType, stateMap storage.DataStateMapType, testnet bool) (error) {
+    opScript := opData.OpScript[index]
+    ////////////////////////////////
+    if stateMap.StateTokenMap[opScript.Tick] == nil {
+        opData.OpAccept = -1
+        opData.OpError = "tick not found"
+        return nil
+    }
+    if stateMap.StateTokenMap[opScript.Tick].Mod != storage.TokenModIssue {
+        opData.OpAccept = -1
+        opData.OpError = "mode invalid"
+        return nil
+    }
+    if stateMap.StateTokenMap[opScript.Tick].Owner != opScript.From {
+        opData.OpAccept = -1
+        opData.OpError = "no ownership"
+        return nil
+    }
+    if (opScript.From == opScript.To || !misc.VerifyAddr(opScript.To, testnet)) {
+        opData.OpAccept = -1
+        opData.OpError = "address invalid"
+        return nil
+    }
+    ////////////////////////////////
+    keyBlacklist := opScript.Tick +"_"+ opScript.To
+    stBlacklist := stateMap.StateBlacklistMap[keyBlacklist]
+    if (opScript.Mod == "add" && stBlacklist != nil) {
+        opData.OpAccept = -1
+        opData.OpError = "blacklist existed"
+        return nil
+    }
+    if (opScript.Mod == "remove" && stBlacklist == nil) {
+        opData.OpAccept = -1
+        opData.OpError = "blacklist not found"
+        return nil
+    }
+    ////////////////////////////////
+    opData.StBefore = nil
+    opData.StBefore = AppendStLineBlacklist(opData.StBefore, keyBlacklist, stBlacklist, false)
+    ////////////////////////////////
+    if opScript.Mod == "add" {
+        stBlacklist = &storage.StateBlacklistType{
+            Tick: opScript.Tick,
+            Address: opScript.To,
+            OpAdd: opData.OpScore,
+        }
+    } else {
+        stBlacklist = nil
+    }
+    stateMap.StateBlacklistMap[keyBlacklist] = stBlacklist
+    ////////////////////////////////
+    opData.SsInfo.TickAffc = AppendSsInfoTickAffc(opData.SsInfo.TickAffc, opScript.Tick, 0)
+    ////////////////////////////////
+    opData.StAfter = nil
+    opData.StAfter = AppendStLineBlacklist(opData.StAfter, keyBlacklist, stBlacklist, true)
+    ////////////////////////////////
+    opData.OpAccept = 1
+    return nil
+}
+
+////////////////////////////////
+/*func (opMethodBlacklist OpMethodBlacklist) UnDo() (error) {
+    // ...
+    return nil
+}*/
+
+// ...
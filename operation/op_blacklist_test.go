@@ -0,0 +1,116 @@
+
+////////////////////////////////
+package operation
+
+import (
+    "testing"
+    "kasplex-executor/misc"
+    "kasplex-executor/storage"
+)
+
+////////////////////////////////
+func TestOpBlacklistValidate(t *testing.T) {
+    testList := []struct{
+        name string
+        script storage.DataScriptType
+        daaScore uint64
+        testnet bool
+        want bool
+    }{
+        {"accept add", storage.DataScriptType{P: "KRC-20", Op: "blacklist", From: addrTestA, To: addrTestB, Tick: "abcd", Mod: "add"}, daaScoreTestGated, false, true},
+        {"accept remove", storage.DataScriptType{P: "KRC-20", Op: "blacklist", From: addrTestA, To: addrTestB, Tick: "ABCD", Mod: "remove"}, daaScoreTestGated, false, true},
+        {"accept testnet before the gate", storage.DataScriptType{P: "KRC-20", Op: "blacklist", From: addrTestA, To: addrTestB, Tick: "ABCD", Mod: "add"}, 1, true, true},
+        {"reject mainnet before the gate", storage.DataScriptType{P: "KRC-20", Op: "blacklist", From: addrTestA, To: addrTestB, Tick: "ABCD", Mod: "add"}, daaScoreTestGated-1, false, false},
+        {"reject mod", storage.DataScriptType{P: "KRC-20", Op: "blacklist", From: addrTestA, To: addrTestB, Tick: "ABCD", Mod: "issue"}, daaScoreTestGated, false, false},
+        {"reject no mod", storage.DataScriptType{P: "KRC-20", Op: "blacklist", From: addrTestA, To: addrTestB, Tick: "ABCD"}, daaScoreTestGated, false, false},
+        {"reject no to", storage.DataScriptType{P: "KRC-20", Op: "blacklist", From: addrTestA, Tick: "ABCD", Mod: "add"}, daaScoreTestGated, false, false},
+        {"reject p", storage.DataScriptType{P: "KRC-721", Op: "blacklist", From: addrTestA, To: addrTestB, Tick: "ABCD", Mod: "add"}, daaScoreTestGated, false, false},
+    }
+    for _, test := range testList {
+        script := test.script
+        got := OpMethodBlacklist{}.Validate(&script, test.daaScore, test.testnet)
+        if got != test.want {
+            t.Errorf("%s: validate = %v, want %v", test.name, got, test.want)
+        }
+    }
+}
+
+////////////////////////////////
+// The address blacklisted by the owner can't transfer or list, but receives, and the blacklist removed unblocks it.
+func TestOpBlacklistBlocks(t *testing.T) {
+    scenario := newScenarioTest(t)
+    uAddr, _ := misc.MakeP2shKasplex(scriptSigTest, "", `{"p":"krc-20","op":"send","tick":"abcd"}`, false)
+    opDataList, _ := scenario.run(
+        storage.DataScriptType{P: "KRC-20", Op: "deploy", From: addrTestA, Tick: "ABCD", Max: "1000", Mod: "issue"},
+        storage.DataScriptType{P: "KRC-20", Op: "deploy", From: addrTestA, Tick: "WXYZ", Max: "1000", Lim: "10"},
+        storage.DataScriptType{P: "KRC-20", Op: "issue", From: addrTestA, To: addrTestB, Tick: "ABCD", Amt: "500"},
+    )
+    checkOpResult(t, opDataList, "accepted", "accepted", "accepted")
+
+    opDataList, stateMap := scenario.run(
+        storage.DataScriptType{P: "KRC-20", Op: "blacklist", From: addrTestB, To: addrTestC, Tick: "ABCD", Mod: "add"},
+        storage.DataScriptType{P: "KRC-20", Op: "blacklist", From: addrTestA, To: addrTestA, Tick: "ABCD", Mod: "add"},
+        storage.DataScriptType{P: "KRC-20", Op: "blacklist", From: addrTestA, To: addrTestB, Tick: "WXYZ", Mod: "add"},
+        storage.DataScriptType{P: "KRC-20", Op: "blacklist", From: addrTestA, To: addrTestB, Tick: "ABCD", Mod: "remove"},
+        storage.DataScriptType{P: "KRC-20", Op: "blacklist", From: addrTestA, To: addrTestB, Tick: "ABCD", Mod: "add"},
+        storage.DataScriptType{P: "KRC-20", Op: "blacklist", From: addrTestA, To: addrTestB, Tick: "ABCD", Mod: "add"},
+    )
+    checkOpResult(t, opDataList, "no ownership", "address invalid", "mode invalid", "blacklist not found", "accepted", "blacklist existed")
+    if stateMap.StateBlacklistMap["ABCD_"+addrTestB] == nil {
+        t.Fatal("blacklist not added")
+    }
+
+    opDataList, stateMap = scenario.run(
+        storage.DataScriptType{P: "KRC-20", Op: "transfer", From: addrTestB, To: addrTestC, Tick: "ABCD", Amt: "10"},
+        storage.DataScriptType{P: "KRC-20", Op: "list", From: addrTestB, Tick: "ABCD", Amt: "100", Utxo: "txlist_"+uAddr+"_100000"},
+        storage.DataScriptType{P: "KRC-20", Op: "issue", From: addrTestA, To: addrTestB, Tick: "ABCD", Amt: "10"},
+    )
+    checkOpResult(t, opDataList, "address blacklisted", "address blacklisted", "accepted")
+    if stateMap.StateBalanceMap[addrTestB+"_ABCD"].Balance != "510" {
+        t.Fatalf("balance of the address blacklisted = %+v", stateMap.StateBalanceMap[addrTestB+"_ABCD"])
+    }
+
+    opDataList, _ = scenario.run(
+        storage.DataScriptType{P: "KRC-20", Op: "blacklist", From: addrTestA, To: addrTestB, Tick: "ABCD", Mod: "remove"},
+        storage.DataScriptType{P: "KRC-20", Op: "transfer", From: addrTestB, To: addrTestC, Tick: "ABCD", Amt: "10"},
+    )
+    checkOpResult(t, opDataList, "accepted", "accepted")
+}
+
+////////////////////////////////
+// The order listed before the blacklist is filled by the buyer not blacklisted and cancelled by the lister blacklisted,
+// the buyer blacklisted can't fill it.
+func TestOpSendBlacklisted(t *testing.T) {
+    scenario := newScenarioTest(t)
+    uAddr, _ := misc.MakeP2shKasplex(scriptSigTest, "", `{"p":"krc-20","op":"send","tick":"abcd"}`, false)
+    opDataList, _ := scenario.run(
+        storage.DataScriptType{P: "KRC-20", Op: "deploy", From: addrTestA, Tick: "ABCD", Max: "1000", Mod: "issue"},
+        storage.DataScriptType{P: "KRC-20", Op: "issue", From: addrTestA, To: addrTestB, Tick: "ABCD", Amt: "500"},
+    )
+    checkOpResult(t, opDataList, "accepted", "accepted")
+    opDataList, _ = scenario.run(
+        storage.DataScriptType{P: "KRC-20", Op: "list", From: addrTestB, Tick: "ABCD", Amt: "100", Utxo: "txlist1_"+uAddr+"_100000"},
+        storage.DataScriptType{P: "KRC-20", Op: "list", From: addrTestB, Tick: "ABCD", Amt: "200", Utxo: "txlist2_"+uAddr+"_100000"},
+        storage.DataScriptType{P: "KRC-20", Op: "blacklist", From: addrTestA, To: addrTestB, Tick: "ABCD", Mod: "add"},
+    )
+    checkOpResult(t, opDataList, "accepted", "accepted", "accepted")
+
+    opDataList, _ = scenario.run(
+        storage.DataScriptType{P: "KRC-20", Op: "send", From: addrTestB, To: addrTestC, Tick: "ABCD", Utxo: "txlist1_"+addrTestB, Price: "100000"},
+        storage.DataScriptType{P: "KRC-20", Op: "blacklist", From: addrTestA, To: addrTestC, Tick: "ABCD", Mod: "add"},
+        storage.DataScriptType{P: "KRC-20", Op: "send", From: addrTestB, To: addrTestC, Tick: "ABCD", Utxo: "txlist2_"+addrTestB, Price: "100000"},
+        storage.DataScriptType{P: "KRC-20", Op: "send", From: addrTestB, To: addrTestB, Tick: "ABCD", Utxo: "txlist2_"+addrTestB, Price: "0"},
+    )
+    checkOpResult(t, opDataList, "accepted", "accepted", "address blacklisted", "accepted")
+    if (opDataList[0].OpEvent != storage.OpEventTrade || opDataList[3].OpEvent != storage.OpEventCancel) {
+        t.Errorf("event = %q %q", opDataList[0].OpEvent, opDataList[3].OpEvent)
+    }
+    stateMap := scenario.load(
+        storage.DataScriptType{P: "KRC-20", Op: "transfer", From: addrTestB, To: addrTestC, Tick: "ABCD", Amt: "1"},
+    )
+    stBalanceB := stateMap.StateBalanceMap[addrTestB+"_ABCD"]
+    stBalanceC := stateMap.StateBalanceMap[addrTestC+"_ABCD"]
+    if (stBalanceB.Balance != "400" || stBalanceB.Locked != "0" || stBalanceC.Balance != "100") {
+        t.Fatalf("balance after the fill and the cancel = %+v, %+v", stBalanceB, stBalanceC)
+    }
+}
@@ -0,0 +1,85 @@
+
+////////////////////////////////
+package operation
+
+import (
+    "reflect"
+    "testing"
+    "kasplex-executor/storage"
+)
+
+////////////////////////////////
+func TestOpBurnValidate(t *testing.T) {
+    testList := []struct{
+        name string
+        script storage.DataScriptType
+        daaScore uint64
+        testnet bool
+        want bool
+    }{
+        {"accept", storage.DataScriptType{P: "KRC-20", Op: "burn", From: addrTestA, Tick: "abcd", Amt: "100", To: addrTestB}, daaScoreTestGated, false, true},
+        {"accept testnet before the gate", storage.DataScriptType{P: "KRC-20", Op: "burn", From: addrTestA, Tick: "ABCD", Amt: "100"}, 1, true, true},
+        {"reject mainnet before the gate", storage.DataScriptType{P: "KRC-20", Op: "burn", From: addrTestA, Tick: "ABCD", Amt: "100"}, daaScoreTestGated-1, false, false},
+        {"reject no from", storage.DataScriptType{P: "KRC-20", Op: "burn", Tick: "ABCD", Amt: "100"}, daaScoreTestGated, false, false},
+        {"reject p", storage.DataScriptType{P: "KRC-721", Op: "burn", From: addrTestA, Tick: "ABCD", Amt: "100"}, daaScoreTestGated, false, false},
+        {"reject tick", storage.DataScriptType{P: "KRC-20", Op: "burn", From: addrTestA, Tick: "AB1D", Amt: "100"}, daaScoreTestGated, false, false},
+        {"reject amt zero", storage.DataScriptType{P: "KRC-20", Op: "burn", From: addrTestA, Tick: "ABCD", Amt: "0"}, daaScoreTestGated, false, false},
+        {"reject amt padded", storage.DataScriptType{P: "KRC-20", Op: "burn", From: addrTestA, Tick: "ABCD", Amt: "0100"}, daaScoreTestGated, false, false},
+    }
+    for _, test := range testList {
+        script := test.script
+        got := OpMethodBurn{}.Validate(&script, test.daaScore, test.testnet)
+        if got != test.want {
+            t.Errorf("%s: validate = %v, want %v", test.name, got, test.want)
+            continue
+        }
+        if (got && (script.Tick != "ABCD" || script.To != "")) {
+            t.Errorf("%s: script = %+v, tick not normalized or to not cleared", test.name, script)
+        }
+    }
+}
+
+////////////////////////////////
+// The burned amount is out of the supply and never minted again, the holder burned all is removed, and the rollback restores both.
+func TestOpBurnSupply(t *testing.T) {
+    scenario := newScenarioTest(t)
+    opDataList, _ := scenario.run(
+        storage.DataScriptType{P: "KRC-20", Op: "deploy", From: addrTestA, Tick: "ABCD", Max: "1000", Lim: "500", Pre: "500"},
+        storage.DataScriptType{P: "KRC-20", Op: "burn", From: addrTestA, Tick: "WXYZ", Amt: "1"},
+    )
+    checkOpResult(t, opDataList, "accepted", "tick not found")
+
+    opDataList, stateMap := scenario.run(
+        storage.DataScriptType{P: "KRC-20", Op: "burn", From: addrTestA, Tick: "ABCD", Amt: "200"},
+        storage.DataScriptType{P: "KRC-20", Op: "burn", From: addrTestB, Tick: "ABCD", Amt: "1"},
+        storage.DataScriptType{P: "KRC-20", Op: "burn", From: addrTestA, Tick: "ABCD", Amt: "301"},
+    )
+    checkOpResult(t, opDataList, "accepted", "balance insuff", "balance insuff")
+    stToken := stateMap.StateTokenMap["ABCD"]
+    if (stToken.Minted != "500" || stToken.Burned != "200" || stateMap.StateBalanceMap[addrTestA+"_ABCD"].Balance != "300") {
+        t.Fatalf("token after the burn = %+v, balance %+v", stToken, stateMap.StateBalanceMap[addrTestA+"_ABCD"])
+    }
+    if !reflect.DeepEqual(opDataList[0].SsInfo.AddressAffc, []string{addrTestA+"_ABCD=300"}) {
+        t.Errorf("address affc = %q", opDataList[0].SsInfo.AddressAffc)
+    }
+
+    opDataList, _ = scenario.run(
+        storage.DataScriptType{P: "KRC-20", Op: "burn", From: addrTestA, Tick: "ABCD", Amt: "300"},
+        storage.DataScriptType{P: "KRC-20", Op: "mint", From: addrTestB, Tick: "ABCD"},
+        storage.DataScriptType{P: "KRC-20", Op: "mint", From: addrTestB, Tick: "ABCD"},
+    )
+    checkOpResult(t, opDataList, "accepted", "accepted", "mint finished")
+    if !reflect.DeepEqual(opDataList[0].SsInfo.TickAffc, []string{"ABCD=-1"}) {
+        t.Errorf("tick affc of the holder burned all = %q", opDataList[0].SsInfo.TickAffc)
+    }
+    stateMap = scenario.load(storage.DataScriptType{P: "KRC-20", Op: "burn", From: addrTestA, Tick: "ABCD", Amt: "1"})
+    if (stateMap.StateTokenMap["ABCD"].Minted != "1000" || stateMap.StateTokenMap["ABCD"].Burned != "500" || stateMap.StateBalanceMap[addrTestA+"_ABCD"] != nil) {
+        t.Fatalf("token saved = %+v, balance %+v", stateMap.StateTokenMap["ABCD"], stateMap.StateBalanceMap[addrTestA+"_ABCD"])
+    }
+
+    scenario.rollback()
+    stateMap = scenario.load(storage.DataScriptType{P: "KRC-20", Op: "burn", From: addrTestA, Tick: "ABCD", Amt: "1"})
+    if (stateMap.StateTokenMap["ABCD"].Minted != "500" || stateMap.StateTokenMap["ABCD"].Burned != "200" || stateMap.StateBalanceMap[addrTestA+"_ABCD"] == nil || stateMap.StateBalanceMap[addrTestA+"_ABCD"].Balance != "300") {
+        t.Fatalf("token rolled back = %+v, balance %+v", stateMap.StateTokenMap["ABCD"], stateMap.StateBalanceMap[addrTestA+"_ABCD"])
+    }
+}
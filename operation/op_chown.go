@@ -0,0 +1,105 @@
+
+////////////////////////////////
+package operation
+
+import (
+    "kasplex-executor/misc"
+    "kasplex-executor/storage"
+)
+
+////////////////////////////////
+type OpMethodChown struct {}
+
+////////////////////////////////
+func init() {
+    pName := "KRC-20"
+    opName := "chown"
+    P_Registered[pName] = true
+    Op_Registered[opName] = true
+    Method_Registered[pName+"_"+opName] = new(OpMethodChown)
+}
+
+////////////////////////////////
+func (opMethodChown OpMethodChown) FeeLeast(daaScore uint64) (uint64) {
+    return 0
+}
+
+////////////////////////////////
+func (opMethodChown OpMethodChown) ScriptCollectEx(index int, script *storage.DataScriptType, txData *storage.DataTransactionType, testnet bool) {}
+
+////////////////////////////////
+func (opMethodChown OpMethodChown) Validate(script *storage.DataScriptType, daaScore uint64, testnet bool) (bool) {
+    if (!testnet && daaScore < 110165000) {
+        return false
+    }
+    if (script.From == "" || script.To == "" || script.P != "KRC-20" || !ValidateTick(&script.Tick)) {
+        return false
+    }
+    script.Amt = ""
+    script.Max = ""
+    script.Lim = ""
+    script.Pre = ""
+    script.Dec = ""
+    script.Utxo = ""
+    script.Price = ""
+    script.Mod = ""
+    script.Buri = ""
+    script.TokenId = ""
+    return true
+}
+
+////////////////////////////////
+func (opMethodChown OpMethodChown) PrepareStateKey(opScript *storage.DataScriptType, stateMap storage.DataStateMapType) {
+    stateMap.StateTokenMap[opScript.Tick] = nil
+}
+
+////////////////////////////////
+func (opMethodChown OpMethodChown) Do(index int, opData *storage.DataOperationType, stateMap storage.DataStateMapType, testnet bool) (error) {
+    opScript := opData.OpScript[index]
+    ////////////////////////////////
+    if stateMap.StateTokenMap[opScript.Tick] == nil {
+        opData.OpAccept = -1
+        opData.OpError = "tick not found"
+        return nil
+    }
+    if stateMap.StateTokenMap[opScript.Tick].Mod != storage.TokenModIssue {
+        opData.OpAccept = -1
+        opData.OpError = "mode invalid"
+        return nil
+    }
+    if stateMap.StateTokenMap[opScript.Tick].Owner != opScript.From {
+        opData.OpAccept = -1
+        opData.OpError = "no ownership"
+        return nil
+    }
+    if (opScript.From == opScript.To || !misc.VerifyAddr(opScript.To, testnet)) {
+        opData.OpAccept = -1
+        opData.OpError = "address invalid"
+        return nil
+    }
+    ////////////////////////////////
+    stToken := stateMap.StateTokenMap[opScript.Tick]
+    ////////////////////////////////
+    opData.StBefore = nil
+    opData.StBefore = AppendStLineToken(opData.StBefore, opScript.Tick, stToken, false, false)
+    ////////////////////////////////
+    stToken.Owner = opScript.To
+    stToken.OpMod = opData.OpScore
+    stToken.MtsMod = opData.MtsAdd
+    ////////////////////////////////
+    opData.SsInfo.TickAffc = AppendSsInfoTickAffc(opData.SsInfo.TickAffc, opScript.Tick, 0)
+    ////////////////////////////////
+    opData.StAfter = nil
+    opData.StAfter = AppendStLineToken(opData.StAfter, opScript.Tick, stToken, false, true)
+    ////////////////////////////////
+    opData.OpAccept = 1
+    return nil
+}
+
+////////////////////////////////
+/*func (opMethodChown OpMethodChown) UnDo() (error) {
+    // ...
+    return nil
+}*/
+
+// ...
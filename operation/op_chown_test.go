@@ -0,0 +1,72 @@
+
+////////////////////////////////
+package operation
+
+import (
+    "reflect"
+    "testing"
+    "kasplex-executor/storage"
+)
+
+////////////////////////////////
+func TestOpChownValidate(t *testing.T) {
+    testList := []struct{
+        name string
+        script storage.DataScriptType
+        daaScore uint64
+        testnet bool
+        want bool
+    }{
+        {"accept", storage.DataScriptType{P: "KRC-20", Op: "chown", From: addrTestA, To: addrTestB, Tick: "abcd", Amt: "100"}, daaScoreTestGated, false, true},
+        {"accept testnet before the gate", storage.DataScriptType{P: "KRC-20", Op: "chown", From: addrTestA, To: addrTestB, Tick: "ABCD"}, 1, true, true},
+        {"reject mainnet before the gate", storage.DataScriptType{P: "KRC-20", Op: "chown", From: addrTestA, To: addrTestB, Tick: "ABCD"}, daaScoreTestGated-1, false, false},
+        {"reject no to", storage.DataScriptType{P: "KRC-20", Op: "chown", From: addrTestA, Tick: "ABCD"}, daaScoreTestGated, false, false},
+        {"reject no from", storage.DataScriptType{P: "KRC-20", Op: "chown", To: addrTestB, Tick: "ABCD"}, daaScoreTestGated, false, false},
+        {"reject tick", storage.DataScriptType{P: "KRC-20", Op: "chown", From: addrTestA, To: addrTestB, Tick: "ABC"}, daaScoreTestGated, false, false},
+    }
+    for _, test := range testList {
+        script := test.script
+        got := OpMethodChown{}.Validate(&script, test.daaScore, test.testnet)
+        if got != test.want {
+            t.Errorf("%s: validate = %v, want %v", test.name, got, test.want)
+            continue
+        }
+        if (got && (script.Tick != "ABCD" || script.Amt != "")) {
+            t.Errorf("%s: script = %+v, tick not normalized or amt not cleared", test.name, script)
+        }
+    }
+}
+
+////////////////////////////////
+// The ownership moves to the new owner, the old one loses the issue and the chown.
+func TestOpChownOwnership(t *testing.T) {
+    scenario := newScenarioTest(t)
+    opDataList, _ := scenario.run(
+        storage.DataScriptType{P: "KRC-20", Op: "deploy", From: addrTestA, Tick: "ABCD", Max: "1000", Mod: "issue"},
+        storage.DataScriptType{P: "KRC-20", Op: "deploy", From: addrTestA, Tick: "WXYZ", Max: "1000", Lim: "10"},
+    )
+    checkOpResult(t, opDataList, "accepted", "accepted")
+
+    opDataList, stateMap := scenario.run(
+        storage.DataScriptType{P: "KRC-20", Op: "chown", From: addrTestB, To: addrTestC, Tick: "ABCD"},
+        storage.DataScriptType{P: "KRC-20", Op: "chown", From: addrTestA, To: addrTestA, Tick: "ABCD"},
+        storage.DataScriptType{P: "KRC-20", Op: "chown", From: addrTestA, To: addrTestB, Tick: "WXYZ"},
+        storage.DataScriptType{P: "KRC-20", Op: "chown", From: addrTestA, To: addrTestB, Tick: "ABCD"},
+        storage.DataScriptType{P: "KRC-20", Op: "issue", From: addrTestA, Tick: "ABCD", Amt: "10"},
+        storage.DataScriptType{P: "KRC-20", Op: "chown", From: addrTestA, To: addrTestC, Tick: "ABCD"},
+        storage.DataScriptType{P: "KRC-20", Op: "issue", From: addrTestB, Tick: "ABCD", Amt: "10"},
+    )
+    checkOpResult(t, opDataList, "no ownership", "address invalid", "mode invalid", "accepted", "no ownership", "no ownership", "accepted")
+    if !reflect.DeepEqual(opDataList[3].SsInfo.TickAffc, []string{"ABCD=0"}) {
+        t.Errorf("tick affc of the chown = %q", opDataList[3].SsInfo.TickAffc)
+    }
+    if stateMap.StateTokenMap["ABCD"].Owner != addrTestB {
+        t.Fatalf("owner after the chown = %q", stateMap.StateTokenMap["ABCD"].Owner)
+    }
+
+    scenario.rollback()
+    stateMap = scenario.load(storage.DataScriptType{P: "KRC-20", Op: "chown", From: addrTestA, To: addrTestB, Tick: "ABCD"})
+    if stateMap.StateTokenMap["ABCD"].Owner != addrTestA {
+        t.Fatalf("owner rolled back = %q", stateMap.StateTokenMap["ABCD"].Owner)
+    }
+}
@@ -14,10 +14,11 @@ type OpMethodDeploy struct {}
 
 ////////////////////////////////
 func init() {
+    pName := "KRC-20"
     opName := "deploy"
-    P_Registered["KRC-20"] = true
+    P_Registered[pName] = true
     Op_Registered[opName] = true
-    Method_Registered[opName] = new(OpMethodDeploy)
+    Method_Registered[pName+"_"+opName] = new(OpMethodDeploy)
 }
 
 ////////////////////////////////
@@ -31,7 +32,16 @@ func (opMethodDeploy OpMethodDeploy) ScriptCollectEx(index int, script *storage.
 
 ////////////////////////////////
 func (opMethodDeploy OpMethodDeploy) Validate(script *storage.DataScriptType, daaScore uint64, testnet bool) (bool) {
-    if (script.From == "" || script.P != "KRC-20" || !ValidateTick(&script.Tick) || !ValidateAmount(&script.Max) || !ValidateAmount(&script.Lim) || !ValidateDec(&script.Dec, "8")) {
+    if (script.Mod != storage.TokenModIssue || (!testnet && daaScore < 110165000)) {
+        script.Mod = ""
+    }
+    if (script.From == "" || script.P != "KRC-20" || !ValidateTick(&script.Tick) || !ValidateAmount(&script.Max) || !ValidateDec(&script.Dec, "8")) {
+        return false
+    }
+    // The lim is useless in the issue mode, the owner issues any amount.
+    if script.Mod == storage.TokenModIssue {
+        script.Lim = "0"
+    } else if !ValidateAmount(&script.Lim) {
         return false
     }
     if !ValidateAmount(&script.Pre) {
@@ -43,6 +53,8 @@ func (opMethodDeploy OpMethodDeploy) Validate(script *storage.DataScriptType, da
     script.Amt = ""
     script.Utxo = ""
     script.Price = ""
+    script.Buri = ""
+    script.TokenId = ""
     return true
 }
 
@@ -116,6 +128,10 @@ func (opMethodDeploy OpMethodDeploy) Do(index int, opData *storage.DataOperation
         MtsAdd: opData.MtsAdd,
         MtsMod: opData.MtsAdd,
     }
+    if opScript.Mod == storage.TokenModIssue {
+        stToken.Mod = opScript.Mod
+        stToken.Owner = opScript.From
+    }
     stateMap.StateTokenMap[opScript.Tick] = stToken
     if opScript.Pre != "0" {
         minted = opScript.Pre
@@ -0,0 +1,102 @@
+
+////////////////////////////////
+package operation
+
+import (
+    "reflect"
+    "testing"
+    "kasplex-executor/storage"
+)
+
+////////////////////////////////
+func TestOpIssueValidate(t *testing.T) {
+    testList := []struct{
+        name string
+        script storage.DataScriptType
+        daaScore uint64
+        testnet bool
+        want bool
+        wantTo string
+    }{
+        {"accept to", storage.DataScriptType{P: "KRC-20", Op: "issue", From: addrTestA, To: addrTestB, Tick: "abcd", Amt: "100"}, daaScoreTestGated, false, true, addrTestB},
+        {"accept to the owner", storage.DataScriptType{P: "KRC-20", Op: "issue", From: addrTestA, Tick: "ABCD", Amt: "100"}, daaScoreTestGated, false, true, addrTestA},
+        {"accept testnet before the gate", storage.DataScriptType{P: "KRC-20", Op: "issue", From: addrTestA, Tick: "ABCD", Amt: "100"}, 1, true, true, addrTestA},
+        {"reject mainnet before the gate", storage.DataScriptType{P: "KRC-20", Op: "issue", From: addrTestA, Tick: "ABCD", Amt: "100"}, daaScoreTestGated-1, false, false, ""},
+        {"reject no from", storage.DataScriptType{P: "KRC-20", Op: "issue", Tick: "ABCD", Amt: "100"}, daaScoreTestGated, false, false, ""},
+        {"reject tick", storage.DataScriptType{P: "KRC-20", Op: "issue", From: addrTestA, Tick: "ABCDEFG", Amt: "100"}, daaScoreTestGated, false, false, ""},
+        {"reject amt", storage.DataScriptType{P: "KRC-20", Op: "issue", From: addrTestA, Tick: "ABCD", Amt: ""}, daaScoreTestGated, false, false, ""},
+    }
+    for _, test := range testList {
+        script := test.script
+        got := OpMethodIssue{}.Validate(&script, test.daaScore, test.testnet)
+        if got != test.want {
+            t.Errorf("%s: validate = %v, want %v", test.name, got, test.want)
+            continue
+        }
+        if (got && (script.Tick != "ABCD" || script.To != test.wantTo)) {
+            t.Errorf("%s: script = %+v, want tick ABCD to %s", test.name, script, test.wantTo)
+        }
+    }
+}
+
+////////////////////////////////
+// The mode of the deploy is kept only if issue and after the gate, the lim is then useless.
+func TestOpDeployValidateMod(t *testing.T) {
+    testList := []struct{
+        name string
+        script storage.DataScriptType
+        daaScore uint64
+        want bool
+        wantMod string
+        wantLim string
+    }{
+        {"issue", storage.DataScriptType{P: "KRC-20", Op: "deploy", From: addrTestA, Tick: "ABCD", Max: "1000", Mod: "issue"}, daaScoreTestGated, true, "issue", "0"},
+        {"issue before the gate", storage.DataScriptType{P: "KRC-20", Op: "deploy", From: addrTestA, Tick: "ABCD", Max: "1000", Lim: "10", Mod: "issue"}, daaScoreTestGated-1, true, "", "10"},
+        {"issue before the gate no lim", storage.DataScriptType{P: "KRC-20", Op: "deploy", From: addrTestA, Tick: "ABCD", Max: "1000", Mod: "issue"}, daaScoreTestGated-1, false, "", ""},
+        {"mod unknown", storage.DataScriptType{P: "KRC-20", Op: "deploy", From: addrTestA, Tick: "ABCD", Max: "1000", Lim: "10", Mod: "free"}, daaScoreTestGated, true, "", "10"},
+    }
+    for _, test := range testList {
+        script := test.script
+        got := OpMethodDeploy{}.Validate(&script, test.daaScore, false)
+        if got != test.want {
+            t.Errorf("%s: validate = %v, want %v", test.name, got, test.want)
+            continue
+        }
+        if (got && (script.Mod != test.wantMod || script.Lim != test.wantLim)) {
+            t.Errorf("%s: mod lim = %q %q, want %q %q", test.name, script.Mod, script.Lim, test.wantMod, test.wantLim)
+        }
+    }
+}
+
+////////////////////////////////
+// Only the owner issues the token in the issue mode, any amount to any address up to the max, and nobody mints it.
+func TestOpIssueOwnerOnly(t *testing.T) {
+    scenario := newScenarioTest(t)
+    opDataList, stateMap := scenario.run(
+        storage.DataScriptType{P: "KRC-20", Op: "deploy", From: addrTestA, Tick: "ABCD", Max: "1000", Lim: "10", Mod: "issue"},
+        storage.DataScriptType{P: "KRC-20", Op: "deploy", From: addrTestA, Tick: "WXYZ", Max: "1000", Lim: "10"},
+    )
+    checkOpResult(t, opDataList, "accepted", "accepted")
+    stToken := stateMap.StateTokenMap["ABCD"]
+    if (stToken.Mod != "issue" || stToken.Owner != addrTestA || stToken.Lim != "0" || stateMap.StateTokenMap["WXYZ"].Mod != "") {
+        t.Fatalf("token deployed = %+v, %+v", stToken, stateMap.StateTokenMap["WXYZ"])
+    }
+
+    opDataList, stateMap = scenario.run(
+        storage.DataScriptType{P: "KRC-20", Op: "issue", From: addrTestB, Tick: "ABCD", Amt: "100"},
+        storage.DataScriptType{P: "KRC-20", Op: "mint", From: addrTestB, Tick: "ABCD"},
+        storage.DataScriptType{P: "KRC-20", Op: "mint", From: addrTestA, Tick: "ABCD"},
+        storage.DataScriptType{P: "KRC-20", Op: "issue", From: addrTestA, To: addrTestB, Tick: "ABCD", Amt: "600"},
+        storage.DataScriptType{P: "KRC-20", Op: "issue", From: addrTestA, Tick: "ABCD", Amt: "400"},
+        storage.DataScriptType{P: "KRC-20", Op: "issue", From: addrTestA, Tick: "ABCD", Amt: "401"},
+        storage.DataScriptType{P: "KRC-20", Op: "issue", From: addrTestA, Tick: "WXYZ", Amt: "100"},
+        storage.DataScriptType{P: "KRC-20", Op: "mint", From: addrTestB, Tick: "WXYZ"},
+    )
+    checkOpResult(t, opDataList, "no ownership", "mode invalid", "mode invalid", "accepted", "accepted", "max exceeded", "mode invalid", "accepted")
+    if (stateMap.StateTokenMap["ABCD"].Minted != "1000" || stateMap.StateBalanceMap[addrTestB+"_ABCD"].Balance != "600" || stateMap.StateBalanceMap[addrTestA+"_ABCD"].Balance != "400") {
+        t.Fatalf("token issued = %+v, balance %+v %+v", stateMap.StateTokenMap["ABCD"], stateMap.StateBalanceMap[addrTestB+"_ABCD"], stateMap.StateBalanceMap[addrTestA+"_ABCD"])
+    }
+    if (!reflect.DeepEqual(opDataList[3].SsInfo.TickAffc, []string{"ABCD=1"}) || !reflect.DeepEqual(opDataList[3].SsInfo.AddressAffc, []string{addrTestB+"_ABCD=600"})) {
+        t.Errorf("affc of the issue = %q %q", opDataList[3].SsInfo.TickAffc, opDataList[3].SsInfo.AddressAffc)
+    }
+}
@@ -0,0 +1,107 @@
+
+////////////////////////////////
+package operation
+
+import (
+    "kasplex-executor/storage"
+)
+
+////////////////////////////////
+type OpMethodKrc721Deploy struct {}
+
+////////////////////////////////
+func init() {
+    pName := "KRC-721"
+    opName := "deploy"
+    P_Registered[pName] = true
+    Op_Registered[opName] = true
+    Method_Registered[pName+"_"+opName] = new(OpMethodKrc721Deploy)
+}
+
+////////////////////////////////
+func (opMethodKrc721Deploy OpMethodKrc721Deploy) FeeLeast(daaScore uint64) (uint64) {
+    // if daaScore ...
+    return 100000000000
+}
+
+////////////////////////////////
+func (opMethodKrc721Deploy OpMethodKrc721Deploy) ScriptCollectEx(index int, script *storage.DataScriptType, txData *storage.DataTransactionType, testnet bool) {}
+
+////////////////////////////////
+func (opMethodKrc721Deploy OpMethodKrc721Deploy) Validate(script *storage.DataScriptType, daaScore uint64, testnet bool) (bool) {
+    if (!testnet && daaScore < 110165000) {
+        return false
+    }
+    if (script.From == "" || script.P != "KRC-721" || !ValidateTickCollection(&script.Tick) || !ValidateAmount(&script.Max) || !ValidateBuri(&script.Buri)) {
+        return false
+    }
+    script.To = ""
+    script.Lim = ""
+    script.Pre = ""
+    script.Dec = ""
+    script.Amt = ""
+    script.Utxo = ""
+    script.Price = ""
+    script.Mod = ""
+    script.TokenId = ""
+    return true
+}
+
+////////////////////////////////
+func (opMethodKrc721Deploy OpMethodKrc721Deploy) PrepareStateKey(opScript *storage.DataScriptType, stateMap storage.DataStateMapType) {
+    stateMap.StateCollectionMap[opScript.Tick] = nil
+}
+
+////////////////////////////////
+func (opMethodKrc721Deploy OpMethodKrc721Deploy) Do(index int, opData *storage.DataOperationType, stateMap storage.DataStateMapType, testnet bool) (error) {
+    opScript := opData.OpScript[index]
+    ////////////////////////////////
+    if stateMap.StateCollectionMap[opScript.Tick] != nil {
+        opData.OpAccept = -1
+        opData.OpError = "tick existed"
+        return nil
+    }
+    if opData.Fee == 0 {
+        opData.OpAccept = -1
+        opData.OpError = "fee unknown"
+        return nil
+    }
+    if opData.Fee < opData.FeeLeast {
+        opData.OpAccept = -1
+        opData.OpError = "fee not enough"
+        return nil
+    }
+    ////////////////////////////////
+    stCollection := stateMap.StateCollectionMap[opScript.Tick]
+    ////////////////////////////////
+    opData.StBefore = nil
+    opData.StBefore = AppendStLineCollection(opData.StBefore, opScript.Tick, stCollection, false)
+    ////////////////////////////////
+    stCollection = &storage.StateCollectionType{
+        Tick: opScript.Tick,
+        Max: opScript.Max,
+        Buri: opScript.Buri,
+        From: opScript.From,
+        Minted: "0",
+        TxId: opData.TxId,
+        OpAdd: opData.OpScore,
+        OpMod: opData.OpScore,
+        MtsAdd: opData.MtsAdd,
+        MtsMod: opData.MtsAdd,
+    }
+    stateMap.StateCollectionMap[opScript.Tick] = stCollection
+    ////////////////////////////////
+    opData.StAfter = nil
+    opData.StAfter = AppendStLineCollection(opData.StAfter, opScript.Tick, stCollection, true)
+    ////////////////////////////////
+    opData.OpAccept = 1
+    return nil
+}
+
+////////////////////////////////
+/*func (opMethodKrc721Deploy OpMethodKrc721Deploy) UnDo() (error) {
+    // ...
+    return nil
+}*/
+
+// ...
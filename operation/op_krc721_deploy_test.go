@@ -0,0 +1,60 @@
+
+////////////////////////////////
+package operation
+
+import (
+    "testing"
+    "kasplex-executor/storage"
+)
+
+////////////////////////////////
+func TestOpKrc721DeployValidate(t *testing.T) {
+    testList := []struct{
+        name string
+        script storage.DataScriptType
+        daaScore uint64
+        testnet bool
+        want bool
+    }{
+        {"accept", storage.DataScriptType{P: "KRC-721", Op: "deploy", From: addrTestA, Tick: "nft1", Max: "100", Buri: "ipfs://x", To: addrTestB}, daaScoreTestGated, false, true},
+        {"accept testnet before the gate", storage.DataScriptType{P: "KRC-721", Op: "deploy", From: addrTestA, Tick: "NFT1", Max: "100", Buri: "ipfs://x"}, 1, true, true},
+        {"reject mainnet before the gate", storage.DataScriptType{P: "KRC-721", Op: "deploy", From: addrTestA, Tick: "NFT1", Max: "100", Buri: "ipfs://x"}, daaScoreTestGated-1, false, false},
+        {"reject p", storage.DataScriptType{P: "KRC-20", Op: "deploy", From: addrTestA, Tick: "NFT1", Max: "100", Buri: "ipfs://x"}, daaScoreTestGated, false, false},
+        {"reject tick long", storage.DataScriptType{P: "KRC-721", Op: "deploy", From: addrTestA, Tick: "ABCDEFGHIJK", Max: "100", Buri: "ipfs://x"}, daaScoreTestGated, false, false},
+        {"reject tick char", storage.DataScriptType{P: "KRC-721", Op: "deploy", From: addrTestA, Tick: "NFT-1", Max: "100", Buri: "ipfs://x"}, daaScoreTestGated, false, false},
+        {"reject max", storage.DataScriptType{P: "KRC-721", Op: "deploy", From: addrTestA, Tick: "NFT1", Max: "0", Buri: "ipfs://x"}, daaScoreTestGated, false, false},
+        {"reject buri empty", storage.DataScriptType{P: "KRC-721", Op: "deploy", From: addrTestA, Tick: "NFT1", Max: "100"}, daaScoreTestGated, false, false},
+        {"reject buri separator", storage.DataScriptType{P: "KRC-721", Op: "deploy", From: addrTestA, Tick: "NFT1", Max: "100", Buri: "ipfs://x,y"}, daaScoreTestGated, false, false},
+        {"reject buri space", storage.DataScriptType{P: "KRC-721", Op: "deploy", From: addrTestA, Tick: "NFT1", Max: "100", Buri: "ipfs:// x"}, daaScoreTestGated, false, false},
+    }
+    for _, test := range testList {
+        script := test.script
+        got := OpMethodKrc721Deploy{}.Validate(&script, test.daaScore, test.testnet)
+        if got != test.want {
+            t.Errorf("%s: validate = %v, want %v", test.name, got, test.want)
+            continue
+        }
+        if (got && (script.Tick != "NFT1" || script.To != "")) {
+            t.Errorf("%s: script = %+v, tick not normalized or to not cleared", test.name, script)
+        }
+    }
+}
+
+////////////////////////////////
+// The tick of the collection is apart from the KRC-20 tokens, deployed once.
+func TestOpKrc721DeployTick(t *testing.T) {
+    scenario := newScenarioTest(t)
+    opDataList, stateMap := scenario.run(
+        storage.DataScriptType{P: "KRC-20", Op: "deploy", From: addrTestA, Tick: "ABCD", Max: "1000", Lim: "10"},
+        storage.DataScriptType{P: "KRC-721", Op: "deploy", From: addrTestB, Tick: "abcd", Max: "100", Buri: "ipfs://x"},
+        storage.DataScriptType{P: "KRC-721", Op: "deploy", From: addrTestA, Tick: "ABCD", Max: "10", Buri: "ipfs://y"},
+    )
+    checkOpResult(t, opDataList, "accepted", "accepted", "tick existed")
+    stCollection := stateMap.StateCollectionMap["ABCD"]
+    if (stCollection.From != addrTestB || stCollection.Max != "100" || stCollection.Minted != "0" || stateMap.StateTokenMap["ABCD"].From != addrTestA) {
+        t.Fatalf("collection deployed = %+v", stCollection)
+    }
+    if (len(opDataList[1].SsInfo.TickAffc) != 0 || len(opDataList[1].SsInfo.AddressAffc) != 0) {
+        t.Errorf("affc of the deploy = %q %q", opDataList[1].SsInfo.TickAffc, opDataList[1].SsInfo.AddressAffc)
+    }
+}
@@ -0,0 +1,135 @@
+
+////////////////////////////////
+package operation
+
+import (
+    "math/big"
+    "kasplex-executor/misc"
+    "kasplex-executor/storage"
+)
+
+////////////////////////////////
+type OpMethodKrc721Mint struct {}
+
+////////////////////////////////
+func init() {
+    pName := "KRC-721"
+    opName := "mint"
+    P_Registered[pName] = true
+    Op_Registered[opName] = true
+    Method_Registered[pName+"_"+opName] = new(OpMethodKrc721Mint)
+}
+
+////////////////////////////////
+func (opMethodKrc721Mint OpMethodKrc721Mint) FeeLeast(daaScore uint64) (uint64) {
+    // if daaScore ...
+    return 100000000
+}
+
+////////////////////////////////
+func (opMethodKrc721Mint OpMethodKrc721Mint) ScriptCollectEx(index int, script *storage.DataScriptType, txData *storage.DataTransactionType, testnet bool) {}
+
+////////////////////////////////
+func (opMethodKrc721Mint OpMethodKrc721Mint) Validate(script *storage.DataScriptType, daaScore uint64, testnet bool) (bool) {
+    if (!testnet && daaScore < 110165000) {
+        return false
+    }
+    if (script.From == "" || script.P != "KRC-721" || !ValidateTickCollection(&script.Tick)) {
+        return false
+    }
+    if script.To == "" {
+        script.To = script.From
+    }
+    script.Max = ""
+    script.Lim = ""
+    script.Pre = ""
+    script.Dec = ""
+    script.Amt = ""
+    script.Utxo = ""
+    script.Price = ""
+    script.Mod = ""
+    script.Buri = ""
+    script.TokenId = ""
+    return true
+}
+
+////////////////////////////////
+// The key of the token minted is unknown before executed, it is deleted by the rollback as not prepared.
+func (opMethodKrc721Mint OpMethodKrc721Mint) PrepareStateKey(opScript *storage.DataScriptType, stateMap storage.DataStateMapType) {
+    stateMap.StateCollectionMap[opScript.Tick] = nil
+}
+
+////////////////////////////////
+func (opMethodKrc721Mint OpMethodKrc721Mint) Do(index int, opData *storage.DataOperationType, stateMap storage.DataStateMapType, testnet bool) (error) {
+    opScript := opData.OpScript[index]
+    ////////////////////////////////
+    if stateMap.StateCollectionMap[opScript.Tick] == nil {
+        opData.OpAccept = -1
+        opData.OpError = "tick not found"
+        return nil
+    }
+    if opData.Fee == 0 {
+        opData.OpAccept = -1
+        opData.OpError = "fee unknown"
+        return nil
+    }
+    if opData.Fee < opData.FeeLeast {
+        opData.OpAccept = -1
+        opData.OpError = "fee not enough"
+        return nil
+    }
+    if !misc.VerifyAddr(opScript.To, testnet) {
+        opData.OpAccept = -1
+        opData.OpError = "address invalid"
+        return nil
+    }
+    ////////////////////////////////
+    stCollection := stateMap.StateCollectionMap[opScript.Tick]
+    maxBig := new(big.Int)
+    maxBig.SetString(stCollection.Max, 10)
+    mintedBig := new(big.Int)
+    mintedBig.SetString(stCollection.Minted, 10)
+    if mintedBig.Cmp(maxBig) >= 0 {
+        opData.OpAccept = -1
+        opData.OpError = "mint finished"
+        return nil
+    }
+    mintedBig = mintedBig.Add(mintedBig, big.NewInt(1))
+    minted := mintedBig.Text(10)
+    opScript.TokenId = minted
+    keyNft := opScript.Tick +"_"+ opScript.TokenId
+    stNft := stateMap.StateNftMap[keyNft]
+    ////////////////////////////////
+    opData.StBefore = nil
+    opData.StBefore = AppendStLineCollection(opData.StBefore, opScript.Tick, stCollection, false)
+    opData.StBefore = AppendStLineNft(opData.StBefore, keyNft, stNft, false)
+    ////////////////////////////////
+    stCollection.Minted = minted
+    stCollection.OpMod = opData.OpScore
+    stCollection.MtsMod = opData.MtsAdd
+    stNft = &storage.StateNftType{
+        Tick: opScript.Tick,
+        TokenId: opScript.TokenId,
+        Owner: opScript.To,
+        OpMod: opData.OpScore,
+    }
+    stateMap.StateNftMap[keyNft] = stNft
+    ////////////////////////////////
+    // The balance of the nft is the ownership of the token id, the tick affected is of the KRC-20 token only.
+    opData.SsInfo.AddressAffc = AppendSsInfoAddressAffc(opData.SsInfo.AddressAffc, opScript.To+"_"+keyNft, "1")
+    ////////////////////////////////
+    opData.StAfter = nil
+    opData.StAfter = AppendStLineCollection(opData.StAfter, opScript.Tick, stCollection, true)
+    opData.StAfter = AppendStLineNft(opData.StAfter, keyNft, stNft, true)
+    ////////////////////////////////
+    opData.OpAccept = 1
+    return nil
+}
+
+////////////////////////////////
+/*func (opMethodKrc721Mint OpMethodKrc721Mint) UnDo() (error) {
+    // ...
+    return nil
+}*/
+
+// ...
@@ -0,0 +1,72 @@
+
+////////////////////////////////
+package operation
+
+import (
+    "reflect"
+    "testing"
+    "kasplex-executor/storage"
+)
+
+////////////////////////////////
+func TestOpKrc721MintValidate(t *testing.T) {
+    testList := []struct{
+        name string
+        script storage.DataScriptType
+        daaScore uint64
+        testnet bool
+        want bool
+        wantTo string
+    }{
+        {"accept to", storage.DataScriptType{P: "KRC-721", Op: "mint", From: addrTestA, To: addrTestB, Tick: "nft1", TokenId: "9"}, daaScoreTestGated, false, true, addrTestB},
+        {"accept to the sender", storage.DataScriptType{P: "KRC-721", Op: "mint", From: addrTestA, Tick: "NFT1"}, daaScoreTestGated, false, true, addrTestA},
+        {"accept testnet before the gate", storage.DataScriptType{P: "KRC-721", Op: "mint", From: addrTestA, Tick: "NFT1"}, 1, true, true, addrTestA},
+        {"reject mainnet before the gate", storage.DataScriptType{P: "KRC-721", Op: "mint", From: addrTestA, Tick: "NFT1"}, daaScoreTestGated-1, false, false, ""},
+        {"reject no from", storage.DataScriptType{P: "KRC-721", Op: "mint", Tick: "NFT1"}, daaScoreTestGated, false, false, ""},
+        {"reject tick", storage.DataScriptType{P: "KRC-721", Op: "mint", From: addrTestA, Tick: ""}, daaScoreTestGated, false, false, ""},
+    }
+    for _, test := range testList {
+        script := test.script
+        got := OpMethodKrc721Mint{}.Validate(&script, test.daaScore, test.testnet)
+        if got != test.want {
+            t.Errorf("%s: validate = %v, want %v", test.name, got, test.want)
+            continue
+        }
+        if (got && (script.Tick != "NFT1" || script.To != test.wantTo || script.TokenId != "")) {
+            t.Errorf("%s: script = %+v, want tick NFT1 to %s and no token id", test.name, script, test.wantTo)
+        }
+    }
+}
+
+////////////////////////////////
+// The token ids are minted in sequence up to the max, the owner is recorded in the address affected.
+func TestOpKrc721MintSequence(t *testing.T) {
+    scenario := newScenarioTest(t)
+    opDataList, _ := scenario.run(
+        storage.DataScriptType{P: "KRC-721", Op: "deploy", From: addrTestA, Tick: "NFT1", Max: "2", Buri: "ipfs://x"},
+        storage.DataScriptType{P: "KRC-721", Op: "mint", From: addrTestA, Tick: "NFT9"},
+    )
+    checkOpResult(t, opDataList, "accepted", "tick not found")
+
+    opDataList, stateMap := scenario.run(
+        storage.DataScriptType{P: "KRC-721", Op: "mint", From: addrTestA, Tick: "NFT1"},
+        storage.DataScriptType{P: "KRC-721", Op: "mint", From: addrTestA, To: addrTestB, Tick: "NFT1"},
+        storage.DataScriptType{P: "KRC-721", Op: "mint", From: addrTestA, Tick: "NFT1"},
+    )
+    checkOpResult(t, opDataList, "accepted", "accepted", "mint finished")
+    if (opDataList[0].OpScript[0].TokenId != "1" || opDataList[1].OpScript[0].TokenId != "2") {
+        t.Errorf("token id = %q %q", opDataList[0].OpScript[0].TokenId, opDataList[1].OpScript[0].TokenId)
+    }
+    if (stateMap.StateNftMap["NFT1_1"].Owner != addrTestA || stateMap.StateNftMap["NFT1_2"].Owner != addrTestB || stateMap.StateCollectionMap["NFT1"].Minted != "2") {
+        t.Fatalf("nft minted = %+v %+v", stateMap.StateNftMap["NFT1_1"], stateMap.StateNftMap["NFT1_2"])
+    }
+    if (!reflect.DeepEqual(opDataList[1].SsInfo.AddressAffc, []string{addrTestB+"_NFT1_2=1"}) || len(opDataList[1].SsInfo.TickAffc) != 0) {
+        t.Errorf("affc of the mint = %q %q", opDataList[1].SsInfo.AddressAffc, opDataList[1].SsInfo.TickAffc)
+    }
+
+    scenario.rollback()
+    stateMap = scenario.load(storage.DataScriptType{P: "KRC-721", Op: "transfer", From: addrTestA, To: addrTestB, Tick: "NFT1", TokenId: "1"})
+    if stateMap.StateNftMap["NFT1_1"] != nil {
+        t.Fatalf("nft rolled back = %+v", stateMap.StateNftMap["NFT1_1"])
+    }
+}
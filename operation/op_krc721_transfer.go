@@ -0,0 +1,99 @@
+
+////////////////////////////////
+package operation
+
+import (
+    "kasplex-executor/misc"
+    "kasplex-executor/storage"
+)
+
+////////////////////////////////
+type OpMethodKrc721Transfer struct {}
+
+////////////////////////////////
+func init() {
+    pName := "KRC-721"
+    opName := "transfer"
+    P_Registered[pName] = true
+    Op_Registered[opName] = true
+    Method_Registered[pName+"_"+opName] = new(OpMethodKrc721Transfer)
+}
+
+////////////////////////////////
+func (opMethodKrc721Transfer OpMethodKrc721Transfer) FeeLeast(daaScore uint64) (uint64) {
+    return 0
+}
+
+////////////////////////////////
+func (opMethodKrc721Transfer OpMethodKrc721Transfer) ScriptCollectEx(index int, script *storage.DataScriptType, txData *storage.DataTransactionType, testnet bool) {}
+
+////////////////////////////////
+func (opMethodKrc721Transfer OpMethodKrc721Transfer) Validate(script *storage.DataScriptType, daaScore uint64, testnet bool) (bool) {
+    if (!testnet && daaScore < 110165000) {
+        return false
+    }
+    if (script.From == "" || script.To == "" || script.P != "KRC-721" || !ValidateTickCollection(&script.Tick) || !ValidateAmount(&script.TokenId)) {
+        return false
+    }
+    script.Max = ""
+    script.Lim = ""
+    script.Pre = ""
+    script.Dec = ""
+    script.Amt = ""
+    script.Utxo = ""
+    script.Price = ""
+    script.Mod = ""
+    script.Buri = ""
+    return true
+}
+
+////////////////////////////////
+func (opMethodKrc721Transfer OpMethodKrc721Transfer) PrepareStateKey(opScript *storage.DataScriptType, stateMap storage.DataStateMapType) {
+    stateMap.StateNftMap[opScript.Tick+"_"+opScript.TokenId] = nil
+}
+
+////////////////////////////////
+func (opMethodKrc721Transfer OpMethodKrc721Transfer) Do(index int, opData *storage.DataOperationType, stateMap storage.DataStateMapType, testnet bool) (error) {
+    opScript := opData.OpScript[index]
+    ////////////////////////////////
+    keyNft := opScript.Tick +"_"+ opScript.TokenId
+    stNft := stateMap.StateNftMap[keyNft]
+    if stNft == nil {
+        opData.OpAccept = -1
+        opData.OpError = "token not found"
+        return nil
+    }
+    if stNft.Owner != opScript.From {
+        opData.OpAccept = -1
+        opData.OpError = "no ownership"
+        return nil
+    }
+    if (opScript.From == opScript.To || !misc.VerifyAddr(opScript.To, testnet)) {
+        opData.OpAccept = -1
+        opData.OpError = "address invalid"
+        return nil
+    }
+    ////////////////////////////////
+    opData.StBefore = nil
+    opData.StBefore = AppendStLineNft(opData.StBefore, keyNft, stNft, false)
+    ////////////////////////////////
+    stNft.Owner = opScript.To
+    stNft.OpMod = opData.OpScore
+    ////////////////////////////////
+    opData.SsInfo.AddressAffc = AppendSsInfoAddressAffc(opData.SsInfo.AddressAffc, opScript.From+"_"+keyNft, "0")
+    opData.SsInfo.AddressAffc = AppendSsInfoAddressAffc(opData.SsInfo.AddressAffc, opScript.To+"_"+keyNft, "1")
+    ////////////////////////////////
+    opData.StAfter = nil
+    opData.StAfter = AppendStLineNft(opData.StAfter, keyNft, stNft, true)
+    ////////////////////////////////
+    opData.OpAccept = 1
+    return nil
+}
+
+////////////////////////////////
+/*func (opMethodKrc721Transfer OpMethodKrc721Transfer) UnDo() (error) {
+    // ...
+    return nil
+}*/
+
+// ...
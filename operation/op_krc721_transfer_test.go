@@ -0,0 +1,71 @@
+
+////////////////////////////////
+package operation
+
+import (
+    "reflect"
+    "testing"
+    "kasplex-executor/storage"
+)
+
+////////////////////////////////
+func TestOpKrc721TransferValidate(t *testing.T) {
+    testList := []struct{
+        name string
+        script storage.DataScriptType
+        daaScore uint64
+        testnet bool
+        want bool
+    }{
+        {"accept", storage.DataScriptType{P: "KRC-721", Op: "transfer", From: addrTestA, To: addrTestB, Tick: "nft1", TokenId: "1", Amt: "5"}, daaScoreTestGated, false, true},
+        {"accept testnet before the gate", storage.DataScriptType{P: "KRC-721", Op: "transfer", From: addrTestA, To: addrTestB, Tick: "NFT1", TokenId: "1"}, 1, true, true},
+        {"reject mainnet before the gate", storage.DataScriptType{P: "KRC-721", Op: "transfer", From: addrTestA, To: addrTestB, Tick: "NFT1", TokenId: "1"}, daaScoreTestGated-1, false, false},
+        {"reject no to", storage.DataScriptType{P: "KRC-721", Op: "transfer", From: addrTestA, Tick: "NFT1", TokenId: "1"}, daaScoreTestGated, false, false},
+        {"reject token id zero", storage.DataScriptType{P: "KRC-721", Op: "transfer", From: addrTestA, To: addrTestB, Tick: "NFT1", TokenId: "0"}, daaScoreTestGated, false, false},
+        {"reject token id padded", storage.DataScriptType{P: "KRC-721", Op: "transfer", From: addrTestA, To: addrTestB, Tick: "NFT1", TokenId: "01"}, daaScoreTestGated, false, false},
+        {"reject p", storage.DataScriptType{P: "KRC-20", Op: "transfer", From: addrTestA, To: addrTestB, Tick: "NFT1", TokenId: "1"}, daaScoreTestGated, false, false},
+    }
+    for _, test := range testList {
+        script := test.script
+        got := OpMethodKrc721Transfer{}.Validate(&script, test.daaScore, test.testnet)
+        if got != test.want {
+            t.Errorf("%s: validate = %v, want %v", test.name, got, test.want)
+            continue
+        }
+        if (got && (script.Tick != "NFT1" || script.Amt != "")) {
+            t.Errorf("%s: script = %+v, tick not normalized or amt not cleared", test.name, script)
+        }
+    }
+}
+
+////////////////////////////////
+// Only the owner transfers the token, both the sender and the recipient are in the address affected.
+func TestOpKrc721TransferOwnership(t *testing.T) {
+    scenario := newScenarioTest(t)
+    opDataList, _ := scenario.run(
+        storage.DataScriptType{P: "KRC-721", Op: "deploy", From: addrTestA, Tick: "NFT1", Max: "10", Buri: "ipfs://x"},
+        storage.DataScriptType{P: "KRC-721", Op: "mint", From: addrTestA, Tick: "NFT1"},
+    )
+    checkOpResult(t, opDataList, "accepted", "accepted")
+
+    opDataList, stateMap := scenario.run(
+        storage.DataScriptType{P: "KRC-721", Op: "transfer", From: addrTestB, To: addrTestC, Tick: "NFT1", TokenId: "1"},
+        storage.DataScriptType{P: "KRC-721", Op: "transfer", From: addrTestA, To: addrTestA, Tick: "NFT1", TokenId: "1"},
+        storage.DataScriptType{P: "KRC-721", Op: "transfer", From: addrTestA, To: addrTestB, Tick: "NFT1", TokenId: "2"},
+        storage.DataScriptType{P: "KRC-721", Op: "transfer", From: addrTestA, To: addrTestB, Tick: "NFT1", TokenId: "1"},
+        storage.DataScriptType{P: "KRC-721", Op: "transfer", From: addrTestA, To: addrTestC, Tick: "NFT1", TokenId: "1"},
+    )
+    checkOpResult(t, opDataList, "no ownership", "address invalid", "token not found", "accepted", "no ownership")
+    if !reflect.DeepEqual(opDataList[3].SsInfo.AddressAffc, []string{addrTestA+"_NFT1_1=0", addrTestB+"_NFT1_1=1"}) {
+        t.Errorf("address affc of the transfer = %q", opDataList[3].SsInfo.AddressAffc)
+    }
+    if stateMap.StateNftMap["NFT1_1"].Owner != addrTestB {
+        t.Fatalf("owner after the transfer = %q", stateMap.StateNftMap["NFT1_1"].Owner)
+    }
+
+    scenario.rollback()
+    stateMap = scenario.load(storage.DataScriptType{P: "KRC-721", Op: "transfer", From: addrTestA, To: addrTestB, Tick: "NFT1", TokenId: "1"})
+    if stateMap.StateNftMap["NFT1_1"].Owner != addrTestA {
+        t.Fatalf("owner rolled back = %q", stateMap.StateNftMap["NFT1_1"].Owner)
+    }
+}
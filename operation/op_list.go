@@ -15,10 +15,11 @@ type OpMethodList struct {}
 
 ////////////////////////////////
 func init() {
+    pName := "KRC-20"
     opName := "list"
-    P_Registered["KRC-20"] = true
+    P_Registered[pName] = true
     Op_Registered[opName] = true
-    Method_Registered[opName] = new(OpMethodList)
+    Method_Registered[pName+"_"+opName] = new(OpMethodList)
 }
 
 ////////////////////////////////
@@ -48,6 +49,9 @@ func (opMethodList OpMethodList) Validate(script *storage.DataScriptType, daaSco
     script.Pre = ""
     script.Dec = ""
     script.Price = ""
+    script.Mod = ""
+    script.Buri = ""
+    script.TokenId = ""
     return true
 }
 
@@ -55,6 +59,7 @@ func (opMethodList OpMethodList) Validate(script *storage.DataScriptType, daaSco
 func (opMethodList OpMethodList) PrepareStateKey(opScript *storage.DataScriptType, stateMap storage.DataStateMapType) {
     stateMap.StateTokenMap[opScript.Tick] = nil
     stateMap.StateBalanceMap[opScript.From+"_"+opScript.Tick] = nil
+    stateMap.StateBlacklistMap[opScript.Tick+"_"+opScript.From] = nil
 }
 
 ////////////////////////////////
@@ -66,6 +71,11 @@ func (opMethodList OpMethodList) Do(index int, opData *storage.DataOperationType
         opData.OpError = "tick not found"
         return nil
     }
+    if stateMap.StateBlacklistMap[opScript.Tick+"_"+opScript.From] != nil {
+        opData.OpAccept = -1
+        opData.OpError = "address blacklisted"
+        return nil
+    }
     ////////////////////////////////
     dataUtxo := strings.Split(opScript.Utxo, "_")
     keyMarket := opScript.Tick +"_"+ opScript.From +"_"+ dataUtxo[0]
@@ -13,10 +13,11 @@ type OpMethodMint struct {}
 
 ////////////////////////////////
 func init() {
+    pName := "KRC-20"
     opName := "mint"
-    P_Registered["KRC-20"] = true
+    P_Registered[pName] = true
     Op_Registered[opName] = true
-    Method_Registered[opName] = new(OpMethodMint)
+    Method_Registered[pName+"_"+opName] = new(OpMethodMint)
 }
 
 ////////////////////////////////
@@ -43,6 +44,9 @@ func (opMethodMint OpMethodMint) Validate(script *storage.DataScriptType, daaSco
     script.Dec = ""
     script.Utxo = ""
     script.Price = ""
+    script.Mod = ""
+    script.Buri = ""
+    script.TokenId = ""
     return true
 }
 
@@ -61,6 +65,11 @@ func (opMethodMint OpMethodMint) Do(index int, opData *storage.DataOperationType
         opData.OpError = "tick not found"
         return nil
     }
+    if stateMap.StateTokenMap[opScript.Tick].Mod == storage.TokenModIssue {
+        opData.OpAccept = -1
+        opData.OpError = "mode invalid"
+        return nil
+    }
     if opData.Fee == 0 {
         opData.OpAccept = -1
         opData.OpError = "fee unknown"
@@ -14,11 +14,12 @@ type OpMethodSend struct {}
 
 ////////////////////////////////
 func init() {
+    pName := "KRC-20"
     opName := "send"
-    P_Registered["KRC-20"] = true
+    P_Registered[pName] = true
     Op_Registered[opName] = true
-    OpRecycle_Registered[opName] = true
-    Method_Registered[opName] = new(OpMethodSend)
+    OpRecycle_Registered[pName+"_"+opName] = true
+    Method_Registered[pName+"_"+opName] = new(OpMethodSend)
 }
 
 ////////////////////////////////
@@ -55,6 +56,9 @@ func (opMethodSend OpMethodSend) Validate(script *storage.DataScriptType, daaSco
     script.Lim = ""
     script.Pre = ""
     script.Dec = ""
+    script.Mod = ""
+    script.Buri = ""
+    script.TokenId = ""
     return true
 }
 
@@ -65,6 +69,7 @@ func (opMethodSend OpMethodSend) PrepareStateKey(opScript *storage.DataScriptTyp
     stateMap.StateBalanceMap[opScript.To+"_"+opScript.Tick] = nil
     dataUtxo := strings.Split(opScript.Utxo, "_")
     stateMap.StateMarketMap[opScript.Tick+"_"+opScript.From+"_"+dataUtxo[0]] = nil
+    stateMap.StateBlacklistMap[opScript.Tick+"_"+opScript.To] = nil
 }
 
 ////////////////////////////////
@@ -76,6 +81,13 @@ func (opMethodSend OpMethodSend) Do(index int, opData *storage.DataOperationType
         opData.OpError = "tick not found"
         return nil
     }
+    // The blacklist is checked on the receiving address, the order listed before is still filled by any buyer not blacklisted.
+    // The cancel to the lister is always accepted, the tokens unlocked stay frozen in its balance.
+    if (opScript.To != opScript.From && stateMap.StateBlacklistMap[opScript.Tick+"_"+opScript.To] != nil) {
+        opData.OpAccept = -1
+        opData.OpError = "address blacklisted"
+        return nil
+    }
     ////////////////////////////////
     dataUtxo := strings.Split(opScript.Utxo, "_")
     keyMarket := opScript.Tick +"_"+ opScript.From +"_"+ dataUtxo[0]
@@ -14,10 +14,11 @@ type OpMethodTransfer struct {}
 
 ////////////////////////////////
 func init() {
+    pName := "KRC-20"
     opName := "transfer"
-    P_Registered["KRC-20"] = true
+    P_Registered[pName] = true
     Op_Registered[opName] = true
-    Method_Registered[opName] = new(OpMethodTransfer)
+    Method_Registered[pName+"_"+opName] = new(OpMethodTransfer)
 }
 
 ////////////////////////////////
@@ -39,6 +40,9 @@ func (opMethodTransfer OpMethodTransfer) Validate(script *storage.DataScriptType
     script.Dec = ""
     script.Utxo = ""
     script.Price = ""
+    script.Mod = ""
+    script.Buri = ""
+    script.TokenId = ""
     return true
 }
 
@@ -47,6 +51,7 @@ func (opMethodTransfer OpMethodTransfer) PrepareStateKey(opScript *storage.DataS
     stateMap.StateTokenMap[opScript.Tick] = nil
     stateMap.StateBalanceMap[opScript.From+"_"+opScript.Tick] = nil
     stateMap.StateBalanceMap[opScript.To+"_"+opScript.Tick] = nil
+    stateMap.StateBlacklistMap[opScript.Tick+"_"+opScript.From] = nil
 }
 
 ////////////////////////////////
@@ -58,6 +63,11 @@ func (opMethodTransfer OpMethodTransfer) Do(index int, opData *storage.DataOpera
         opData.OpError = "tick not found"
         return nil
     }
+    if stateMap.StateBlacklistMap[opScript.Tick+"_"+opScript.From] != nil {
+        opData.OpAccept = -1
+        opData.OpError = "address blacklisted"
+        return nil
+    }
     if (opScript.From == opScript.To || !misc.VerifyAddr(opScript.To, testnet)) {
         opData.OpAccept = -1
         opData.OpError = "address invalid"
@@ -1,8 +1,12 @@
 package storage
 
 import (
+	"encoding/json"
 	"kasplex-executor/api/models"
+	"log"
+	"math/big"
 	"sort"
+	"strconv"
 )
 
 func GetAddressBalances(address string) ([]*models.AddressBalance, error) {
@@ -19,8 +23,8 @@ func GetAddressBalances(address string) ([]*models.AddressBalance, error) {
 	for iter.Scan(&tick, &dec, &balance, &locked) {
 		balances = append(balances, &models.AddressBalance{
 			Tick:    tick,
-			Balance: parseStringToUint64(balance),
-			Locked:  parseStringToUint64(locked),
+			Balance: ParseAmount(balance).String(),
+			Locked:  ParseAmount(locked).String(),
 			Dec:     dec,
 		})
 	}
@@ -32,6 +36,31 @@ func GetAddressBalances(address string) ([]*models.AddressBalance, error) {
 	return balances, nil
 }
 
+// GetAddressBalancesAt returns the token balances of the address as of the opScore, reconstructed from the op history.
+func GetAddressBalancesAt(address string, opScore uint64) ([]*models.AddressBalance, error) {
+	balanceMap, err := GetStateBalanceMapAddressAt(address, opScore)
+	if err != nil {
+		return nil, err
+	}
+
+	balances := make([]*models.AddressBalance, 0, len(balanceMap))
+	for _, stBalance := range balanceMap {
+		balances = append(balances, &models.AddressBalance{
+			Tick:    stBalance.Tick,
+			Balance: ParseAmount(stBalance.Balance).String(),
+			Locked:  ParseAmount(stBalance.Locked).String(),
+			Dec:     stBalance.Dec,
+		})
+	}
+
+	// Sort by tick as the clustering order of stbalance
+	sort.Slice(balances, func(i, j int) bool {
+		return balances[i].Tick < balances[j].Tick
+	})
+
+	return balances, nil
+}
+
 func GetTopHoldersByTokenCount(page, pageSize int) ([]HolderPortfolio, int, error) {
 	// Use the balance index directly
 	query := sRuntime.sessionCassa.Query(`
@@ -49,10 +78,10 @@ func GetTopHoldersByTokenCount(page, pageSize int) ([]HolderPortfolio, int, erro
 
 	iter := query.Iter()
 	for iter.Scan(&address, &tick, &dec, &balance, &locked) {
-		bal := parseStringToUint64(balance)
-		lock := parseStringToUint64(locked)
+		bal := ParseAmount(balance)
+		lock := ParseAmount(locked)
 
-		if bal == 0 && lock == 0 {
+		if bal.Sign() == 0 && lock.Sign() == 0 {
 			continue
 		}
 
@@ -62,7 +91,7 @@ func GetTopHoldersByTokenCount(page, pageSize int) ([]HolderPortfolio, int, erro
 				Address:    address,
 				TokenCount: 0,
 				Holdings:   make([]PortfolioHolding, 0, 10),
-				TotalValue: 0,
+				TotalValue: new(big.Int),
 			}
 			addressMap[address] = portfolio
 		}
@@ -70,11 +99,12 @@ func GetTopHoldersByTokenCount(page, pageSize int) ([]HolderPortfolio, int, erro
 		// Add the holding
 		portfolio.Holdings = append(portfolio.Holdings, PortfolioHolding{
 			Tick:    tick,
-			Balance: bal,
-			Locked:  lock,
+			Balance: bal.String(),
+			Locked:  lock.String(),
 			Dec:     dec,
 		})
-		portfolio.TotalValue += bal + lock
+		portfolio.TotalValue.Add(portfolio.TotalValue, bal)
+		portfolio.TotalValue.Add(portfolio.TotalValue, lock)
 	}
 
 	if err := iter.Close(); err != nil {
@@ -98,7 +128,7 @@ func GetTopHoldersByTokenCount(page, pageSize int) ([]HolderPortfolio, int, erro
 		if portfolios[i].TokenCount != portfolios[j].TokenCount {
 			return portfolios[i].TokenCount > portfolios[j].TokenCount
 		}
-		return portfolios[i].TotalValue > portfolios[j].TotalValue
+		return portfolios[i].TotalValue.Cmp(portfolios[j].TotalValue) > 0
 	})
 
 	// Handle pagination
@@ -131,10 +161,10 @@ func GetAllAddressesBalances() ([]models.AddressPortfolio, error) {
 
 	iter := query.Iter()
 	for iter.Scan(&address, &tick, &dec, &balance, &locked) {
-		bal := parseStringToUint64(balance)
-		lock := parseStringToUint64(locked)
+		bal := ParseAmount(balance)
+		lock := ParseAmount(locked)
 
-		if bal == 0 && lock == 0 {
+		if bal.Sign() == 0 && lock.Sign() == 0 {
 			continue
 		}
 
@@ -149,8 +179,8 @@ func GetAllAddressesBalances() ([]models.AddressPortfolio, error) {
 
 		portfolio.Balances = append(portfolio.Balances, &models.AddressBalance{
 			Tick:    tick,
-			Balance: bal,
-			Locked:  lock,
+			Balance: bal.String(),
+			Locked:  lock.String(),
 			Dec:     dec,
 		})
 	}
@@ -167,3 +197,77 @@ func GetAllAddressesBalances() ([]models.AddressPortfolio, error) {
 
 	return result, nil
 }
+
+// GetAddressOperations returns the operations affecting the address, newest first.
+// The page starts before the cursor opScore if not zero, the op and tick are filtered if not empty.
+func GetAddressOperations(address string, cursor uint64, op, tick string, pageSize int) ([]models.AddressOperation, uint64, bool, error) {
+	cql := "SELECT opscore, txid, op, tick, balance, state, script FROM oplist_by_address WHERE address = ?"
+	args := []interface{}{address}
+	if cursor > 0 {
+		cql += " AND opscore < ?"
+		args = append(args, cursor)
+	}
+	filtering := false
+	if op != "" {
+		cql += " AND op = ?"
+		args = append(args, op)
+		filtering = true
+	}
+	if tick != "" {
+		cql += " AND tick = ?"
+		args = append(args, tick)
+		filtering = true
+	}
+	cql += " LIMIT ?"
+	args = append(args, pageSize+1)
+	if filtering {
+		cql += " ALLOW FILTERING"
+	}
+
+	iter := sRuntime.sessionCassa.Query(cql, args...).PageSize(pageSize + 1).Iter()
+	var opScore uint64
+	var txid, opType, tickRow, balance, state, script string
+	operations := make([]models.AddressOperation, 0, pageSize)
+	hasMore := false
+	cursorNext := uint64(0)
+
+	for iter.Scan(&opScore, &txid, &opType, &tickRow, &balance, &state, &script) {
+		if len(operations) >= pageSize {
+			hasMore = true
+			break
+		}
+		stateData := DataOpStateType{}
+		if err := json.Unmarshal([]byte(state), &stateData); err != nil {
+			log.Printf("Error parsing state JSON for txid %s: %v", txid, err)
+			continue
+		}
+		scriptData := DataScriptType{}
+		if err := json.Unmarshal([]byte(script), &scriptData); err != nil {
+			log.Printf("Error parsing script JSON for txid %s: %v", txid, err)
+			continue
+		}
+		operations = append(operations, models.AddressOperation{
+			P:        scriptData.P,
+			Op:       opType,
+			Tick:     tickRow,
+			Amt:      scriptData.Amt,
+			From:     scriptData.From,
+			To:       scriptData.To,
+			Balance:  balance,
+			OpScore:  strconv.FormatUint(opScore, 10),
+			HashRev:  txid,
+			FeeRev:   strconv.FormatUint(stateData.Fee, 10),
+			OpAccept: strconv.Itoa(int(stateData.OpAccept)),
+			OpError:  stateData.OpError,
+			OpEvent:  stateData.OpEvent,
+			MtsAdd:   strconv.FormatInt(stateData.MtsAdd, 10),
+		})
+		cursorNext = opScore
+	}
+
+	if err := iter.Close(); err != nil {
+		return nil, 0, false, err
+	}
+
+	return operations, cursorNext, hasMore, nil
+}
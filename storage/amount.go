@@ -0,0 +1,54 @@
+package storage
+
+import (
+	"math/big"
+	"strings"
+)
+
+// Decimal digits of the share percentage.
+const shareDecimals = 18
+
+// ParseAmount parses the decimal amount string stored in the state, zero if empty or invalid.
+func ParseAmount(s string) *big.Int {
+	amount, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		return new(big.Int)
+	}
+	return amount
+}
+
+// ShareOf returns the percentage of the amount in the total, rounded to shareDecimals digits.
+func ShareOf(amount, total *big.Int) string {
+	if total.Sign() <= 0 {
+		return "0"
+	}
+	share := new(big.Rat).SetFrac(new(big.Int).Mul(amount, big.NewInt(100)), total)
+	return share.FloatString(shareDecimals)
+}
+
+// FormatAmount renders the integer amount in base units as the decimal string with dec digits,
+// the trailing zeros of the fraction are trimmed. Empty if the amount is empty or invalid.
+func FormatAmount(amount string, dec int) string {
+	value, ok := new(big.Int).SetString(amount, 10)
+	if !ok {
+		return ""
+	}
+	if dec <= 0 {
+		return value.String()
+	}
+	sign := ""
+	if value.Sign() < 0 {
+		sign = "-"
+		value.Neg(value)
+	}
+	digits := value.String()
+	if len(digits) <= dec {
+		digits = strings.Repeat("0", dec-len(digits)+1) + digits
+	}
+	whole := digits[:len(digits)-dec]
+	fraction := strings.TrimRight(digits[len(digits)-dec:], "0")
+	if fraction == "" {
+		return sign + whole
+	}
+	return sign + whole + "." + fraction
+}
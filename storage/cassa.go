@@ -2,6 +2,7 @@
 package storage
 
 import (
+	"kasplex-executor/metrics"
 	"log/slog"
 	"math"
 	"sync"
@@ -22,7 +23,8 @@ var mtsBatchLastCassa = int64(0)
 
 // //////////////////////////////
 func startExecuteBatchCassa(lenBatch int, fAdd func(*gocql.Batch, int) error) (int64, error) {
-	if lenBatch <= 0 {
+	// Skip if the cluster db disabled, in the replay mode.
+	if lenBatch <= 0 || sRuntime.sessionCassa == nil {
 		return 0, nil
 	}
 	mtss := time.Now().UnixMilli()
@@ -35,6 +37,7 @@ func startExecuteBatchCassa(lenBatch int, fAdd func(*gocql.Batch, int) error) (i
 		nStartNext, err := doExecuteBatchCassa(lenBatch, nStart, fAdd, nBatchAdj)
 		if err != nil {
 			nRetry++
+			metrics.CassaBatchRetryTotal.Inc("execute")
 			if nRetry > nBatchMaxCassa {
 				return 0, err
 			}
@@ -111,7 +114,7 @@ func doExecuteBatchCassa(lenBatch int, nStart int, fAdd func(*gocql.Batch, int)
 
 // //////////////////////////////
 func startQueryBatchInCassa(lenBatch int, fQuery func(int, int, *gocql.Session) error) (int64, error) {
-	if lenBatch <= 0 {
+	if lenBatch <= 0 || sRuntime.sessionCassa == nil {
 		return 0, nil
 	}
 	mtss := time.Now().UnixMilli()
@@ -124,6 +127,7 @@ func startQueryBatchInCassa(lenBatch int, fQuery func(int, int, *gocql.Session)
 		nStartNext, err := doQueryBatchInCassa(lenBatch, nStart, fQuery, nBatchAdj)
 		if err != nil {
 			nRetry++
+			metrics.CassaBatchRetryTotal.Inc("query")
 			if nRetry > nBatchMaxCassa {
 				return 0, err
 			}
@@ -0,0 +1,52 @@
+package storage
+
+import (
+	"kasplex-executor/api/models"
+	"strconv"
+)
+
+// GetCheckpointByOpScore returns the checkpoint of the last accepted op at or before the opScore.
+// The opcheckpoint buckets are searched backwards down to the first one, the empty buckets are cheap to read.
+func GetCheckpointByOpScore(opScore uint64) (*models.CheckpointInfo, error) {
+	for opBucket := int64(opScore / OpBucketCheckpointBy); opBucket >= 0; opBucket-- {
+		var opScoreRow uint64
+		var txid, checkpoint string
+		iter := sRuntime.sessionCassa.Query(cqlnGetOpCheckpointLast, opBucket, opScore).Iter()
+		found := iter.Scan(&opScoreRow, &txid, &checkpoint)
+		if err := iter.Close(); err != nil {
+			return nil, err
+		}
+		if found {
+			return &models.CheckpointInfo{
+				OpScore:    opScoreRow,
+				DaaScore:   opScoreRow / 10000,
+				HashRev:    txid,
+				Checkpoint: checkpoint,
+			}, nil
+		}
+	}
+	return nil, nil
+}
+
+// GetCheckpointByDaaScore returns the checkpoint of the last accepted op at or before the daaScore.
+func GetCheckpointByDaaScore(daaScore uint64) (*models.CheckpointInfo, error) {
+	return GetCheckpointByOpScore(daaScore*10000 + 9999)
+}
+
+// GetCheckpointLast returns the checkpoint after the last executed batch.
+func GetCheckpointLast() (*models.CheckpointInfo, error) {
+	checkpoint, strOpScore, strDaaScore, err := GetRuntimeCassa("CHECKPOINT")
+	if err != nil {
+		return nil, err
+	}
+	if strDaaScore == "" {
+		return nil, nil
+	}
+	opScore, _ := strconv.ParseUint(strOpScore, 10, 64)
+	daaScore, _ := strconv.ParseUint(strDaaScore, 10, 64)
+	return &models.CheckpointInfo{
+		OpScore:    opScore,
+		DaaScore:   daaScore,
+		Checkpoint: checkpoint,
+	}, nil
+}
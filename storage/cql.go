@@ -23,8 +23,29 @@ var (
 			"WHERE oprange IS NOT NULL AND opscore IS NOT NULL AND tickaffc IS NOT NULL " +
 			"PRIMARY KEY ((tickaffc), opscore, oprange) " +
 			"WITH CLUSTERING ORDER BY (opscore DESC, oprange DESC);",
+		// v2.04 - Add the checkpoint index of the accepted ops, partitioned by the opscore bucket
+		"CREATE TABLE IF NOT EXISTS opcheckpoint(opbucket bigint, opscore bigint, txid ascii, checkpoint ascii, PRIMARY KEY((opbucket), opscore)) WITH CLUSTERING ORDER BY(opscore DESC);",
+		// v2.05 - Add the op index by the address affected
+		"CREATE TABLE IF NOT EXISTS oplist_by_address(address ascii, opscore bigint, txid ascii, op ascii, tick ascii, balance ascii, state ascii, script ascii, PRIMARY KEY((address), opscore)) WITH CLUSTERING ORDER BY(opscore DESC);",
+		// v2.06 - Add the op index by the tick, partitioned by the opscore bucket
+		"CREATE TABLE IF NOT EXISTS oplist_by_tick(tick ascii, opbucket bigint, opscore bigint, txid ascii, op ascii, accepted boolean, state ascii, script ascii, PRIMARY KEY((tick, opbucket), opscore)) WITH CLUSTERING ORDER BY(opscore DESC);",
+		// v2.07 - Add the trade ledger of the orders filled, partitioned by the opscore bucket
+		"CREATE TABLE IF NOT EXISTS optrade(tick ascii, opbucket bigint, opscore bigint, txid ascii, seller ascii, buyer ascii, amt ascii, price ascii, mtsadd bigint, PRIMARY KEY((tick, opbucket), opscore)) WITH CLUSTERING ORDER BY(opscore DESC);",
+		// v2.09 - Add the blacklist of the owned tokens
+		"CREATE TABLE IF NOT EXISTS stblacklist(tick ascii, address ascii, opadd bigint, PRIMARY KEY((tick), address)) WITH CLUSTERING ORDER BY(address ASC);",
+		// v2.10 - Add the KRC-721 collections and the token ownership
+		"CREATE TABLE IF NOT EXISTS stcollection(tick ascii, meta ascii, minted ascii, opmod bigint, mtsmod bigint, PRIMARY KEY((tick)));",
+		"CREATE TABLE IF NOT EXISTS stnft(tick ascii, tokenid ascii, owner ascii, opmod bigint, PRIMARY KEY((tick), tokenid)) WITH CLUSTERING ORDER BY(tokenid ASC);",
+		"CREATE INDEX IF NOT EXISTS idx_stnft_owner ON stnft(owner);",
 	}
 	////////////////////////////
+	// The columns added to the existing tables as {table, column, cqln}, the cqln is skipped if the column exists.
+	cqlnUpgradeColumn = [][3]string{
+		// v2.08 - Add the event of the trade ledger, the order filled or canceled
+		{"optrade", "event", "ALTER TABLE optrade ADD event ascii;"},
+	}
+	cqlnGetColumn = "SELECT column_name FROM system_schema.columns WHERE keyspace_name=? AND table_name=? AND column_name=?;"
+	////////////////////////////
 	cqlnGetRuntime = "SELECT * FROM runtime WHERE key=?;"
 	cqlnSetRuntime = "INSERT INTO runtime (key,value1,value2,value3) VALUES (?,?,?,?);"
 	////////////////////////////
@@ -32,17 +53,45 @@ var (
 	////////////////////////////
 	cqlnGetTransactionData = "SELECT txid,data FROM transaction WHERE txid IN ({txidIn});"
 	////////////////////////////
-	cqlnSaveStateToken     = "INSERT INTO sttoken (p2tick,tick,meta,minted,opmod,mtsmod) VALUES (?,?,?,?,?,?);"
-	cqlnDeleteStateToken   = "DELETE FROM sttoken WHERE p2tick=? AND tick=?;"
-	cqlnSaveStateBalance   = "INSERT INTO stbalance (address,tick,dec,balance,locked,opmod) VALUES (?,?,?,?,?,?);"
-	cqlnDeleteStateBalance = "DELETE FROM stbalance WHERE address=? AND tick=?;"
-	cqlnSaveStateMarket    = "INSERT INTO stmarket (tick,taddr_utxid,uaddr,uamt,uscript,tamt,opadd) VALUES (?,?,?,?,?,?,?);"
-	cqlnDeleteStateMarket  = "DELETE FROM stmarket WHERE tick=? AND taddr_utxid=?;"
+	cqlnSaveStateToken        = "INSERT INTO sttoken (p2tick,tick,meta,minted,opmod,mtsmod) VALUES (?,?,?,?,?,?);"
+	cqlnDeleteStateToken      = "DELETE FROM sttoken WHERE p2tick=? AND tick=?;"
+	cqlnSaveStateBalance      = "INSERT INTO stbalance (address,tick,dec,balance,locked,opmod) VALUES (?,?,?,?,?,?);"
+	cqlnDeleteStateBalance    = "DELETE FROM stbalance WHERE address=? AND tick=?;"
+	cqlnSaveStateMarket       = "INSERT INTO stmarket (tick,taddr_utxid,uaddr,uamt,uscript,tamt,opadd) VALUES (?,?,?,?,?,?,?);"
+	cqlnDeleteStateMarket     = "DELETE FROM stmarket WHERE tick=? AND taddr_utxid=?;"
+	cqlnSaveStateBlacklist    = "INSERT INTO stblacklist (tick,address,opadd) VALUES (?,?,?);"
+	cqlnDeleteStateBlacklist  = "DELETE FROM stblacklist WHERE tick=? AND address=?;"
+	cqlnGetStateTokenAll      = "SELECT tick,meta,minted,opmod,mtsmod FROM sttoken;"
+	cqlnGetStateBalanceAll    = "SELECT address,tick,dec,balance,locked,opmod FROM stbalance;"
+	cqlnGetStateMarketAll     = "SELECT tick,taddr_utxid,uaddr,uamt,uscript,tamt,opadd FROM stmarket;"
+	cqlnGetStateBlacklistAll  = "SELECT tick,address,opadd FROM stblacklist;"
+	cqlnSaveStateCollection   = "INSERT INTO stcollection (tick,meta,minted,opmod,mtsmod) VALUES (?,?,?,?,?);"
+	cqlnDeleteStateCollection = "DELETE FROM stcollection WHERE tick=?;"
+	cqlnSaveStateNft          = "INSERT INTO stnft (tick,tokenid,owner,opmod) VALUES (?,?,?,?);"
+	cqlnDeleteStateNft        = "DELETE FROM stnft WHERE tick=? AND tokenid=?;"
+	cqlnGetStateCollectionAll = "SELECT tick,meta,minted,opmod,mtsmod FROM stcollection;"
+	cqlnGetStateNftAll        = "SELECT tick,tokenid,owner,opmod FROM stnft;"
 	////////////////////////////
 	cqlnSaveOpData   = "INSERT INTO opdata (txid,state,script,stbefore,stafter) VALUES (?,?,?,?,?);"
 	cqlnDeleteOpData = "DELETE FROM opdata WHERE txid=?;"
 	////////////////////////////
 	cqlnSaveOpList   = "INSERT INTO oplist (oprange,opscore,txid,state,script,tickaffc,addressaffc) VALUES (?,?,?,?,?,?,?);"
 	cqlnDeleteOpList = "DELETE FROM oplist WHERE oprange=? AND opscore=?;"
+	////////////////////////////
+	cqlnSaveOpListByAddress   = "INSERT INTO oplist_by_address (address,opscore,txid,op,tick,balance,state,script) VALUES (?,?,?,?,?,?,?,?);"
+	cqlnDeleteOpListByAddress = "DELETE FROM oplist_by_address WHERE address=? AND opscore=?;"
+	cqlnSaveOpListByTick      = "INSERT INTO oplist_by_tick (tick,opbucket,opscore,txid,op,accepted,state,script) VALUES (?,?,?,?,?,?,?,?);"
+	cqlnDeleteOpListByTick    = "DELETE FROM oplist_by_tick WHERE tick=? AND opbucket=? AND opscore=?;"
+	cqlnSaveOpTrade           = "INSERT INTO optrade (tick,opbucket,opscore,txid,event,seller,buyer,amt,price,mtsadd) VALUES (?,?,?,?,?,?,?,?,?,?);"
+	cqlnDeleteOpTrade         = "DELETE FROM optrade WHERE tick=? AND opbucket=? AND opscore=?;"
+	cqlnSaveOpCheckpoint      = "INSERT INTO opcheckpoint (opbucket,opscore,txid,checkpoint) VALUES (?,?,?,?);"
+	cqlnDeleteOpCheckpoint    = "DELETE FROM opcheckpoint WHERE opbucket=? AND opscore=?;"
+	cqlnGetOpCheckpointLast   = "SELECT opscore,txid,checkpoint FROM opcheckpoint WHERE opbucket=? AND opscore<=? LIMIT 1;"
+	cqlnGetOpListIndexKey     = "SELECT opscore,script,addressaffc FROM oplist WHERE oprange=? AND opscore IN ({opscoreIn});"
+	////////////////////////////
+	cqlnGetOpListByRange  = "SELECT opscore,txid,state,script FROM oplist WHERE oprange IN ({oprangeIn});"
+	cqlnGetOpListByScore  = "SELECT opscore,txid,state,script FROM oplist WHERE oprange=? AND opscore IN ({opscoreIn});"
+	cqlnGetOpDataStAfter  = "SELECT txid,state,stafter FROM opdata WHERE txid IN ({txidIn});"
+	cqlnGetOpDataStBefore = "SELECT txid,stbefore FROM opdata WHERE txid IN ({txidIn});"
 	// ...
 )
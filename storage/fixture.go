@@ -0,0 +1,102 @@
+
+////////////////////////////////
+package storage
+
+import (
+    "os"
+    "sort"
+    "errors"
+    "path/filepath"
+    "encoding/json"
+    "kasplex-executor/protowire"
+)
+
+////////////////////////////////
+type fixtureVspcType struct {
+    DaaScore uint64 `json:"daaScore"`
+    Hash string `json:"hash"`
+    TxIdList []string `json:"txIdList"`
+}
+
+////////////////////////////////
+// Read the vspc and transaction data from the recorded fixture directory, used for the replay.
+//   vspc.json: [{"daaScore":0,"hash":"","txIdList":[""]}, ...]
+//   transaction/<txid>.json: the RpcTransaction, the input transactions are needed for the fee.
+type NodeSourceFixture struct {
+    dir string
+    vspcList []DataVspcType
+}
+
+////////////////////////////////
+func LoadNodeSourceFixture(dir string) (*NodeSourceFixture, error) {
+    dataJson, err := os.ReadFile(filepath.Join(dir, "vspc.json"))
+    if err != nil {
+        return nil, err
+    }
+    fixtureList := []fixtureVspcType{}
+    err = json.Unmarshal(dataJson, &fixtureList)
+    if err != nil {
+        return nil, err
+    }
+    if len(fixtureList) <= 0 {
+        return nil, errors.New("fixture vspc empty")
+    }
+    source := &NodeSourceFixture{
+        dir: dir,
+        vspcList: make([]DataVspcType, 0, len(fixtureList)),
+    }
+    for _, vspc := range fixtureList {
+        sort.Strings(vspc.TxIdList)
+        source.vspcList = append(source.vspcList, DataVspcType{
+            DaaScore: vspc.DaaScore,
+            Hash: vspc.Hash,
+            TxIdList: vspc.TxIdList,
+        })
+    }
+    sort.SliceStable(source.vspcList, func(i int, j int) (bool) {
+        return source.vspcList[i].DaaScore < source.vspcList[j].DaaScore
+    })
+    return source, nil
+}
+
+////////////////////////////////
+// Get the daaScore range of the fixture vspc.
+func (source *NodeSourceFixture) GetDaaScoreRange() (uint64, uint64) {
+    return source.vspcList[0].DaaScore, source.vspcList[len(source.vspcList)-1].DaaScore
+}
+
+////////////////////////////////
+// Get the next vspc data list, the gap of daaScore is skipped.
+func (source *NodeSourceFixture) GetVspcList(daaScoreStart uint64, lenBlock int) ([]DataVspcType, int64, error) {
+    iStart := sort.Search(len(source.vspcList), func(i int) (bool) {
+        return source.vspcList[i].DaaScore >= daaScoreStart
+    })
+    iEnd := iStart + lenBlock
+    if iEnd > len(source.vspcList) {
+        iEnd = len(source.vspcList)
+    }
+    vspcList := make([]DataVspcType, 0, iEnd-iStart)
+    vspcList = append(vspcList, source.vspcList[iStart:iEnd]...)
+    return vspcList, 0, nil
+}
+
+////////////////////////////////
+func (source *NodeSourceFixture) GetTransactionDataMap(txDataList []DataTransactionType) (map[string]*protowire.RpcTransaction, int64, error) {
+    txDataMap := map[string]*protowire.RpcTransaction{}
+    for _, txData := range txDataList {
+        dataJson, err := os.ReadFile(filepath.Join(source.dir, "transaction", filepath.Base(txData.TxId)+".json"))
+        if err != nil {
+            if os.IsNotExist(err) {
+                continue
+            }
+            return nil, 0, err
+        }
+        data := protowire.RpcTransaction{}
+        err = json.Unmarshal(dataJson, &data)
+        if err != nil {
+            return nil, 0, err
+        }
+        txDataMap[txData.TxId] = &data
+    }
+    return txDataMap, 0, nil
+}
@@ -0,0 +1,231 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Page size of the op index walked for the historical state.
+const nPageSizeHistory = 2000
+
+// The value of "at" with more digits is the opScore, the daaScore otherwise.
+const opScoreAtMin = uint64(100000000000)
+
+// ParseOpScoreAt returns the opScore of the last op at or before the daaScore or opScore.
+func ParseOpScoreAt(at string) (uint64, error) {
+	value, err := strconv.ParseUint(at, 10, 64)
+	if err != nil {
+		return 0, errors.New("invalid at parameter")
+	}
+	if value >= opScoreAtMin {
+		return value, nil
+	}
+	return value*10000 + 9999, nil
+}
+
+// getStateBalanceMapAt returns the balances matched after the opScore, keyed by "address_tick".
+// The anchor is the nearest snapshot file after the opScore if it's before the last checkpoint, the current state otherwise.
+// There's no limit of the opScore, the walk is bounded by the ops of the address or the tick.
+// The accepted ops after the opScore and up to the anchor are walked in the op index by fOpList, and the anchor is rewound
+// by the stbefore of each in descending order, so the earliest op after the opScore wins for each key.
+func getStateBalanceMapAt(opScore uint64, fLoad func(map[string]*StateBalanceType) error, fMatchKey func(string) bool, fOpList func(uint64) ([]DataOpListType, error)) (map[string]*StateBalanceType, error) {
+	balanceMap := make(map[string]*StateBalanceType)
+	opScoreMax := uint64(0)
+	head, err := GetCheckpointLast()
+	if err != nil {
+		return nil, err
+	}
+	if path, opScoreSnapshot := getSnapshotPathAfter(sRuntime.dirSnapshot, opScore); head != nil && path != "" && opScoreSnapshot < head.OpScore {
+		err = loadStateBalanceSnapshot(path, balanceMap, fMatchKey)
+		if err == nil {
+			opScoreMax = opScoreSnapshot
+		} else {
+			slog.Warn("storage.loadStateBalanceSnapshot failed, use the current state.", "path", path, "error", err.Error())
+			balanceMap = make(map[string]*StateBalanceType)
+		}
+	}
+	if opScoreMax == 0 {
+		err = fLoad(balanceMap)
+		if err != nil {
+			return nil, err
+		}
+	}
+	opList, err := fOpList(opScoreMax)
+	if err != nil {
+		return nil, err
+	}
+	if len(opList) == 0 {
+		return balanceMap, nil
+	}
+	_, err = GetOpDataStBeforeList(opList)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(opList, func(i, j int) bool {
+		return opList[i].OpScore > opList[j].OpScore
+	})
+	for _, op := range opList {
+		for _, line := range op.StBefore {
+			key, stBalance := ParseStLineBalance(line)
+			if key == "" || !fMatchKey(key) {
+				continue
+			}
+			if stBalance == nil {
+				delete(balanceMap, key)
+				continue
+			}
+			balanceMap[key] = stBalance
+		}
+	}
+	return balanceMap, nil
+}
+
+// loadStateBalanceSnapshot reads the balances of the keys matched in the snapshot file.
+func loadStateBalanceSnapshot(path string, balanceMap map[string]*StateBalanceType, fMatchKey func(string) bool) error {
+	_, _, err := readSnapshot(path, func(key string, value []byte) error {
+		if !strings.HasPrefix(key, KeyPrefixStateBalance) {
+			return nil
+		}
+		key = strings.TrimPrefix(key, KeyPrefixStateBalance)
+		if !fMatchKey(key) {
+			return nil
+		}
+		stBalance := &StateBalanceType{}
+		err := json.Unmarshal(value, stBalance)
+		if err != nil {
+			return err
+		}
+		balanceMap[key] = stBalance
+		return nil
+	})
+	return err
+}
+
+// getOpListAddressAfter returns the accepted ops of the address in oplist_by_address, after the opScore and up to the max if not zero.
+func getOpListAddressAfter(address string, opScore uint64, opScoreMax uint64) ([]DataOpListType, error) {
+	cql := "SELECT opscore, txid, state FROM oplist_by_address WHERE address = ? AND opscore > ?"
+	args := []interface{}{address, opScore}
+	if opScoreMax > 0 {
+		cql += " AND opscore <= ?"
+		args = append(args, opScoreMax)
+	}
+	opList := []DataOpListType{}
+	iter := sRuntime.sessionCassa.Query(cql, args...).PageSize(nPageSizeHistory).Iter()
+	op := DataOpListType{}
+	var state string
+	for iter.Scan(&op.OpScore, &op.TxId, &state) {
+		stateData := DataOpStateType{}
+		if json.Unmarshal([]byte(state), &stateData) != nil || stateData.OpAccept != 1 {
+			continue
+		}
+		opList = append(opList, DataOpListType{OpScore: op.OpScore, TxId: op.TxId})
+	}
+	if err := iter.Close(); err != nil {
+		return nil, err
+	}
+	return opList, nil
+}
+
+// getOpListTickAfter returns the accepted ops of the tick in oplist_by_tick, after the opScore and up to the max if not zero.
+func getOpListTickAfter(tick string, opScore uint64, opScoreMax uint64) ([]DataOpListType, error) {
+	_, bucketMax, err := getTickBucketRange(tick)
+	if err != nil {
+		return nil, err
+	}
+	cql := "SELECT opscore, txid, accepted FROM oplist_by_tick WHERE tick = ? AND opbucket = ? AND opscore > ?"
+	if opScoreMax > 0 {
+		bucketMax = opScoreMax / OpBucketTickBy
+		cql += " AND opscore <= ?"
+	}
+	opList := []DataOpListType{}
+	for opBucket := opScore / OpBucketTickBy; opBucket <= bucketMax; opBucket++ {
+		args := []interface{}{tick, opBucket, opScore}
+		if opScoreMax > 0 {
+			args = append(args, opScoreMax)
+		}
+		iter := sRuntime.sessionCassa.Query(cql, args...).PageSize(nPageSizeHistory).Iter()
+		op := DataOpListType{}
+		var accepted bool
+		for iter.Scan(&op.OpScore, &op.TxId, &accepted) {
+			if !accepted {
+				continue
+			}
+			opList = append(opList, DataOpListType{OpScore: op.OpScore, TxId: op.TxId})
+		}
+		if err := iter.Close(); err != nil {
+			return nil, err
+		}
+	}
+	return opList, nil
+}
+
+// ParseStLineBalance parses the balance line "stbalance_address_tick,dec,balance,locked,opmod" in stbefore/stafter.
+// The key is empty if not a balance line, the balance is nil if not existing.
+func ParseStLineBalance(line string) (string, *StateBalanceType) {
+	if !strings.HasPrefix(line, KeyPrefixStateBalance) {
+		return "", nil
+	}
+	list := strings.Split(strings.TrimPrefix(line, KeyPrefixStateBalance), ",")
+	key := list[0]
+	if len(list) < 5 {
+		return key, nil
+	}
+	addrTick := strings.SplitN(key, "_", 2)
+	if len(addrTick) < 2 {
+		return "", nil
+	}
+	dec, _ := strconv.Atoi(list[1])
+	opMod, _ := strconv.ParseUint(list[4], 10, 64)
+	return key, &StateBalanceType{
+		Address: addrTick[0],
+		Tick:    addrTick[1],
+		Dec:     dec,
+		Balance: list[2],
+		Locked:  list[3],
+		OpMod:   opMod,
+	}
+}
+
+// GetStateBalanceMapAddressAt returns the balances of the address after the opScore, keyed by "address_tick".
+func GetStateBalanceMapAddressAt(address string, opScore uint64) (map[string]*StateBalanceType, error) {
+	return getStateBalanceMapAt(opScore, func(balanceMap map[string]*StateBalanceType) error {
+		iter := sRuntime.sessionCassa.Query("SELECT address, tick, dec, balance, locked, opmod FROM stbalance WHERE address = ?", address).Iter()
+		stBalance := StateBalanceType{}
+		for iter.Scan(&stBalance.Address, &stBalance.Tick, &stBalance.Dec, &stBalance.Balance, &stBalance.Locked, &stBalance.OpMod) {
+			st := stBalance
+			balanceMap[st.Address+"_"+st.Tick] = &st
+		}
+		return iter.Close()
+	}, func(key string) bool {
+		return strings.HasPrefix(key, address+"_")
+	}, func(opScoreMax uint64) ([]DataOpListType, error) {
+		return getOpListAddressAfter(address, opScore, opScoreMax)
+	})
+}
+
+// GetStateBalanceMapTickAt returns the balances of the tick after the opScore, keyed by "address_tick".
+func GetStateBalanceMapTickAt(tick string, opScore uint64) (map[string]*StateBalanceType, error) {
+	return getStateBalanceMapAt(opScore, func(balanceMap map[string]*StateBalanceType) error {
+		iter := sRuntime.sessionCassa.Query(`
+			SELECT address, tick, dec, balance, locked, opmod
+			FROM stbalance
+			WHERE tick = ?
+			ALLOW FILTERING`,
+			tick,
+		).PageSize(2000).Iter()
+		stBalance := StateBalanceType{}
+		for iter.Scan(&stBalance.Address, &stBalance.Tick, &stBalance.Dec, &stBalance.Balance, &stBalance.Locked, &stBalance.OpMod) {
+			st := stBalance
+			balanceMap[st.Address+"_"+st.Tick] = &st
+		}
+		return iter.Close()
+	}, func(key string) bool {
+		return strings.HasSuffix(key, "_"+tick)
+	}, func(opScoreMax uint64) ([]DataOpListType, error) {
+		return getOpListTickAfter(tick, opScore, opScoreMax)
+	})
+}
@@ -0,0 +1,118 @@
+package storage
+
+import (
+	"kasplex-executor/config"
+	"testing"
+)
+
+func TestParseOpScoreAt(t *testing.T) {
+	tests := []struct {
+		at      string
+		opScore uint64
+		ok      bool
+	}{
+		{"110165000", 1101650009999, true},
+		{"1101650000003", 1101650000003, true},
+		{"0", 9999, true},
+		{"", 0, false},
+		{"-1", 0, false},
+		{"abc", 0, false},
+	}
+	for _, tt := range tests {
+		opScore, err := ParseOpScoreAt(tt.at)
+		if (err == nil) != tt.ok || opScore != tt.opScore {
+			t.Errorf("ParseOpScoreAt(%q) = %d, %v, want %d, ok %v", tt.at, opScore, err, tt.opScore, tt.ok)
+		}
+	}
+}
+
+func TestParseStLineBalance(t *testing.T) {
+	tests := []struct {
+		line      string
+		key       string
+		stBalance *StateBalanceType
+	}{
+		{
+			line:      "stbalance_kaspa:qzabc_TEST,8,1000,200,1101650000003",
+			key:       "kaspa:qzabc_TEST",
+			stBalance: &StateBalanceType{Address: "kaspa:qzabc", Tick: "TEST", Dec: 8, Balance: "1000", Locked: "200", OpMod: 1101650000003},
+		},
+		{
+			line: "stbalance_kaspa:qzabc_TEST",
+			key:  "kaspa:qzabc_TEST",
+		},
+		{
+			line: "sttoken_TEST,100,1,0,8,a,b,5,7",
+		},
+		{
+			line: "stbalance_TEST,8,1000,0,1",
+		},
+	}
+	for _, tt := range tests {
+		key, stBalance := ParseStLineBalance(tt.line)
+		if key != tt.key {
+			t.Errorf("ParseStLineBalance(%q) key = %q, want %q", tt.line, key, tt.key)
+		}
+		if (stBalance == nil) != (tt.stBalance == nil) || (stBalance != nil && *stBalance != *tt.stBalance) {
+			t.Errorf("ParseStLineBalance(%q) = %+v, want %+v", tt.line, stBalance, tt.stBalance)
+		}
+	}
+}
+
+func TestStateBalanceSnapshotAnchor(t *testing.T) {
+	InitRocks(config.RocksConfig{Path: t.TempDir()})
+	stateMap := NewDataStateMap()
+	stateMap.StateBalanceMap["kaspa:qzabc_TEST"] = &StateBalanceType{Address: "kaspa:qzabc", Tick: "TEST", Dec: 8, Balance: "1000", Locked: "0", OpMod: 10000000}
+	stateMap.StateBalanceMap["kaspa:qzdef_TEST"] = &StateBalanceType{Address: "kaspa:qzdef", Tick: "TEST", Dec: 8, Balance: "5", Locked: "0", OpMod: 10000001}
+	stateMap.StateTokenMap["TEST"] = &StateTokenType{Tick: "TEST", Max: "2000", Minted: "1005"}
+	txRocks, _, err := SaveStateBatchRocksBegin(stateMap, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = txRocks.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	txRocks.Destroy()
+	dir := t.TempDir()
+	for _, daaScore := range []uint64{1000, 2000} {
+		_, _, err = WriteSnapshot(MakeSnapshotPath(dir, daaScore), DataSnapshotHeaderType{DaaScore: daaScore}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		opScore    uint64
+		daaScore   uint64
+		opScoreMax uint64
+	}{
+		{9999999, 1000, 10009999},
+		{10009999, 1000, 10009999},
+		{10010000, 2000, 20009999},
+		{20010000, 0, 0},
+	}
+	for _, tt := range tests {
+		path, opScoreMax := getSnapshotPathAfter(dir, tt.opScore)
+		pathWant := ""
+		if tt.daaScore > 0 {
+			pathWant = MakeSnapshotPath(dir, tt.daaScore)
+		}
+		if path != pathWant || opScoreMax != tt.opScoreMax {
+			t.Errorf("getSnapshotPathAfter(%d) = %s, %d, want %s, %d", tt.opScore, path, opScoreMax, pathWant, tt.opScoreMax)
+		}
+	}
+	if path, _ := getSnapshotPathAfter("", 0); path != "" {
+		t.Errorf("getSnapshotPathAfter without the directory = %s", path)
+	}
+
+	balanceMap := map[string]*StateBalanceType{}
+	err = loadStateBalanceSnapshot(MakeSnapshotPath(dir, 1000), balanceMap, func(key string) bool {
+		return key == "kaspa:qzabc_TEST"
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(balanceMap) != 1 || balanceMap["kaspa:qzabc_TEST"] == nil || *balanceMap["kaspa:qzabc_TEST"] != *stateMap.StateBalanceMap["kaspa:qzabc_TEST"] {
+		t.Errorf("loadStateBalanceSnapshot = %+v", balanceMap)
+	}
+}
@@ -23,6 +23,7 @@ type runtimeType struct {
 	txOptRocks   *gorocksdb.TransactionOptions
 	cfgCassa     config.CassaConfig
 	cfgRocks     config.RocksConfig
+	dirSnapshot  string
 	// ...
 }
 
@@ -30,9 +31,22 @@ var sRuntime runtimeType
 
 // //////////////////////////////
 func Init(cfgCassa config.CassaConfig, cfgRocks config.RocksConfig) {
-	sRuntime.cfgCassa = cfgCassa
-	sRuntime.cfgRocks = cfgRocks
 	slog.Info("storage.Init start.")
+	InitCassa(cfgCassa)
+	InitRocks(cfgRocks)
+	slog.Info("storage ready.")
+}
+
+// //////////////////////////////
+// Set the directory of the snapshot files, used as the anchor of the historical queries.
+func SetSnapshotDir(dir string) {
+	sRuntime.dirSnapshot = dir
+}
+
+// //////////////////////////////
+// Init the cluster db only, the local db is not opened.
+func InitCassa(cfgCassa config.CassaConfig) {
+	sRuntime.cfgCassa = cfgCassa
 
 	// Use cassandra driver.
 	var err error
@@ -65,7 +79,30 @@ func Init(cfgCassa config.CassaConfig, cfgRocks config.RocksConfig) {
 		}
 	}
 
+	// Upgrade the tables if the column not exists, the ALTER fails on the existing column.
+	for _, upgrade := range cqlnUpgradeColumn {
+		var column string
+		err = sRuntime.sessionCassa.Query(cqlnGetColumn, sRuntime.cfgCassa.Space, upgrade[0], upgrade[1]).Scan(&column)
+		if err == nil {
+			continue
+		}
+		if err != gocql.ErrNotFound {
+			log.Fatalln("storage.Init fatal:", err.Error())
+		}
+		err = sRuntime.sessionCassa.Query(upgrade[2]).Exec()
+		if err != nil {
+			log.Fatalln("storage.Init fatal:", err.Error())
+		}
+	}
+}
+
+// //////////////////////////////
+// Init the local db only, the cluster db is disabled if not initialized.
+func InitRocks(cfgRocks config.RocksConfig) {
+	sRuntime.cfgRocks = cfgRocks
+
 	// Use rocksdb driver.
+	var err error
 	sRuntime.rOptRocks = gorocksdb.NewDefaultReadOptions()
 	sRuntime.wOptRocks = gorocksdb.NewDefaultWriteOptions()
 	sRuntime.txOptRocks = gorocksdb.NewDefaultTransactionOptions()
@@ -85,8 +122,6 @@ func Init(cfgCassa config.CassaConfig, cfgRocks config.RocksConfig) {
 	txOptRocks.SetTransactionLockTimeout(10)
 	sRuntime.rocksTx, err = gorocksdb.OpenTransactionDb(optRocks, txOptRocks, sRuntime.cfgRocks.Path)
 	if err != nil {
-		log.Fatalln("storage.Init fatal: ", err.Error())
+		log.Fatalln("storage.InitRocks fatal: ", err.Error())
 	}
-
-	slog.Info("storage ready.")
 }
@@ -0,0 +1,71 @@
+package storage
+
+import (
+	"kasplex-executor/api/models"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// GetMarketOrders returns the open list orders of the tick and/or the address, newest first.
+// The address matches either the seller or the P2SH address of the order.
+func GetMarketOrders(tick, address string) ([]models.MarketOrder, error) {
+	cql := "SELECT tick, taddr_utxid, uaddr, uamt, uscript, tamt, opadd FROM stmarket"
+	args := []interface{}{}
+	if tick != "" {
+		cql += " WHERE tick = ?"
+		args = append(args, tick)
+	}
+	iter := sRuntime.sessionCassa.Query(cql, args...).PageSize(2000).Iter()
+
+	var tickRow, tAddrUTxId, uAddr, uAmt, uScript, tAmt string
+	var opAdd uint64
+	orderList := []StateMarketType{}
+
+	for iter.Scan(&tickRow, &tAddrUTxId, &uAddr, &uAmt, &uScript, &tAmt, &opAdd) {
+		key := strings.SplitN(tAddrUTxId, "_", 2)
+		if len(key) < 2 {
+			continue
+		}
+		if address != "" && key[0] != address && uAddr != address {
+			continue
+		}
+		orderList = append(orderList, StateMarketType{
+			Tick:    tickRow,
+			TAddr:   key[0],
+			UTxId:   key[1],
+			UAddr:   uAddr,
+			UAmt:    uAmt,
+			UScript: uScript,
+			TAmt:    tAmt,
+			OpAdd:   opAdd,
+		})
+	}
+
+	if err := iter.Close(); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(orderList, func(i, j int) bool {
+		if orderList[i].OpAdd != orderList[j].OpAdd {
+			return orderList[i].OpAdd > orderList[j].OpAdd
+		}
+		return orderList[i].UTxId < orderList[j].UTxId
+	})
+
+	orders := make([]models.MarketOrder, 0, len(orderList))
+	for _, stMarket := range orderList {
+		orders = append(orders, models.MarketOrder{
+			Tick:        stMarket.Tick,
+			Address:     stMarket.TAddr,
+			P2shAddress: stMarket.UAddr,
+			UTxId:       stMarket.UTxId,
+			UAmt:        stMarket.UAmt,
+			TAmt:        stMarket.TAmt,
+			UScript:     stMarket.UScript,
+			OpScore:     strconv.FormatUint(stMarket.OpAdd, 10),
+		})
+	}
+
+	return orders, nil
+}
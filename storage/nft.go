@@ -0,0 +1,115 @@
+package storage
+
+import (
+	"encoding/json"
+	"kasplex-executor/api/models"
+	"sort"
+	"strconv"
+
+	"github.com/gocql/gocql"
+)
+
+// GetCollectionList returns all the KRC-721 collections, the newest deployed first.
+func GetCollectionList() ([]models.Collection, error) {
+	iter := sRuntime.sessionCassa.Query(cqlnGetStateCollectionAll).PageSize(5000).Iter()
+	var tick, meta, minted string
+	var opMod uint64
+	var mtsMod int64
+	collectionList := []models.Collection{}
+	for iter.Scan(&tick, &meta, &minted, &opMod, &mtsMod) {
+		collectionList = append(collectionList, makeCollection(tick, meta, minted, opMod, mtsMod))
+	}
+	if err := iter.Close(); err != nil {
+		return nil, err
+	}
+	sort.Slice(collectionList, func(i, j int) bool {
+		return collectionList[i].OpScoreAdd > collectionList[j].OpScoreAdd
+	})
+	return collectionList, nil
+}
+
+// GetCollection returns the KRC-721 collection of the tick, nil if not found.
+func GetCollection(tick string) (*models.Collection, error) {
+	var meta, minted string
+	var opMod uint64
+	var mtsMod int64
+	err := sRuntime.sessionCassa.Query(`SELECT meta, minted, opmod, mtsmod FROM stcollection WHERE tick = ?`, tick).Scan(&meta, &minted, &opMod, &mtsMod)
+	if err == gocql.ErrNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	collection := makeCollection(tick, meta, minted, opMod, mtsMod)
+	return &collection, nil
+}
+
+// makeCollection converts the row of stcollection to the model, the missing meta fields are left empty.
+func makeCollection(tick string, metaJson string, minted string, opMod uint64, mtsMod int64) models.Collection {
+	meta := StateCollectionMetaType{}
+	json.Unmarshal([]byte(metaJson), &meta)
+	return models.Collection{
+		Tick:       tick,
+		Max:        meta.Max,
+		Buri:       meta.Buri,
+		Deployer:   meta.From,
+		Minted:     minted,
+		HashRev:    meta.TxId,
+		OpScoreAdd: meta.OpAdd,
+		OpScoreMod: opMod,
+		MtsAdd:     meta.MtsAdd,
+		MtsMod:     mtsMod,
+	}
+}
+
+// GetNft returns the KRC-721 token of the tick and the token id, nil if not found.
+func GetNft(tick string, tokenId string) (*models.Nft, error) {
+	var owner string
+	var opMod uint64
+	err := sRuntime.sessionCassa.Query(`SELECT owner, opmod FROM stnft WHERE tick = ? AND tokenid = ?`, tick, tokenId).Scan(&owner, &opMod)
+	if err == gocql.ErrNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &models.Nft{
+		Tick:    tick,
+		TokenId: tokenId,
+		Owner:   owner,
+		OpScore: strconv.FormatUint(opMod, 10),
+	}, nil
+}
+
+// GetNftListByOwner returns the KRC-721 tokens held by the address, optionally of the tick only,
+// ordered by the tick and the token id.
+func GetNftListByOwner(address string, tick string) ([]models.Nft, error) {
+	cql := "SELECT tick, tokenid, opmod FROM stnft WHERE owner = ?"
+	args := []interface{}{address}
+	if tick != "" {
+		cql += " AND tick = ?"
+		args = append(args, tick)
+	}
+	iter := sRuntime.sessionCassa.Query(cql, args...).PageSize(5000).Iter()
+	var tickRow, tokenId string
+	var opMod uint64
+	nftList := []models.Nft{}
+	for iter.Scan(&tickRow, &tokenId, &opMod) {
+		nftList = append(nftList, models.Nft{
+			Tick:    tickRow,
+			TokenId: tokenId,
+			Owner:   address,
+			OpScore: strconv.FormatUint(opMod, 10),
+		})
+	}
+	if err := iter.Close(); err != nil {
+		return nil, err
+	}
+	sort.Slice(nftList, func(i, j int) bool {
+		if nftList[i].Tick != nftList[j].Tick {
+			return nftList[i].Tick < nftList[j].Tick
+		}
+		return ParseAmount(nftList[i].TokenId).Cmp(ParseAmount(nftList[j].TokenId)) < 0
+	})
+	return nftList, nil
+}
@@ -5,6 +5,7 @@ package storage
 import (
     "sync"
     "sort"
+    "time"
     "strconv"
     "strings"
     //"log/slog"
@@ -13,9 +14,113 @@ import (
     "kasplex-executor/protowire"
 )
 
+////////////////////////////////
+// Source of the vspc and transaction data, the node archive db by default.
+type NodeSource interface {
+    GetVspcList(uint64, int) ([]DataVspcType, int64, error)
+    GetTransactionDataMap([]DataTransactionType) (map[string]*protowire.RpcTransaction, int64, error)
+    // ...
+}
+
+////////////////////////////////
+// Read the vspc and transaction data from the node archive db.
+type NodeSourceCassa struct {}
+
+////////////////////////////////
+const lenVspcProbeCassa = 200
+const mtsRefreshVspcTipCassa = 5000
+
+////////////////////////////////
+// The max vspc daaScore in the node archive db, cached and refreshed by probing ahead.
+var vspcTipCassa = struct {
+    mutex sync.Mutex
+    daaScore uint64
+    mtsRefresh int64
+}{}
+
+////////////////////////////////
+func (nodeSourceCassa NodeSourceCassa) GetVspcList(daaScoreStart uint64, lenBlock int) ([]DataVspcType, int64, error) {
+    return GetNodeVspcList(daaScoreStart, lenBlock)
+}
+
+////////////////////////////////
+func (nodeSourceCassa NodeSourceCassa) GetTransactionDataMap(txDataList []DataTransactionType) (map[string]*protowire.RpcTransaction, int64, error) {
+    return GetNodeTransactionDataMap(txDataList)
+}
+
+////////////////////////////////
+// Get the max vspc daaScore in the node archive db, refreshed if expired, the cached one is kept if failed.
+// It starts from the max daaScore got in the vspc list, zero before any.
+func (nodeSourceCassa NodeSourceCassa) GetDaaScoreTip() (uint64) {
+    vspcTipCassa.mutex.Lock()
+    daaScoreTip := vspcTipCassa.daaScore
+    mtsNow := time.Now().UnixMilli()
+    if (daaScoreTip == 0 || mtsNow-vspcTipCassa.mtsRefresh < mtsRefreshVspcTipCassa) {
+        vspcTipCassa.mutex.Unlock()
+        return daaScoreTip
+    }
+    vspcTipCassa.mtsRefresh = mtsNow
+    vspcTipCassa.mutex.Unlock()
+    // Probe without the lock, the scan is not blocked.
+    daaScoreTip, err := probeVspcTipCassa(daaScoreTip)
+    vspcTipCassa.mutex.Lock()
+    defer vspcTipCassa.mutex.Unlock()
+    if (err == nil && daaScoreTip > vspcTipCassa.daaScore) {
+        vspcTipCassa.daaScore = daaScoreTip
+    }
+    return vspcTipCassa.daaScore
+}
+
+////////////////////////////////
+// Probe the max vspc daaScore after the daaScore, by the window after the tip and the exponential jump ahead.
+// The gap of the vspc daaScore is assumed less than the window.
+func probeVspcTipCassa(daaScoreTip uint64) (uint64, error) {
+    _maxIn := func(daaScoreStart uint64) (uint64, error) {
+        vspcList, _, err := getNodeVspcList(daaScoreStart, lenVspcProbeCassa)
+        if (err != nil || len(vspcList) <= 0) {
+            return 0, err
+        }
+        return vspcList[len(vspcList)-1].DaaScore, nil
+    }
+    for {
+        daaScoreMax, err := _maxIn(daaScoreTip + 1)
+        if err != nil {
+            return 0, err
+        }
+        if daaScoreMax == 0 {
+            return daaScoreTip, nil
+        }
+        daaScoreTip = daaScoreMax
+        for step := uint64(lenVspcProbeCassa*2); ; step *= 2 {
+            daaScoreMax, err = _maxIn(daaScoreTip + step)
+            if err != nil {
+                return 0, err
+            }
+            if daaScoreMax == 0 {
+                break
+            }
+            daaScoreTip = daaScoreMax
+        }
+    }
+}
+
 ////////////////////////////////
 // Get the next vspc data list, use the node archive db.
 func GetNodeVspcList(daaScoreStart uint64, lenBlock int) ([]DataVspcType, int64, error) {
+    vspcList, mtsBatch, err := getNodeVspcList(daaScoreStart, lenBlock)
+    if (err != nil || len(vspcList) <= 0) {
+        return vspcList, mtsBatch, err
+    }
+    vspcTipCassa.mutex.Lock()
+    if vspcList[len(vspcList)-1].DaaScore > vspcTipCassa.daaScore {
+        vspcTipCassa.daaScore = vspcList[len(vspcList)-1].DaaScore
+    }
+    vspcTipCassa.mutex.Unlock()
+    return vspcList, mtsBatch, nil
+}
+
+////////////////////////////////
+func getNodeVspcList(daaScoreStart uint64, lenBlock int) ([]DataVspcType, int64, error) {
     vspcMap := map[uint64]*DataVspcType{}
     mutex := new(sync.RWMutex)
     mtsBatch, err := startQueryBatchInCassa(lenBlock, func(iStart int, iEnd int, session *gocql.Session) (error) {
@@ -97,9 +202,9 @@ func GetNodeTransactionDataMap(txDataList []DataTransactionType) (map[string]*pr
 }
 
 ////////////////////////////////
-// Get the data list of the transaction in vspc, use the node archive db.
-func GetNodeTransactionDataList(txDataList []DataTransactionType) ([]DataTransactionType, int64, error) {
-    txDataMap, mtsBatch, err := GetNodeTransactionDataMap(txDataList)
+// Get the data list of the transaction in vspc, use the node data source.
+func GetNodeTransactionDataList(source NodeSource, txDataList []DataTransactionType) ([]DataTransactionType, int64, error) {
+    txDataMap, mtsBatch, err := source.GetTransactionDataMap(txDataList)
     if err != nil {
         return nil, 0, err
     }
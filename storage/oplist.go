@@ -0,0 +1,193 @@
+
+////////////////////////////////
+package storage
+
+import (
+    "sort"
+    "sync"
+    "strconv"
+    "strings"
+    "encoding/json"
+    "github.com/gocql/gocql"
+)
+
+////////////////////////////////
+// The op stored in oplist, with the state after in opdata.
+type DataOpListType struct {
+    OpScore uint64
+    TxId string
+    State DataOpStateType
+    Script DataScriptType
+    StAfter []string
+    StateOpData DataOpStateType
+    StBefore []string
+}
+
+////////////////////////////////
+// Get the op list by the opScore list, sorted by opScore.
+func GetOpListByScore(opScoreList []uint64) ([]DataOpListType, error) {
+    opList := []DataOpListType{}
+    if (len(opScoreList) <= 0 || sRuntime.sessionCassa == nil) {
+        return opList, nil
+    }
+    opScoreMap := map[uint64][]string{}
+    for _, opScore := range opScoreList {
+        opRange := opScore / OpRangeBy
+        opScoreMap[opRange] = append(opScoreMap[opRange], strconv.FormatUint(opScore, 10))
+    }
+    for opRange, opScoreIn := range opScoreMap {
+        cql := strings.Replace(cqlnGetOpListByScore, "{opscoreIn}", strings.Join(opScoreIn, ","), 1)
+        row := sRuntime.sessionCassa.Query(cql, opRange).Iter().Scanner()
+        for row.Next() {
+            opData := DataOpListType{}
+            var stateJson string
+            var scriptJson string
+            err := row.Scan(&opData.OpScore, &opData.TxId, &stateJson, &scriptJson)
+            if err != nil {
+                return nil, err
+            }
+            json.Unmarshal([]byte(stateJson), &opData.State)
+            json.Unmarshal([]byte(scriptJson), &opData.Script)
+            opList = append(opList, opData)
+        }
+        err := row.Err()
+        if err != nil {
+            return nil, err
+        }
+    }
+    sort.Slice(opList, func(i int, j int) (bool) {
+        return opList[i].OpScore < opList[j].OpScore
+    })
+    return opList, nil
+}
+
+////////////////////////////////
+// Get the op list in the oprange, sorted by opScore.
+func GetOpListByRange(opRangeStart uint64, lenRange int) ([]DataOpListType, int64, error) {
+    opList := []DataOpListType{}
+    mutex := new(sync.RWMutex)
+    mtsBatch, err := startQueryBatchInCassa(lenRange, func(iStart int, iEnd int, session *gocql.Session) (error) {
+        opRangeList := []string{}
+        for i := iStart; i < iEnd; i ++ {
+            opRangeList = append(opRangeList, strconv.FormatUint(opRangeStart+uint64(i),10))
+        }
+        opRangeIn := strings.Join(opRangeList, ",")
+        cql := strings.Replace(cqlnGetOpListByRange, "{oprangeIn}", opRangeIn, 1)
+        row := session.Query(cql).Iter().Scanner()
+        for row.Next() {
+            opData := DataOpListType{}
+            var stateJson string
+            var scriptJson string
+            err := row.Scan(&opData.OpScore, &opData.TxId, &stateJson, &scriptJson)
+            if err != nil {
+                return err
+            }
+            err = json.Unmarshal([]byte(stateJson), &opData.State)
+            if err != nil {
+                return err
+            }
+            err = json.Unmarshal([]byte(scriptJson), &opData.Script)
+            if err != nil {
+                return err
+            }
+            mutex.Lock()
+            opList = append(opList, opData)
+            mutex.Unlock()
+        }
+        return row.Err()
+    })
+    if err != nil {
+        return nil, 0, err
+    }
+    sort.Slice(opList, func(i int, j int) (bool) {
+        return opList[i].OpScore < opList[j].OpScore
+    })
+    return opList, mtsBatch, nil
+}
+
+////////////////////////////////
+// Fill the state after and the state in opdata of the op list.
+func GetOpDataStAfterList(opList []DataOpListType) (int64, error) {
+    iMap := make(map[string]int, len(opList))
+    for i := range opList {
+        iMap[opList[i].TxId] = i
+    }
+    mutex := new(sync.RWMutex)
+    mtsBatch, err := startQueryBatchInCassa(len(opList), func(iStart int, iEnd int, session *gocql.Session) (error) {
+        txIdList := []string{}
+        for i := iStart; i < iEnd; i ++ {
+            txIdList = append(txIdList, "'"+opList[i].TxId+"'")
+        }
+        txIdIn := strings.Join(txIdList, ",")
+        cql := strings.Replace(cqlnGetOpDataStAfter, "{txidIn}", txIdIn, 1)
+        row := session.Query(cql).Iter().Scanner()
+        for row.Next() {
+            var txId string
+            var stateJson string
+            var stAfterJson string
+            err := row.Scan(&txId, &stateJson, &stAfterJson)
+            if err != nil {
+                return err
+            }
+            stateData := DataOpStateType{}
+            err = json.Unmarshal([]byte(stateJson), &stateData)
+            if err != nil {
+                return err
+            }
+            stAfter := []string{}
+            err = json.Unmarshal([]byte(stAfterJson), &stAfter)
+            if err != nil {
+                return err
+            }
+            mutex.Lock()
+            opList[iMap[txId]].StateOpData = stateData
+            opList[iMap[txId]].StAfter = stAfter
+            mutex.Unlock()
+        }
+        return row.Err()
+    })
+    if err != nil {
+        return 0, err
+    }
+    return mtsBatch, nil
+}
+
+////////////////////////////////
+// Fill the state before in opdata of the op list.
+func GetOpDataStBeforeList(opList []DataOpListType) (int64, error) {
+    iMap := make(map[string]int, len(opList))
+    for i := range opList {
+        iMap[opList[i].TxId] = i
+    }
+    mutex := new(sync.RWMutex)
+    mtsBatch, err := startQueryBatchInCassa(len(opList), func(iStart int, iEnd int, session *gocql.Session) (error) {
+        txIdList := []string{}
+        for i := iStart; i < iEnd; i ++ {
+            txIdList = append(txIdList, "'"+opList[i].TxId+"'")
+        }
+        txIdIn := strings.Join(txIdList, ",")
+        cql := strings.Replace(cqlnGetOpDataStBefore, "{txidIn}", txIdIn, 1)
+        row := session.Query(cql).Iter().Scanner()
+        for row.Next() {
+            var txId string
+            var stBeforeJson string
+            err := row.Scan(&txId, &stBeforeJson)
+            if err != nil {
+                return err
+            }
+            stBefore := []string{}
+            err = json.Unmarshal([]byte(stBeforeJson), &stBefore)
+            if err != nil {
+                return err
+            }
+            mutex.Lock()
+            opList[iMap[txId]].StBefore = stBefore
+            mutex.Unlock()
+        }
+        return row.Err()
+    })
+    if err != nil {
+        return 0, err
+    }
+    return mtsBatch, nil
+}
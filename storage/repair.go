@@ -0,0 +1,59 @@
+
+////////////////////////////////
+package storage
+
+////////////////////////////////
+const nPageSizeRepairCassa = 5000
+
+////////////////////////////////
+// Get all the state data in the local db.
+func GetStateMapRocksAll() (DataStateMapType, error) {
+    stateMap := NewDataStateMap()
+    for _, kind := range stateKindList {
+        err := kind.getRocksAll(&stateMap)
+        if err != nil {
+            return DataStateMapType{}, err
+        }
+    }
+    return stateMap, nil
+}
+
+////////////////////////////////
+// Get all the state data in the cluster db, as the same format in the local db.
+func GetStateMapCassaAll() (DataStateMapType, error) {
+    stateMap := NewDataStateMap()
+    for _, kind := range stateKindList {
+        err := kind.getCassaAll(&stateMap)
+        if err != nil {
+            return DataStateMapType{}, err
+        }
+    }
+    return stateMap, nil
+}
+
+////////////////////////////////
+// Diff the state data key by key, the value of each side is nil if missing.
+func DiffStateMap(stateMapA DataStateMapType, stateMapB DataStateMapType) (DataStateMapType, DataStateMapType) {
+    diffA := NewDataStateMap()
+    diffB := NewDataStateMap()
+    for _, kind := range stateKindList {
+        kind.diffMap(stateMapA, stateMapB, &diffA, &diffB)
+    }
+    return diffA, diffB
+}
+
+////////////////////////////////
+// Save the state data in the local db only, the nil value is deleted.
+func SaveStateBatchRocks(stateMap DataStateMapType) (error) {
+    txRocks, _, err := SaveStateBatchRocksBegin(stateMap, nil)
+    defer txRocks.Destroy()
+    if err != nil {
+        return err
+    }
+    err = txRocks.Commit()
+    if err != nil {
+        txRocks.Rollback()
+        return err
+    }
+    return nil
+}
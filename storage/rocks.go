@@ -52,3 +52,43 @@ func doGetBatchRocks(lenBatch int, nStart int, fGet func(int, int, *gorocksdb.Tr
 	}
 	return time.Now().UnixMilli() - mtss, nil
 }
+
+// //////////////////////////////
+// Iterate the key/value with the prefix, in the local db, stop if fRow returns false.
+func doIterateRocks(prefix string, reverse bool, fRow func([]byte, []byte) (bool, error)) error {
+	return doIterateRocksWith(sRuntime.rOptRocks, prefix, reverse, fRow)
+}
+
+// Iterate the key/value with the prefix and the read options, e.g. with a snapshot.
+func doIterateRocksWith(rOpt *gorocksdb.ReadOptions, prefix string, reverse bool, fRow func([]byte, []byte) (bool, error)) error {
+	txRocks := sRuntime.rocksTx.TransactionBegin(sRuntime.wOptRocks, sRuntime.txOptRocks, nil)
+	defer txRocks.Destroy()
+	defer txRocks.Rollback()
+	iter := txRocks.NewIterator(rOpt)
+	defer iter.Close()
+	keyPrefix := []byte(prefix)
+	if reverse {
+		iter.SeekForPrev(append([]byte(prefix), 0xff))
+	} else {
+		iter.Seek(keyPrefix)
+	}
+	for iter.ValidForPrefix(keyPrefix) {
+		key := iter.Key()
+		value := iter.Value()
+		next, err := fRow(append([]byte{}, key.Data()...), append([]byte{}, value.Data()...))
+		key.Free()
+		value.Free()
+		if err != nil {
+			return err
+		}
+		if !next {
+			break
+		}
+		if reverse {
+			iter.Prev()
+		} else {
+			iter.Next()
+		}
+	}
+	return iter.Err()
+}
@@ -0,0 +1,95 @@
+
+////////////////////////////////
+package storage
+
+import (
+    "fmt"
+    "strconv"
+    "encoding/json"
+    "github.com/tecbot/gorocksdb"
+)
+
+////////////////////////////////
+const keyPrefixRollback = "RBK_"  // rollback record of each batch, by daaScore start.
+
+////////////////////////////////
+func makeKeyRollback(daaScoreStart uint64) ([]byte) {
+    return []byte(keyPrefixRollback + fmt.Sprintf("%020d", daaScoreStart))
+}
+
+////////////////////////////////
+// Save the rollback record and the vspc list of the batch, in the transaction of the state batch.
+func saveRollbackRecord(rollback DataRollbackType, vspcList []DataVspcType, txRocks *gorocksdb.Transaction) (error) {
+    record := DataRollbackRecordType{
+        Rollback: rollback,
+        VspcList: vspcList,
+    }
+    valueJson, _ := json.Marshal(record)
+    return txRocks.Put(makeKeyRollback(rollback.DaaScoreStart), valueJson)
+}
+
+////////////////////////////////
+// Delete the rollback record of the batch, in the transaction of the state batch.
+func deleteRollbackRecord(daaScoreStart uint64, txRocks *gorocksdb.Transaction) (error) {
+    return txRocks.Delete(makeKeyRollback(daaScoreStart))
+}
+
+////////////////////////////////
+// Delete the rollback records start before the daaScore, in the local db.
+func DeleteRollbackRecordBefore(daaScore uint64) (int, error) {
+    keyList := [][]byte{}
+    err := doIterateRocks(keyPrefixRollback, false, func(key []byte, value []byte) (bool, error) {
+        daaScoreStart, _ := strconv.ParseUint(string(key[len(keyPrefixRollback):]), 10, 64)
+        if daaScoreStart >= daaScore {
+            return false, nil
+        }
+        keyList = append(keyList, key)
+        return true, nil
+    })
+    if err != nil {
+        return 0, err
+    }
+    for _, key := range keyList {
+        err = sRuntime.rocksTx.Delete(sRuntime.wOptRocks, key)
+        if err != nil {
+            return 0, err
+        }
+    }
+    return len(keyList), nil
+}
+
+////////////////////////////////
+// Get the last rollback record list in ascending order, in the local db.
+func GetRollbackRecordLast(lenRecord int) ([]DataRollbackRecordType, error) {
+    recordList := []DataRollbackRecordType{}
+    err := doIterateRocks(keyPrefixRollback, true, func(key []byte, value []byte) (bool, error) {
+        record := DataRollbackRecordType{}
+        err := json.Unmarshal(value, &record)
+        if err != nil {
+            return false, err
+        }
+        recordList = append(recordList, record)
+        return len(recordList) < lenRecord, nil
+    })
+    if err != nil {
+        return nil, err
+    }
+    for i, j := 0, len(recordList)-1; i < j; i, j = i+1, j-1 {
+        recordList[i], recordList[j] = recordList[j], recordList[i]
+    }
+    return recordList, nil
+}
+
+////////////////////////////////
+// Get the first rollback record, in the local db.
+func GetRollbackRecordFirst() (*DataRollbackRecordType, error) {
+    var record *DataRollbackRecordType
+    err := doIterateRocks(keyPrefixRollback, false, func(key []byte, value []byte) (bool, error) {
+        record = &DataRollbackRecordType{}
+        return false, json.Unmarshal(value, record)
+    })
+    if err != nil {
+        return nil, err
+    }
+    return record, nil
+}
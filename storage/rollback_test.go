@@ -0,0 +1,57 @@
+
+////////////////////////////////
+package storage
+
+import (
+    "testing"
+    "kasplex-executor/config"
+)
+
+////////////////////////////////
+// The rollback record is committed with the state batch and deleted with the rollback of it.
+func TestRollbackRecordWithStateBatch(t *testing.T) {
+    InitRocks(config.RocksConfig{Path: t.TempDir()})
+    stateMap := NewDataStateMap()
+    stateMap.StateTokenMap["TEST"] = &StateTokenType{Tick: "TEST", Max: "100", Minted: "0"}
+    stateMapBefore := NewDataStateMap()
+    stateMapBefore.StateTokenMap["TEST"] = nil
+    rollback := DataRollbackType{
+        DaaScoreStart: 100,
+        DaaScoreEnd: 110,
+        CheckpointAfter: "cp",
+        StateMapBefore: stateMapBefore,
+    }
+    vspcList := []DataVspcType{{DaaScore: 100, Hash: "h100"}, {DaaScore: 110, Hash: "h110"}}
+    _, err := SaveOpStateBatch(nil, stateMap, rollback, vspcList)
+    if err != nil {
+        t.Fatal(err)
+    }
+    recordList, err := GetRollbackRecordLast(10)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if (len(recordList) != 1 || recordList[0].Rollback.CheckpointAfter != "cp" || len(recordList[0].VspcList) != 2) {
+        t.Fatalf("record list after save = %+v", recordList)
+    }
+    stateMapGot := NewDataStateMap()
+    stateMapGot.StateTokenMap["TEST"] = nil
+    _, err = GetStateMap(stateMapGot)
+    if (err != nil || stateMapGot.StateTokenMap["TEST"] == nil) {
+        t.Fatalf("token after save = %v, %v", stateMapGot.StateTokenMap["TEST"], err)
+    }
+    
+    _, err = RollbackOpStateBatch(recordList[0].Rollback)
+    if err != nil {
+        t.Fatal(err)
+    }
+    recordList, err = GetRollbackRecordLast(10)
+    if (err != nil || len(recordList) != 0) {
+        t.Fatalf("record list after rollback = %+v, %v", recordList, err)
+    }
+    stateMapGot = NewDataStateMap()
+    stateMapGot.StateTokenMap["TEST"] = nil
+    _, err = GetStateMap(stateMapGot)
+    if (err != nil || stateMapGot.StateTokenMap["TEST"] != nil) {
+        t.Fatalf("token after rollback = %v, %v", stateMapGot.StateTokenMap["TEST"], err)
+    }
+}
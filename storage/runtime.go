@@ -3,6 +3,7 @@
 package storage
 
 import (
+    "errors"
     "strconv"
     "encoding/json"
 )
@@ -85,6 +86,9 @@ func SetRuntimeRollbackLast(list []DataRollbackType) (error) {
 ////////////////////////////////
 // Get runtime data from table "runtime", in the cluster db.
 func GetRuntimeCassa(key string) (string, string, string, error) {
+    if sRuntime.sessionCassa == nil {
+        return "", "", "", nil
+    }
     key = keyPrefixRuntimeCassa + key
     row := sRuntime.sessionCassa.Query(cqlnGetRuntime, key)
     defer row.Release()
@@ -102,6 +106,9 @@ func GetRuntimeCassa(key string) (string, string, string, error) {
 ////////////////////////////////
 // Set runtime data to table "runtime", in the cluster db.
 func SetRuntimeCassa(key string, v1 string, v2 string, v3 string) (error) {
+    if sRuntime.sessionCassa == nil {
+        return nil
+    }
     key = keyPrefixRuntimeCassa + key
     err := sRuntime.sessionCassa.Query(cqlnSetRuntime, key, v1, v2, v3).Exec()
     return err
@@ -135,6 +142,37 @@ func SetRuntimeSynced(Synced bool, opScore uint64, daaScore uint64) (error) {
     return err
 }
 
+////////////////////////////////
+// Set the checkpoint after the last executed batch.
+func SetRuntimeCheckpoint(checkpoint string, opScore uint64, daaScore uint64) (error) {
+    strDaaScore := strconv.FormatUint(daaScore, 10)
+    strOpScore := strconv.FormatUint(opScore, 10)
+    return SetRuntimeCassa("CHECKPOINT", checkpoint, strOpScore, strDaaScore)
+}
+
+////////////////////////////////
+// Check if the cluster db is reachable, skipped if disabled.
+func PingCassa() (error) {
+    if sRuntime.sessionCassa == nil {
+        return nil
+    }
+    return sRuntime.sessionCassa.Query("SELECT now() FROM system.local;").Exec()
+}
+
+////////////////////////////////
+// Check if the local db is readable, by a small key not written.
+func PingRocks() (error) {
+    if sRuntime.rocksTx == nil {
+        return errors.New("rocksdb not opened")
+    }
+    row, err := sRuntime.rocksTx.Get(sRuntime.rOptRocks, []byte(keyPrefixRuntime + "PING"))
+    if err != nil {
+        return err
+    }
+    row.Free()
+    return nil
+}
+
 ////////////////////////////////
 // Set the version.
 func SetRuntimeVersion(version string) (error) {
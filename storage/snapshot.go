@@ -0,0 +1,321 @@
+
+////////////////////////////////
+package storage
+
+import (
+    "os"
+    "io"
+    "fmt"
+    "sort"
+    "bufio"
+    "errors"
+    "strconv"
+    "strings"
+    "path/filepath"
+    "compress/gzip"
+    "encoding/hex"
+    "encoding/json"
+    "golang.org/x/crypto/blake2b"
+    "github.com/tecbot/gorocksdb"
+)
+
+////////////////////////////////
+const FormatSnapshot = 1
+const lenSnapshotBatchRocks = 5000
+const lenSnapshotLineMax = 64 * 1024 * 1024
+const PatternSnapshotFile = "snapshot-*.jsonl.gz"
+
+////////////////////////////////
+// Header line of the snapshot file, the state lines and the hash line follow.
+//   {"format":1,"daaScore":0,...}
+//   {"k":"sttoken_XXX","v":{...}}
+//   ...
+//   {"count":0,"hash":""}
+// The hash is the blake2b-256 of all the lines before, uncompressed.
+type DataSnapshotHeaderType struct {
+    Format int `json:"format"`
+    Version string `json:"version"`
+    Testnet bool `json:"testnet"`
+    DaaScore uint64 `json:"daaScore"`
+    OpScore uint64 `json:"opScore"`
+    Checkpoint string `json:"checkpoint"`
+    KeyPrefixList []string `json:"keyPrefixList"`
+    VspcList []DataVspcType `json:"vspcList"`
+    RollbackList []DataRollbackType `json:"rollbackList"`
+    MtsAdd int64 `json:"mtsAdd"`
+}
+
+////////////////////////////////
+type dataSnapshotStateType struct {
+    Key string `json:"k"`
+    Value json.RawMessage `json:"v"`
+}
+
+////////////////////////////////
+type dataSnapshotHashType struct {
+    Count int `json:"count"`
+    Hash string `json:"hash"`
+}
+
+////////////////////////////////
+// Point-in-time view of the local db, the state is read through it while the scan goes on.
+type SnapshotRocksType struct {
+    snapshot *gorocksdb.Snapshot
+    rOpt *gorocksdb.ReadOptions
+}
+
+////////////////////////////////
+// Take the point-in-time view of the local db, released by ReleaseSnapshotRocks.
+func NewSnapshotRocks() (*SnapshotRocksType) {
+    snapshotRocks := &SnapshotRocksType{
+        snapshot: sRuntime.rocksTx.NewSnapshot(),
+        rOpt: gorocksdb.NewDefaultReadOptions(),
+    }
+    snapshotRocks.rOpt.SetSnapshot(snapshotRocks.snapshot)
+    return snapshotRocks
+}
+
+////////////////////////////////
+// Release the point-in-time view of the local db.
+func ReleaseSnapshotRocks(snapshotRocks *SnapshotRocksType) {
+    if snapshotRocks == nil {
+        return
+    }
+    snapshotRocks.rOpt.Destroy()
+    sRuntime.rocksTx.ReleaseSnapshot(snapshotRocks.snapshot)
+}
+
+////////////////////////////////
+// Make the path of the snapshot file of the daaScore in the directory.
+func MakeSnapshotPath(dir string, daaScore uint64) (string) {
+    return filepath.Join(dir, "snapshot-"+fmt.Sprintf("%020d", daaScore)+".jsonl.gz")
+}
+
+////////////////////////////////
+// Get the nearest snapshot file with the state at or after the opScore in the directory, and the max opScore of the state.
+func getSnapshotPathAfter(dir string, opScore uint64) (string, uint64) {
+    if dir == "" {
+        return "", 0
+    }
+    pathList, _ := filepath.Glob(filepath.Join(dir, PatternSnapshotFile))
+    sort.Strings(pathList)
+    for _, path := range pathList {
+        daaScore, err := strconv.ParseUint(strings.TrimSuffix(strings.TrimPrefix(filepath.Base(path), "snapshot-"), ".jsonl.gz"), 10, 64)
+        if err != nil {
+            continue
+        }
+        opScoreMax := daaScore*10000 + 9999
+        if opScoreMax >= opScore {
+            return path, opScoreMax
+        }
+    }
+    return "", 0
+}
+
+////////////////////////////////
+// Write all the state data in the local db to the snapshot file, gzip compressed.
+// The state is read through the point-in-time view if not nil, the current state otherwise.
+func WriteSnapshot(path string, header DataSnapshotHeaderType, snapshotRocks *SnapshotRocksType) (string, int, error) {
+    pathTmp := path + ".tmp"
+    file, err := os.Create(pathTmp)
+    if err != nil {
+        return "", 0, err
+    }
+    defer os.Remove(pathTmp)
+    defer file.Close()
+    zw := gzip.NewWriter(file)
+    hash, _ := blake2b.New256(nil)
+    w := bufio.NewWriter(io.MultiWriter(zw, hash))
+    _wLine := func(data interface{}) (error) {
+        lineJson, err := json.Marshal(data)
+        if err != nil {
+            return err
+        }
+        _, err = w.Write(append(lineJson, '\n'))
+        return err
+    }
+    header.Format = FormatSnapshot
+    header.KeyPrefixList = keyPrefixStateList
+    err = _wLine(header)
+    if err != nil {
+        return "", 0, err
+    }
+    rOpt := sRuntime.rOptRocks
+    if snapshotRocks != nil {
+        rOpt = snapshotRocks.rOpt
+    }
+    count := 0
+    for _, prefix := range keyPrefixStateList {
+        err = doIterateRocksWith(rOpt, prefix, false, func(key []byte, value []byte) (bool, error) {
+            count ++
+            return true, _wLine(dataSnapshotStateType{Key: string(key), Value: value})
+        })
+        if err != nil {
+            return "", 0, err
+        }
+    }
+    err = w.Flush()
+    if err != nil {
+        return "", 0, err
+    }
+    sum := hex.EncodeToString(hash.Sum(nil))
+    lineJson, _ := json.Marshal(dataSnapshotHashType{Count: count, Hash: sum})
+    _, err = zw.Write(append(lineJson, '\n'))
+    if err != nil {
+        return "", 0, err
+    }
+    err = zw.Close()
+    if err != nil {
+        return "", 0, err
+    }
+    err = file.Sync()
+    if err != nil {
+        return "", 0, err
+    }
+    err = os.Rename(pathTmp, path)
+    if err != nil {
+        return "", 0, err
+    }
+    return sum, count, nil
+}
+
+////////////////////////////////
+// Read the snapshot file line by line, the hash is checked at the end, fState is nil if check only.
+func readSnapshot(path string, fState func(string, []byte) (error)) (*DataSnapshotHeaderType, string, error) {
+    file, err := os.Open(path)
+    if err != nil {
+        return nil, "", err
+    }
+    defer file.Close()
+    zr, err := gzip.NewReader(file)
+    if err != nil {
+        return nil, "", err
+    }
+    defer zr.Close()
+    hash, _ := blake2b.New256(nil)
+    r := bufio.NewScanner(zr)
+    r.Buffer(make([]byte, 0, 1024*1024), lenSnapshotLineMax)
+    var header *DataSnapshotHeaderType
+    count := 0
+    for r.Scan() {
+        line := r.Bytes()
+        if header == nil {
+            header = &DataSnapshotHeaderType{}
+            err = json.Unmarshal(line, header)
+            if err != nil {
+                return nil, "", err
+            }
+            if header.Format != FormatSnapshot {
+                return nil, "", errors.New("snapshot format unsupported")
+            }
+            hash.Write(line)
+            hash.Write([]byte{'\n'})
+            continue
+        }
+        if !strings.HasPrefix(string(line), "{\"k\":") {
+            hashLine := dataSnapshotHashType{}
+            err = json.Unmarshal(line, &hashLine)
+            if err != nil {
+                return nil, "", err
+            }
+            sum := hex.EncodeToString(hash.Sum(nil))
+            if (hashLine.Hash != sum || hashLine.Count != count) {
+                return nil, "", errors.New("snapshot hash mismatch")
+            }
+            return header, sum, nil
+        }
+        hash.Write(line)
+        hash.Write([]byte{'\n'})
+        count ++
+        if fState == nil {
+            continue
+        }
+        state := dataSnapshotStateType{}
+        err = json.Unmarshal(line, &state)
+        if err != nil {
+            return nil, "", err
+        }
+        err = fState(state.Key, state.Value)
+        if err != nil {
+            return nil, "", err
+        }
+    }
+    err = r.Err()
+    if err != nil {
+        return nil, "", err
+    }
+    return nil, "", errors.New("snapshot incomplete")
+}
+
+////////////////////////////////
+// Check the hash of the snapshot file and get the header.
+func VerifySnapshot(path string) (*DataSnapshotHeaderType, string, error) {
+    return readSnapshot(path, nil)
+}
+
+////////////////////////////////
+// Load the snapshot file into the local db, the state data and the runtime vspc/rollback list are written.
+// The hash and the network are checked before any write, the local db should be empty.
+func LoadSnapshot(path string, testnet bool) (*DataSnapshotHeaderType, string, error) {
+    header, sum, err := VerifySnapshot(path)
+    if err != nil {
+        return nil, "", err
+    }
+    if header.Testnet != testnet {
+        return nil, "", errors.New("snapshot network mismatch")
+    }
+    txRocks := sRuntime.rocksTx.TransactionBegin(sRuntime.wOptRocks, sRuntime.txOptRocks, nil)
+    count := 0
+    _commit := func() (error) {
+        err := txRocks.Commit()
+        if err != nil {
+            txRocks.Rollback()
+        }
+        txRocks.Destroy()
+        return err
+    }
+    _, _, err = readSnapshot(path, func(key string, value []byte) (error) {
+        valid := false
+        for _, prefix := range keyPrefixStateList {
+            if strings.HasPrefix(key, prefix) {
+                valid = true
+                break
+            }
+        }
+        if !valid {
+            return errors.New("snapshot key invalid: " + key)
+        }
+        err := txRocks.Put([]byte(key), value)
+        if err != nil {
+            return err
+        }
+        count ++
+        if count % lenSnapshotBatchRocks != 0 {
+            return nil
+        }
+        err = _commit()
+        if err != nil {
+            return err
+        }
+        txRocks = sRuntime.rocksTx.TransactionBegin(sRuntime.wOptRocks, sRuntime.txOptRocks, nil)
+        return nil
+    })
+    if err != nil {
+        txRocks.Rollback()
+        txRocks.Destroy()
+        return nil, "", err
+    }
+    err = _commit()
+    if err != nil {
+        return nil, "", err
+    }
+    err = SetRuntimeRollbackLast(header.RollbackList)
+    if err != nil {
+        return nil, "", err
+    }
+    err = SetRuntimeVspcLast(header.VspcList)
+    if err != nil {
+        return nil, "", err
+    }
+    return header, sum, nil
+}
@@ -0,0 +1,51 @@
+
+////////////////////////////////
+package storage
+
+import (
+    "testing"
+    "path/filepath"
+    "encoding/json"
+    "kasplex-executor/config"
+)
+
+////////////////////////////////
+// The snapshot file is the state at the point-in-time view, the state saved after it is not written.
+func TestWriteSnapshotPointInTime(t *testing.T) {
+    InitRocks(config.RocksConfig{Path: t.TempDir()})
+    _save := func(balance string) {
+        stateMap := DataStateMapType{
+            StateBalanceMap: map[string]*StateBalanceType{
+                "kaspa:qzabc_TEST": &StateBalanceType{Address: "kaspa:qzabc", Tick: "TEST", Dec: 8, Balance: balance, Locked: "0"},
+            },
+        }
+        txRocks, _, err := SaveStateBatchRocksBegin(stateMap, nil)
+        if err != nil {
+            t.Fatal(err)
+        }
+        err = txRocks.Commit()
+        if err != nil {
+            t.Fatal(err)
+        }
+        txRocks.Destroy()
+    }
+    _save("1000")
+    snapshotRocks := NewSnapshotRocks()
+    _save("1")
+    path := filepath.Join(t.TempDir(), "snapshot.jsonl.gz")
+    _, count, err := WriteSnapshot(path, DataSnapshotHeaderType{DaaScore: 1000}, snapshotRocks)
+    ReleaseSnapshotRocks(snapshotRocks)
+    if (err != nil || count != 1) {
+        t.Fatalf("WriteSnapshot = %d, %v", count, err)
+    }
+    balance := ""
+    _, _, err = readSnapshot(path, func(key string, value []byte) (error) {
+        stBalance := &StateBalanceType{}
+        err := json.Unmarshal(value, stBalance)
+        balance = stBalance.Balance
+        return err
+    })
+    if (err != nil || balance != "1000") {
+        t.Fatalf("balance in the snapshot = %q, %v, want 1000", balance, err)
+    }
+}
@@ -3,8 +3,8 @@
 package storage
 
 import (
-    "sync"
     "time"
+    "strconv"
     "strings"
     //"log/slog"
     "encoding/json"
@@ -14,164 +14,39 @@ import (
 
 ////////////////////////////////
 const OpRangeBy = uint64(100000)
+const OpBucketTickBy = uint64(60480000000)  // about 7 days, the partition of oplist_by_tick and optrade.
+const OpBucketCheckpointBy = uint64(10000000000)  // 1000000 daaScore, the partition of opcheckpoint.
 
 ////////////////////////////////
-const KeyPrefixStateToken = "sttoken_"
-const KeyPrefixStateBalance = "stbalance_"
-const KeyPrefixStateMarket = "stmarket_"
-// KeyPrefixStateXxx ...
+const OpEventTrade = "trade"  // the order filled by the send.
+const OpEventCancel = "cancel"  // the order reclaimed by the lister.
 
 ////////////////////////////////
-func GetStateTokenMap(tokenMap map[string]*StateTokenType) (int64, error) {
-    keyList := [][]byte{}
-    for tick := range tokenMap {
-        keyList = append(keyList, []byte(KeyPrefixStateToken+tick))
-    }
-    mutex := new(sync.RWMutex)
-    mtsBatch, err := doGetBatchRocks(len(keyList), 0, func(iStart int, iEnd int, rdb *gorocksdb.TransactionDB, rro *gorocksdb.ReadOptions) (error) {
-        for i := iStart; i < iEnd; i ++ {
-            row, err := rdb.Get(rro, keyList[i])
-            if err != nil {
-                return err
-            }
-            dataByte := row.Data()
-            if dataByte == nil {
-                continue
-            }
-            decoded := StateTokenType{}
-            err = json.Unmarshal(dataByte, &decoded)
-            if err != nil {
-                return err
-            }
-            mutex.Lock()
-            tokenMap[decoded.Tick] = &decoded
-            mutex.Unlock()
-        }
-        return nil
-    })
-    if err != nil {
-        return 0, err
-    }
-    return mtsBatch, nil
-}
+const TokenModIssue = "issue"  // the token minted only by the owner with the issue op.
 
 ////////////////////////////////
-func GetStateBalanceMap(balanceMap map[string]*StateBalanceType) (int64, error) {
-    keyList := [][]byte{}
-    for addrTick := range balanceMap {
-        keyList = append(keyList, []byte(KeyPrefixStateBalance+addrTick))
-    }
-    mutex := new(sync.RWMutex)
-    mtsBatch, err := doGetBatchRocks(len(keyList), 0, func(iStart int, iEnd int, rdb *gorocksdb.TransactionDB, rro *gorocksdb.ReadOptions) (error) {
-        for i := iStart; i < iEnd; i ++ {
-            row, err := rdb.Get(rro, keyList[i])
-            if err != nil {
-                return err
-            }
-            dataByte := row.Data()
-            if dataByte == nil {
-                continue
-            }
-            decoded := StateBalanceType{}
-            err = json.Unmarshal(dataByte, &decoded)
-            if err != nil {
-                return err
-            }
-            mutex.Lock()
-            balanceMap[decoded.Address+"_"+decoded.Tick] = &decoded
-            mutex.Unlock()
-        }
-        return nil
-    })
-    if err != nil {
-        return 0, err
-    }
-    return mtsBatch, nil
-}
-
-////////////////////////////////
-func GetStateMarketMap(marketMap map[string]*StateMarketType) (int64, error) {
-    keyList := [][]byte{}
-    for tickAddrTxid := range marketMap {
-        keyList = append(keyList, []byte(KeyPrefixStateMarket+tickAddrTxid))
-    }
-    mutex := new(sync.RWMutex)
-    mtsBatch, err := doGetBatchRocks(len(keyList), 0, func(iStart int, iEnd int, rdb *gorocksdb.TransactionDB, rro *gorocksdb.ReadOptions) (error) {
-        for i := iStart; i < iEnd; i ++ {
-            row, err := rdb.Get(rro, keyList[i])
-            if err != nil {
-                return err
-            }
-            dataByte := row.Data()
-            if dataByte == nil {
-                continue
-            }
-            decoded := StateMarketType{}
-            err = json.Unmarshal(dataByte, &decoded)
-            if err != nil {
-                return err
-            }
-            mutex.Lock()
-            marketMap[decoded.Tick+"_"+decoded.TAddr+"_"+decoded.UTxId] = &decoded
-            mutex.Unlock()
-        }
-        return nil
-    })
-    if err != nil {
-        return 0, err
-    }
-    return mtsBatch, nil
-}
-
-////////////////////////////////
-// GetStateXxx ...
-
-////////////////////////////////
-func CopyDataStateMap(stateMapFrom DataStateMapType, stateMapTo *DataStateMapType) {
-    stateMapTo.StateTokenMap = make(map[string]*StateTokenType)
-    stateMapTo.StateBalanceMap = make(map[string]*StateBalanceType)
-    stateMapTo.StateMarketMap = make(map[string]*StateMarketType)
-    // stateMapTo.StateXxxMap ...
-    for key, stToken := range stateMapFrom.StateTokenMap {
-        if stToken == nil {
-            stateMapTo.StateTokenMap[key] = nil
-            continue
-        }
-        stData := *stToken
-        stateMapTo.StateTokenMap[key] = &stData
-    }
-    for key, stBalance := range stateMapFrom.StateBalanceMap {
-        if stBalance == nil {
-            stateMapTo.StateBalanceMap[key] = nil
-            continue
-        }
-        stData := *stBalance
-        stateMapTo.StateBalanceMap[key] = &stData
-    }
-    for key, stMarket := range stateMapFrom.StateMarketMap {
-        if stMarket == nil {
-            stateMapTo.StateMarketMap[key] = nil
-            continue
-        }
-        stData := *stMarket
-        stateMapTo.StateMarketMap[key] = &stData
-    }
-    // StateXxx ...
-}
+const KeyPrefixStateToken = "sttoken_"
+const KeyPrefixStateBalance = "stbalance_"
+const KeyPrefixStateMarket = "stmarket_"
+const KeyPrefixStateBlacklist = "stblacklist_"
+const KeyPrefixStateCollection = "stcollection_"
+const KeyPrefixStateNft = "stnft_"
+// KeyPrefixStateXxx ...
 
 ////////////////////////////////
-func SaveStateBatchCassa(stateMap DataStateMapType) (int64, error) {
-    mtss := time.Now().UnixMilli()
-    keyList := make([]string, 0, len(stateMap.StateTokenMap))
-    for key := range stateMap.StateTokenMap {
-        keyList = append(keyList, key)
-    }
-    _, err := startExecuteBatchCassa(len(keyList), func(batch *gocql.Batch, i int) (error) {
-        stToken := stateMap.StateTokenMap[keyList[i]]
-        tick := keyList[i]
+// The state kinds, registered in the order of the load, the save and the snapshot.
+var StateKindToken = registerStateKind(&StateKindType[StateTokenType]{
+    keyPrefix: KeyPrefixStateToken,
+    mapOf: func(stateMap *DataStateMapType) (*map[string]*StateTokenType) {
+        return &stateMap.StateTokenMap
+    },
+    makeStLine: func(stToken *StateTokenType) (string) {
+        return makeStLineToken(stToken, false)
+    },
+    batchCassa: func(batch *gocql.Batch, tick string, stToken *StateTokenType) {
         if stToken == nil {
             batch.Query(cqlnDeleteStateToken, tick[:2], tick)
-            return nil
+            return
         }
         meta := &StateTokenMetaType{
             Max: stToken.Max,
@@ -183,49 +58,261 @@ func SaveStateBatchCassa(stateMap DataStateMapType) (int64, error) {
             TxId: stToken.TxId,
             OpAdd: stToken.OpAdd,
             MtsAdd: stToken.MtsAdd,
+            Burned: stToken.Burned,
+            Mod: stToken.Mod,
+            Owner: stToken.Owner,
         }
         metaJson, _ := json.Marshal(meta)
         batch.Query(cqlnSaveStateToken, tick[:2], tick, string(metaJson), stToken.Minted, stToken.OpMod, stToken.MtsMod)
-        return nil
-    })
-    if err != nil {
-        return 0, err
-    }
-    keyList = make([]string, 0, len(stateMap.StateBalanceMap))
-    for key := range stateMap.StateBalanceMap {
-        keyList = append(keyList, key)
-    }
-    _, err = startExecuteBatchCassa(len(keyList), func(batch *gocql.Batch, i int) (error) {
-        stBalance := stateMap.StateBalanceMap[keyList[i]]
-        key := strings.Split(keyList[i], "_")
+    },
+    cqlnGetAll: cqlnGetStateTokenAll,
+    scanCassa: func(row gocql.Scanner) (string, *StateTokenType, error) {
+        stToken := StateTokenType{}
+        var metaJson string
+        err := row.Scan(&stToken.Tick, &metaJson, &stToken.Minted, &stToken.OpMod, &stToken.MtsMod)
+        if err != nil {
+            return "", nil, err
+        }
+        meta := StateTokenMetaType{}
+        err = json.Unmarshal([]byte(metaJson), &meta)
+        if err != nil {
+            return "", nil, err
+        }
+        stToken.Max = meta.Max
+        stToken.Lim = meta.Lim
+        stToken.Pre = meta.Pre
+        stToken.Dec = meta.Dec
+        stToken.From = meta.From
+        stToken.To = meta.To
+        stToken.TxId = meta.TxId
+        stToken.OpAdd = meta.OpAdd
+        stToken.MtsAdd = meta.MtsAdd
+        stToken.Burned = meta.Burned
+        stToken.Mod = meta.Mod
+        stToken.Owner = meta.Owner
+        return stToken.Tick, &stToken, nil
+    },
+})
+var StateKindBalance = registerStateKind(&StateKindType[StateBalanceType]{
+    keyPrefix: KeyPrefixStateBalance,
+    mapOf: func(stateMap *DataStateMapType) (*map[string]*StateBalanceType) {
+        return &stateMap.StateBalanceMap
+    },
+    makeStLine: func(stBalance *StateBalanceType) (string) {
+        return strconv.Itoa(stBalance.Dec) +","+ stBalance.Balance +","+ stBalance.Locked +","+ strconv.FormatUint(stBalance.OpMod, 10)
+    },
+    batchCassa: func(batch *gocql.Batch, addrTick string, stBalance *StateBalanceType) {
+        key := strings.Split(addrTick, "_")
         if stBalance == nil {
             batch.Query(cqlnDeleteStateBalance, key[0], key[1])
-            return nil
+            return
         }
         batch.Query(cqlnSaveStateBalance, key[0], key[1], stBalance.Dec, stBalance.Balance, stBalance.Locked, stBalance.OpMod)
-        return nil
-    })
-    if err != nil {
-        return 0, err
-    }
-    keyList = make([]string, 0, len(stateMap.StateMarketMap))
-    for key := range stateMap.StateMarketMap {
-        keyList = append(keyList, key)
-    }
-    _, err = startExecuteBatchCassa(len(keyList), func(batch *gocql.Batch, i int) (error) {
-        stMarket := stateMap.StateMarketMap[keyList[i]]
-        key := strings.Split(keyList[i], "_")
+    },
+    cqlnGetAll: cqlnGetStateBalanceAll,
+    scanCassa: func(row gocql.Scanner) (string, *StateBalanceType, error) {
+        stBalance := StateBalanceType{}
+        err := row.Scan(&stBalance.Address, &stBalance.Tick, &stBalance.Dec, &stBalance.Balance, &stBalance.Locked, &stBalance.OpMod)
+        if err != nil {
+            return "", nil, err
+        }
+        return stBalance.Address+"_"+stBalance.Tick, &stBalance, nil
+    },
+})
+var StateKindMarket = registerStateKind(&StateKindType[StateMarketType]{
+    keyPrefix: KeyPrefixStateMarket,
+    mapOf: func(stateMap *DataStateMapType) (*map[string]*StateMarketType) {
+        return &stateMap.StateMarketMap
+    },
+    makeStLine: func(stMarket *StateMarketType) (string) {
+        return stMarket.UAddr +","+ stMarket.UAmt +","+ stMarket.TAmt +","+ strconv.FormatUint(stMarket.OpAdd, 10)
+    },
+    batchCassa: func(batch *gocql.Batch, tickAddrTxid string, stMarket *StateMarketType) {
+        key := strings.Split(tickAddrTxid, "_")
         if stMarket == nil {
             batch.Query(cqlnDeleteStateMarket, key[0], key[1]+"_"+key[2])
-            return nil
+            return
         }
         batch.Query(cqlnSaveStateMarket, key[0], key[1]+"_"+key[2], stMarket.UAddr, stMarket.UAmt, stMarket.UScript, stMarket.TAmt, stMarket.OpAdd)
-        return nil
-    })
-    if err != nil {
-        return 0, err
-    }    
-    // StateXxx ...
+    },
+    cqlnGetAll: cqlnGetStateMarketAll,
+    scanCassa: func(row gocql.Scanner) (string, *StateMarketType, error) {
+        stMarket := StateMarketType{}
+        var tAddrUTxId string
+        err := row.Scan(&stMarket.Tick, &tAddrUTxId, &stMarket.UAddr, &stMarket.UAmt, &stMarket.UScript, &stMarket.TAmt, &stMarket.OpAdd)
+        if err != nil {
+            return "", nil, err
+        }
+        key := strings.SplitN(tAddrUTxId, "_", 2)
+        if len(key) < 2 {
+            return "", nil, nil
+        }
+        stMarket.TAddr = key[0]
+        stMarket.UTxId = key[1]
+        return stMarket.Tick+"_"+tAddrUTxId, &stMarket, nil
+    },
+})
+var StateKindBlacklist = registerStateKind(&StateKindType[StateBlacklistType]{
+    keyPrefix: KeyPrefixStateBlacklist,
+    mapOf: func(stateMap *DataStateMapType) (*map[string]*StateBlacklistType) {
+        return &stateMap.StateBlacklistMap
+    },
+    makeStLine: func(stBlacklist *StateBlacklistType) (string) {
+        return strconv.FormatUint(stBlacklist.OpAdd, 10)
+    },
+    batchCassa: func(batch *gocql.Batch, tickAddr string, stBlacklist *StateBlacklistType) {
+        key := strings.SplitN(tickAddr, "_", 2)
+        if stBlacklist == nil {
+            batch.Query(cqlnDeleteStateBlacklist, key[0], key[1])
+            return
+        }
+        batch.Query(cqlnSaveStateBlacklist, key[0], key[1], stBlacklist.OpAdd)
+    },
+    cqlnGetAll: cqlnGetStateBlacklistAll,
+    scanCassa: func(row gocql.Scanner) (string, *StateBlacklistType, error) {
+        stBlacklist := StateBlacklistType{}
+        err := row.Scan(&stBlacklist.Tick, &stBlacklist.Address, &stBlacklist.OpAdd)
+        if err != nil {
+            return "", nil, err
+        }
+        return stBlacklist.Tick+"_"+stBlacklist.Address, &stBlacklist, nil
+    },
+})
+var StateKindCollection = registerStateKind(&StateKindType[StateCollectionType]{
+    keyPrefix: KeyPrefixStateCollection,
+    mapOf: func(stateMap *DataStateMapType) (*map[string]*StateCollectionType) {
+        return &stateMap.StateCollectionMap
+    },
+    makeStLine: func(stCollection *StateCollectionType) (string) {
+        return stCollection.Max +","+ stCollection.Buri +","+ stCollection.From +","+ stCollection.Minted +","+ strconv.FormatUint(stCollection.OpMod, 10)
+    },
+    batchCassa: func(batch *gocql.Batch, tick string, stCollection *StateCollectionType) {
+        if stCollection == nil {
+            batch.Query(cqlnDeleteStateCollection, tick)
+            return
+        }
+        meta := &StateCollectionMetaType{
+            Max: stCollection.Max,
+            Buri: stCollection.Buri,
+            From: stCollection.From,
+            TxId: stCollection.TxId,
+            OpAdd: stCollection.OpAdd,
+            MtsAdd: stCollection.MtsAdd,
+        }
+        metaJson, _ := json.Marshal(meta)
+        batch.Query(cqlnSaveStateCollection, tick, string(metaJson), stCollection.Minted, stCollection.OpMod, stCollection.MtsMod)
+    },
+    cqlnGetAll: cqlnGetStateCollectionAll,
+    scanCassa: func(row gocql.Scanner) (string, *StateCollectionType, error) {
+        stCollection := StateCollectionType{}
+        var metaJson string
+        err := row.Scan(&stCollection.Tick, &metaJson, &stCollection.Minted, &stCollection.OpMod, &stCollection.MtsMod)
+        if err != nil {
+            return "", nil, err
+        }
+        meta := StateCollectionMetaType{}
+        err = json.Unmarshal([]byte(metaJson), &meta)
+        if err != nil {
+            return "", nil, err
+        }
+        stCollection.Max = meta.Max
+        stCollection.Buri = meta.Buri
+        stCollection.From = meta.From
+        stCollection.TxId = meta.TxId
+        stCollection.OpAdd = meta.OpAdd
+        stCollection.MtsAdd = meta.MtsAdd
+        return stCollection.Tick, &stCollection, nil
+    },
+})
+var StateKindNft = registerStateKind(&StateKindType[StateNftType]{
+    keyPrefix: KeyPrefixStateNft,
+    mapOf: func(stateMap *DataStateMapType) (*map[string]*StateNftType) {
+        return &stateMap.StateNftMap
+    },
+    makeStLine: func(stNft *StateNftType) (string) {
+        return stNft.Owner +","+ strconv.FormatUint(stNft.OpMod, 10)
+    },
+    batchCassa: func(batch *gocql.Batch, tickTokenId string, stNft *StateNftType) {
+        key := strings.SplitN(tickTokenId, "_", 2)
+        if stNft == nil {
+            batch.Query(cqlnDeleteStateNft, key[0], key[1])
+            return
+        }
+        batch.Query(cqlnSaveStateNft, key[0], key[1], stNft.Owner, stNft.OpMod)
+    },
+    cqlnGetAll: cqlnGetStateNftAll,
+    scanCassa: func(row gocql.Scanner) (string, *StateNftType, error) {
+        stNft := StateNftType{}
+        err := row.Scan(&stNft.Tick, &stNft.TokenId, &stNft.Owner, &stNft.OpMod)
+        if err != nil {
+            return "", nil, err
+        }
+        return stNft.Tick+"_"+stNft.TokenId, &stNft, nil
+    },
+})
+// StateKindXxx ...
+
+////////////////////////////////
+// The line of the token in the checkpoint, the deploy op has the meta but not the burned supply.
+func makeStLineToken(stToken *StateTokenType, isDeploy bool) (string) {
+    stLine := ""
+    opScore := stToken.OpMod
+    if isDeploy {
+        opScore = stToken.OpAdd
+        stLine += stToken.Max + ","
+        stLine += stToken.Lim + ","
+        stLine += stToken.Pre + ","
+        stLine += strconv.Itoa(stToken.Dec) + ","
+        stLine += stToken.From + ","
+        stLine += stToken.To + ","
+    }
+    stLine += stToken.Minted + ","
+    stLine += strconv.FormatUint(opScore, 10)
+    // The burned supply and the mode are appended only if any, the line of the token in the mint mode never burned is unchanged.
+    if (!isDeploy && (stToken.Burned != "" || stToken.Mod != "")) {
+        stLine += "," + stToken.Burned
+    }
+    if stToken.Mod != "" {
+        stLine += "," + stToken.Mod + "," + stToken.Owner
+    }
+    return stLine
+}
+
+////////////////////////////////
+// Make the line of the token deployed in the checkpoint.
+func MakeStLineTokenDeploy(key string, stToken *StateTokenType) (string) {
+    stLine := KeyPrefixStateToken + key
+    if stToken == nil {
+        return stLine
+    }
+    return stLine + "," + makeStLineToken(stToken, true)
+}
+
+////////////////////////////////
+// Get all the state data in the local db, key by key.
+func GetStateRocksAll() (map[string]json.RawMessage, error) {
+    stateMap := map[string]json.RawMessage{}
+    for _, prefix := range keyPrefixStateList {
+        err := doIterateRocks(prefix, false, func(key []byte, value []byte) (bool, error) {
+            stateMap[string(key)] = json.RawMessage(value)
+            return true, nil
+        })
+        if err != nil {
+            return nil, err
+        }
+    }
+    return stateMap, nil
+}
+
+////////////////////////////////
+func SaveStateBatchCassa(stateMap DataStateMapType) (int64, error) {
+    mtss := time.Now().UnixMilli()
+    for _, kind := range stateKindList {
+        err := kind.saveCassa(stateMap)
+        if err != nil {
+            return 0, err
+        }
+    }
     return time.Now().UnixMilli() - mtss, nil
 }
 
@@ -243,6 +330,7 @@ func SaveOpDataBatchCassa(opDataList []DataOperationType) (int64, error) {
             OpScore: opDataList[i].OpScore,
             OpAccept: opDataList[i].OpAccept,
             OpError: opDataList[i].OpError,
+            OpEvent: opDataList[i].OpEvent,
             Checkpoint: opDataList[i].Checkpoint,
         }
         stateJson, _ := json.Marshal(state)
@@ -263,6 +351,53 @@ func SaveOpDataBatchCassa(opDataList []DataOperationType) (int64, error) {
         // xxxAffc ...
         opRange := opDataList[i].OpScore / OpRangeBy
         batch.Query(cqlnSaveOpList, opRange, opDataList[i].OpScore, opDataList[i].TxId, stateJsonMap[opDataList[i].TxId], scriptJsonMap[opDataList[i].TxId], tickAffc, addressAffc)
+        // Index the checkpoint of the accepted op, looked up by the opScore or daaScore.
+        if (opDataList[i].OpAccept == 1 && opDataList[i].Checkpoint != "") {
+            batch.Query(cqlnSaveOpCheckpoint, opDataList[i].OpScore/OpBucketCheckpointBy, opDataList[i].OpScore, opDataList[i].TxId, opDataList[i].Checkpoint)
+        }
+        // Index the op by the tick, of the KRC-20 token only.
+        if (opDataList[i].OpScript[0].Tick != "" && opDataList[i].OpScript[0].P == "KRC-20") {
+            opBucket := opDataList[i].OpScore / OpBucketTickBy
+            batch.Query(cqlnSaveOpListByTick, opDataList[i].OpScript[0].Tick, opBucket, opDataList[i].OpScore, opDataList[i].TxId, opDataList[i].OpScript[0].Op, opDataList[i].OpAccept == 1, stateJsonMap[opDataList[i].TxId], scriptJsonMap[opDataList[i].TxId])
+        }
+        return nil
+    })
+    if err != nil {
+        return 0, err
+    }
+    // Index the op by each address affected.
+    affcList := [][3]string{}
+    iOpList := []int{}
+    for i := range opDataList {
+        for _, affc := range opDataList[i].SsInfo.AddressAffc {
+            address, tick, balance := parseAddressAffc(affc)
+            if address == "" {
+                continue
+            }
+            affcList = append(affcList, [3]string{address, tick, balance})
+            iOpList = append(iOpList, i)
+        }
+    }
+    _, err = startExecuteBatchCassa(len(affcList), func(batch *gocql.Batch, j int) (error) {
+        i := iOpList[j]
+        batch.Query(cqlnSaveOpListByAddress, affcList[j][0], opDataList[i].OpScore, opDataList[i].TxId, opDataList[i].OpScript[0].Op, affcList[j][1], affcList[j][2], stateJsonMap[opDataList[i].TxId], scriptJsonMap[opDataList[i].TxId])
+        return nil
+    })
+    if err != nil {
+        return 0, err
+    }
+    // Record the order filled or canceled in the trade ledger.
+    iTradeList := []int{}
+    for i := range opDataList {
+        if (opDataList[i].OpAccept == 1 && opDataList[i].OpEvent != "") {
+            iTradeList = append(iTradeList, i)
+        }
+    }
+    _, err = startExecuteBatchCassa(len(iTradeList), func(batch *gocql.Batch, j int) (error) {
+        i := iTradeList[j]
+        opScript := opDataList[i].OpScript[0]
+        opBucket := opDataList[i].OpScore / OpBucketTickBy
+        batch.Query(cqlnSaveOpTrade, opScript.Tick, opBucket, opDataList[i].OpScore, opDataList[i].TxId, opDataList[i].OpEvent, opScript.From, opScript.To, opScript.Amt, opScript.Price, opDataList[i].MtsAdd)
         return nil
     })
     if err != nil {
@@ -271,12 +406,56 @@ func SaveOpDataBatchCassa(opDataList []DataOperationType) (int64, error) {
     return time.Now().UnixMilli() - mtss, nil
 }
 
+////////////////////////////////
+// Parse the address affected "address_tick=balance".
+func parseAddressAffc(affc string) (string, string, string) {
+    list := strings.SplitN(affc, "=", 2)
+    key := strings.SplitN(list[0], "_", 2)
+    if (len(list) < 2 || len(key) < 2) {
+        return "", "", ""
+    }
+    return key[0], key[1], list[1]
+}
+
 ////////////////////////////////
 func DeleteOpDataBatchCassa(opScoreList []uint64, txIdList []string) (int64, error) {
     mtss := time.Now().UnixMilli()
-    _, err := startExecuteBatchCassa(len(opScoreList), func(batch *gocql.Batch, i int) (error) {
+    // Remove the op indexes by the address affected and the tick, read from oplist before deleted.
+    keyList, err := getOpListIndexKey(opScoreList)
+    if err != nil {
+        return 0, err
+    }
+    affcList := [][2]interface{}{}
+    for _, key := range keyList {
+        for _, address := range key.addressList {
+            affcList = append(affcList, [2]interface{}{address, key.opScore})
+        }
+    }
+    _, err = startExecuteBatchCassa(len(affcList), func(batch *gocql.Batch, i int) (error) {
+        batch.Query(cqlnDeleteOpListByAddress, affcList[i][0], affcList[i][1])
+        return nil
+    })
+    if err != nil {
+        return 0, err
+    }
+    _, err = startExecuteBatchCassa(len(keyList), func(batch *gocql.Batch, i int) (error) {
+        if keyList[i].tick == "" {
+            return nil
+        }
+        opBucket := keyList[i].opScore / OpBucketTickBy
+        batch.Query(cqlnDeleteOpListByTick, keyList[i].tick, opBucket, keyList[i].opScore)
+        if keyList[i].op == "send" {
+            batch.Query(cqlnDeleteOpTrade, keyList[i].tick, opBucket, keyList[i].opScore)
+        }
+        return nil
+    })
+    if err != nil {
+        return 0, err
+    }
+    _, err = startExecuteBatchCassa(len(opScoreList), func(batch *gocql.Batch, i int) (error) {
         opRange := opScoreList[i] / OpRangeBy
         batch.Query(cqlnDeleteOpList, opRange, opScoreList[i])
+        batch.Query(cqlnDeleteOpCheckpoint, opScoreList[i]/OpBucketCheckpointBy, opScoreList[i])
         return nil
     })
     if err != nil {
@@ -298,53 +477,19 @@ func SaveStateBatchRocksBegin(stateMap DataStateMapType, txRocks *gorocksdb.Tran
     if txRocks == nil {
         txRocks = sRuntime.rocksTx.TransactionBegin(sRuntime.wOptRocks, sRuntime.txOptRocks, nil)
     }
-    var err error
-    var valueJson []byte
-    for key, token := range stateMap.StateTokenMap {
-        key = KeyPrefixStateToken + key
-        if token == nil {
-            err = txRocks.Delete([]byte(key))
-        } else {
-            valueJson, _ = json.Marshal(token)
-            err = txRocks.Put([]byte(key), valueJson)
-        }
-        if err != nil {
-            txRocks.Rollback()
-            return txRocks, 0, err
-        }
-    }
-    for key, balance := range stateMap.StateBalanceMap {
-        key = KeyPrefixStateBalance + key
-        if balance == nil {
-            err = txRocks.Delete([]byte(key))
-        } else {
-            valueJson, _ = json.Marshal(balance)
-            err = txRocks.Put([]byte(key), valueJson)
-        }
-        if err != nil {
-            txRocks.Rollback()
-            return txRocks, 0, err
-        }
-    }
-    for key, market := range stateMap.StateMarketMap {
-        key = KeyPrefixStateMarket + key
-        if market == nil {
-            err = txRocks.Delete([]byte(key))
-        } else {
-            valueJson, _ = json.Marshal(market)
-            err = txRocks.Put([]byte(key), valueJson)
-        }
+    for _, kind := range stateKindList {
+        err := kind.saveRocks(stateMap, txRocks)
         if err != nil {
             txRocks.Rollback()
             return txRocks, 0, err
         }
     }
-    // StateXxx ...
     return txRocks, time.Now().UnixMilli() - mtss, nil
 }
 
 ////////////////////////////////
-func SaveOpStateBatch(opDataList []DataOperationType, stateMap DataStateMapType) ([]int64, error) {
+// Save the op and state data of the batch, the rollback record is committed with the state in the local db.
+func SaveOpStateBatch(opDataList []DataOperationType, stateMap DataStateMapType, rollback DataRollbackType, vspcList []DataVspcType) ([]int64, error) {
     mtsBatchList := [4]int64{}
     mtsBatchList[0] = time.Now().UnixMilli()
     txRocks, _, err := SaveStateBatchRocksBegin(stateMap, nil)
@@ -352,6 +497,11 @@ func SaveOpStateBatch(opDataList []DataOperationType, stateMap DataStateMapType)
     if err != nil {
         return nil, err
     }
+    err = saveRollbackRecord(rollback, vspcList, txRocks)
+    if err != nil {
+        txRocks.Rollback()
+        return nil, err
+    }
     mtsBatchList[1] = time.Now().UnixMilli()
     _, err = SaveStateBatchCassa(stateMap)
     if err != nil {
@@ -378,6 +528,7 @@ func SaveOpStateBatch(opDataList []DataOperationType, stateMap DataStateMapType)
 }
 
 ////////////////////////////////
+// Restore the state data before the batch and remove the op data, the rollback record is deleted with the state in the local db.
 func RollbackOpStateBatch(rollback DataRollbackType) (int64, error) {
     mtss := time.Now().UnixMilli()
     txRocks, _, err := SaveStateBatchRocksBegin(rollback.StateMapBefore, nil)
@@ -385,6 +536,11 @@ func RollbackOpStateBatch(rollback DataRollbackType) (int64, error) {
     if err != nil {
         return 0, err
     }
+    err = deleteRollbackRecord(rollback.DaaScoreStart, txRocks)
+    if err != nil {
+        txRocks.Rollback()
+        return 0, err
+    }
     _, err = SaveStateBatchCassa(rollback.StateMapBefore)
     if err != nil {
         txRocks.Rollback()
@@ -402,3 +558,58 @@ func RollbackOpStateBatch(rollback DataRollbackType) (int64, error) {
     }
     return time.Now().UnixMilli() - mtss, nil
 }
+
+////////////////////////////////
+// Keys of the op indexes, read from oplist.
+type opIndexKeyType struct {
+    opScore uint64
+    op string
+    tick string
+    addressList []string
+}
+
+////////////////////////////////
+// Get the keys of the op indexes of the op list in oplist.
+func getOpListIndexKey(opScoreList []uint64) ([]opIndexKeyType, error) {
+    keyList := []opIndexKeyType{}
+    if (len(opScoreList) <= 0 || sRuntime.sessionCassa == nil) {
+        return keyList, nil
+    }
+    opScoreMap := map[uint64][]string{}
+    for _, opScore := range opScoreList {
+        opRange := opScore / OpRangeBy
+        opScoreMap[opRange] = append(opScoreMap[opRange], strconv.FormatUint(opScore, 10))
+    }
+    for opRange, opScoreIn := range opScoreMap {
+        cql := strings.Replace(cqlnGetOpListIndexKey, "{opscoreIn}", strings.Join(opScoreIn, ","), 1)
+        row := sRuntime.sessionCassa.Query(cql, opRange).Iter().Scanner()
+        for row.Next() {
+            key := opIndexKeyType{}
+            var scriptJson string
+            var addressAffc string
+            err := row.Scan(&key.opScore, &scriptJson, &addressAffc)
+            if err != nil {
+                return nil, err
+            }
+            script := DataScriptType{}
+            json.Unmarshal([]byte(scriptJson), &script)
+            key.op = script.Op
+            key.tick = script.Tick
+            if addressAffc != "" {
+                for _, affc := range strings.Split(addressAffc, ",") {
+                    address, _, _ := parseAddressAffc(affc)
+                    if address == "" {
+                        continue
+                    }
+                    key.addressList = append(key.addressList, address)
+                }
+            }
+            keyList = append(keyList, key)
+        }
+        err := row.Err()
+        if err != nil {
+            return nil, err
+        }
+    }
+    return keyList, nil
+}
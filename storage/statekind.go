@@ -0,0 +1,302 @@
+
+////////////////////////////////
+package storage
+
+import (
+    "sync"
+    "time"
+    "strings"
+    "encoding/json"
+    "github.com/gocql/gocql"
+    "github.com/tecbot/gorocksdb"
+)
+
+////////////////////////////////
+// The kind of the state data, each is a map in DataStateMapType keyed without the prefix.
+type StateKind interface {
+    Name() (string)
+    KeyPrefix() (string)
+    Len(DataStateMapType) (int)
+    ValueMap(DataStateMapType) (map[string]interface{})
+    makeMap(*DataStateMapType)
+    getBatchRocks(DataStateMapType) (error)
+    copyMap(DataStateMapType, *DataStateMapType)
+    markCreated(DataStateMapType, *DataStateMapType)
+    saveRocks(DataStateMapType, *gorocksdb.Transaction) (error)
+    saveCassa(DataStateMapType) (error)
+    getRocksAll(*DataStateMapType) (error)
+    getCassaAll(*DataStateMapType) (error)
+    diffMap(DataStateMapType, DataStateMapType, *DataStateMapType, *DataStateMapType)
+}
+
+////////////////////////////////
+// The state kind declares the key prefix, the map in DataStateMapType, the line in the checkpoint and the mapping in the cluster db.
+type StateKindType[T comparable] struct {
+    keyPrefix string
+    mapOf func(*DataStateMapType) (*map[string]*T)
+    makeStLine func(*T) (string)  // the values of the line, after the key.
+    batchCassa func(*gocql.Batch, string, *T)  // delete if the value is nil.
+    cqlnGetAll string
+    scanCassa func(gocql.Scanner) (string, *T, error)  // skipped if the key is empty.
+}
+
+////////////////////////////////
+var stateKindList = []StateKind{}
+var keyPrefixStateList = []string{}
+
+////////////////////////////////
+// Register the state kind, the order of the registration is the order of the load, the save and the snapshot.
+func registerStateKind[T comparable](kind *StateKindType[T]) (*StateKindType[T]) {
+    stateKindList = append(stateKindList, kind)
+    keyPrefixStateList = append(keyPrefixStateList, kind.keyPrefix)
+    return kind
+}
+
+////////////////////////////////
+func GetStateKindList() ([]StateKind) {
+    return stateKindList
+}
+
+////////////////////////////////
+// Make the state map with the empty map of each kind.
+func NewDataStateMap() (DataStateMapType) {
+    stateMap := DataStateMapType{}
+    for _, kind := range stateKindList {
+        kind.makeMap(&stateMap)
+    }
+    return stateMap
+}
+
+////////////////////////////////
+// Get the state data of the keys prepared in the map of each kind, in the local db.
+func GetStateMap(stateMap DataStateMapType) (int64, error) {
+    mtss := time.Now().UnixMilli()
+    for _, kind := range stateKindList {
+        err := kind.getBatchRocks(stateMap)
+        if err != nil {
+            return 0, err
+        }
+    }
+    return time.Now().UnixMilli() - mtss, nil
+}
+
+////////////////////////////////
+func CopyDataStateMap(stateMapFrom DataStateMapType, stateMapTo *DataStateMapType) {
+    for _, kind := range stateKindList {
+        kind.copyMap(stateMapFrom, stateMapTo)
+    }
+}
+
+////////////////////////////////
+// Mark the keys created in executing but not prepared before as nil, to be deleted by the rollback.
+func MarkDataStateMapCreated(stateMap DataStateMapType, stateMapBefore *DataStateMapType) {
+    for _, kind := range stateKindList {
+        kind.markCreated(stateMap, stateMapBefore)
+    }
+}
+
+////////////////////////////////
+// Append the line of the state to the lines, or replace the line of the same key if after executed.
+func AppendStLine(stLine []string, keyFull string, line string, isAfter bool) ([]string) {
+    iExists := -1
+    list := []string{}
+    for i, lineExists := range stLine {
+        list = strings.SplitN(lineExists, ",", 2)
+        if list[0] == keyFull {
+            iExists = i
+            break
+        }
+    }
+    if iExists < 0 {
+        return append(stLine, line)
+    }
+    if isAfter {
+        stLine[iExists] = line
+    }
+    return stLine
+}
+
+////////////////////////////////
+func (kind *StateKindType[T]) Name() (string) {
+    return strings.TrimSuffix(kind.keyPrefix, "_")
+}
+
+////////////////////////////////
+func (kind *StateKindType[T]) KeyPrefix() (string) {
+    return kind.keyPrefix
+}
+
+////////////////////////////////
+func (kind *StateKindType[T]) Len(stateMap DataStateMapType) (int) {
+    return len(*kind.mapOf(&stateMap))
+}
+
+////////////////////////////////
+func (kind *StateKindType[T]) ValueMap(stateMap DataStateMapType) (map[string]interface{}) {
+    valueMap := map[string]interface{}{}
+    for key, value := range *kind.mapOf(&stateMap) {
+        valueMap[key] = value
+    }
+    return valueMap
+}
+
+////////////////////////////////
+// Make the line of the state in the checkpoint, the key only if the state is nil.
+func (kind *StateKindType[T]) MakeStLine(key string, st *T) (string) {
+    stLine := kind.keyPrefix + key
+    if st == nil {
+        return stLine
+    }
+    return stLine + "," + kind.makeStLine(st)
+}
+
+////////////////////////////////
+func (kind *StateKindType[T]) AppendStLine(stLine []string, key string, st *T, isAfter bool) ([]string) {
+    return AppendStLine(stLine, kind.keyPrefix+key, kind.MakeStLine(key, st), isAfter)
+}
+
+////////////////////////////////
+func (kind *StateKindType[T]) makeMap(stateMap *DataStateMapType) {
+    *kind.mapOf(stateMap) = make(map[string]*T)
+}
+
+////////////////////////////////
+func (kind *StateKindType[T]) getBatchRocks(stateMap DataStateMapType) (error) {
+    valueMap := *kind.mapOf(&stateMap)
+    keyList := make([]string, 0, len(valueMap))
+    for key := range valueMap {
+        keyList = append(keyList, key)
+    }
+    mutex := new(sync.RWMutex)
+    _, err := doGetBatchRocks(len(keyList), 0, func(iStart int, iEnd int, rdb *gorocksdb.TransactionDB, rro *gorocksdb.ReadOptions) (error) {
+        for i := iStart; i < iEnd; i ++ {
+            row, err := rdb.Get(rro, []byte(kind.keyPrefix+keyList[i]))
+            if err != nil {
+                return err
+            }
+            dataByte := row.Data()
+            if dataByte == nil {
+                continue
+            }
+            decoded := new(T)
+            err = json.Unmarshal(dataByte, decoded)
+            if err != nil {
+                return err
+            }
+            mutex.Lock()
+            valueMap[keyList[i]] = decoded
+            mutex.Unlock()
+        }
+        return nil
+    })
+    return err
+}
+
+////////////////////////////////
+func (kind *StateKindType[T]) copyMap(stateMapFrom DataStateMapType, stateMapTo *DataStateMapType) {
+    valueMapTo := make(map[string]*T)
+    for key, value := range *kind.mapOf(&stateMapFrom) {
+        if value == nil {
+            valueMapTo[key] = nil
+            continue
+        }
+        stData := *value
+        valueMapTo[key] = &stData
+    }
+    *kind.mapOf(stateMapTo) = valueMapTo
+}
+
+////////////////////////////////
+func (kind *StateKindType[T]) markCreated(stateMap DataStateMapType, stateMapBefore *DataStateMapType) {
+    valueMapBefore := *kind.mapOf(stateMapBefore)
+    for key := range *kind.mapOf(&stateMap) {
+        if _, exists := valueMapBefore[key]; !exists {
+            valueMapBefore[key] = nil
+        }
+    }
+}
+
+////////////////////////////////
+func (kind *StateKindType[T]) saveRocks(stateMap DataStateMapType, txRocks *gorocksdb.Transaction) (error) {
+    var err error
+    var valueJson []byte
+    for key, value := range *kind.mapOf(&stateMap) {
+        key = kind.keyPrefix + key
+        if value == nil {
+            err = txRocks.Delete([]byte(key))
+        } else {
+            valueJson, _ = json.Marshal(value)
+            err = txRocks.Put([]byte(key), valueJson)
+        }
+        if err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+////////////////////////////////
+func (kind *StateKindType[T]) saveCassa(stateMap DataStateMapType) (error) {
+    valueMap := *kind.mapOf(&stateMap)
+    keyList := make([]string, 0, len(valueMap))
+    for key := range valueMap {
+        keyList = append(keyList, key)
+    }
+    _, err := startExecuteBatchCassa(len(keyList), func(batch *gocql.Batch, i int) (error) {
+        kind.batchCassa(batch, keyList[i], valueMap[keyList[i]])
+        return nil
+    })
+    return err
+}
+
+////////////////////////////////
+func (kind *StateKindType[T]) getRocksAll(stateMap *DataStateMapType) (error) {
+    valueMap := *kind.mapOf(stateMap)
+    return doIterateRocks(kind.keyPrefix, false, func(key []byte, value []byte) (bool, error) {
+        decoded := new(T)
+        err := json.Unmarshal(value, decoded)
+        if err != nil {
+            return false, err
+        }
+        valueMap[strings.TrimPrefix(string(key), kind.keyPrefix)] = decoded
+        return true, nil
+    })
+}
+
+////////////////////////////////
+func (kind *StateKindType[T]) getCassaAll(stateMap *DataStateMapType) (error) {
+    valueMap := *kind.mapOf(stateMap)
+    row := sRuntime.sessionCassa.Query(kind.cqlnGetAll).PageSize(nPageSizeRepairCassa).Iter().Scanner()
+    for row.Next() {
+        key, value, err := kind.scanCassa(row)
+        if err != nil {
+            return err
+        }
+        if key == "" {
+            continue
+        }
+        valueMap[key] = value
+    }
+    return row.Err()
+}
+
+////////////////////////////////
+func (kind *StateKindType[T]) diffMap(stateMapA DataStateMapType, stateMapB DataStateMapType, diffA *DataStateMapType, diffB *DataStateMapType) {
+    valueMapA := *kind.mapOf(&stateMapA)
+    valueMapB := *kind.mapOf(&stateMapB)
+    valueDiffA := *kind.mapOf(diffA)
+    valueDiffB := *kind.mapOf(diffB)
+    for key, stA := range valueMapA {
+        stB := valueMapB[key]
+        if (stB == nil || *stA != *stB) {
+            valueDiffA[key] = stA
+            valueDiffB[key] = stB
+        }
+    }
+    for key, stB := range valueMapB {
+        if valueMapA[key] == nil {
+            valueDiffA[key] = nil
+            valueDiffB[key] = stB
+        }
+    }
+}
@@ -0,0 +1,186 @@
+
+////////////////////////////////
+package storage
+
+import (
+    "reflect"
+    "testing"
+    "kasplex-executor/config"
+)
+
+////////////////////////////////
+// The lines of the state kinds are the same as the lines made by hand before the registry, the checkpoints depend on them.
+func TestStateKindMakeStLine(t *testing.T) {
+    stToken := &StateTokenType{Tick: "ABCD", Max: "100", Lim: "1", Pre: "0", Dec: 8, From: "a", To: "b", Minted: "5", OpAdd: 7, OpMod: 9}
+    stTokenBurned := &StateTokenType{Tick: "ABCD", Minted: "5", Burned: "2", OpMod: 9}
+    stTokenIssue := &StateTokenType{Tick: "ABCD", Max: "100", Lim: "1", Pre: "0", Dec: 8, From: "a", To: "b", Minted: "5", Burned: "3", OpAdd: 7, OpMod: 9, Mod: "issue", Owner: "o"}
+    stTokenIssueNoBurn := &StateTokenType{Tick: "ABCD", Minted: "5", OpMod: 9, Mod: "issue", Owner: "o"}
+    testList := []struct{
+        name string
+        got string
+        want string
+    }{
+        {"token", StateKindToken.MakeStLine("ABCD", stToken), "sttoken_ABCD,5,9"},
+        {"token burned", StateKindToken.MakeStLine("ABCD", stTokenBurned), "sttoken_ABCD,5,9,2"},
+        {"token issue", StateKindToken.MakeStLine("ABCD", stTokenIssue), "sttoken_ABCD,5,9,3,issue,o"},
+        {"token issue never burned", StateKindToken.MakeStLine("ABCD", stTokenIssueNoBurn), "sttoken_ABCD,5,9,,issue,o"},
+        {"token nil", StateKindToken.MakeStLine("ABCD", nil), "sttoken_ABCD"},
+        {"token deploy", MakeStLineTokenDeploy("ABCD", stToken), "sttoken_ABCD,100,1,0,8,a,b,5,7"},
+        {"token deploy issue", MakeStLineTokenDeploy("ABCD", stTokenIssue), "sttoken_ABCD,100,1,0,8,a,b,5,7,issue,o"},
+        {"token deploy nil", MakeStLineTokenDeploy("ABCD", nil), "sttoken_ABCD"},
+        {"balance", StateKindBalance.MakeStLine("kaspa:q1_ABCD", &StateBalanceType{Address: "kaspa:q1", Tick: "ABCD", Dec: 8, Balance: "10", Locked: "2", OpMod: 9}), "stbalance_kaspa:q1_ABCD,8,10,2,9"},
+        {"balance zero", StateKindBalance.MakeStLine("kaspa:q1_ABCD", &StateBalanceType{Balance: "0", Locked: "0"}), "stbalance_kaspa:q1_ABCD,0,0,0,0"},
+        {"balance nil", StateKindBalance.MakeStLine("kaspa:q1_ABCD", nil), "stbalance_kaspa:q1_ABCD"},
+        {"market", StateKindMarket.MakeStLine("ABCD_kaspa:q1_tx1", &StateMarketType{Tick: "ABCD", TAddr: "kaspa:q1", UTxId: "tx1", UAddr: "kaspa:q2", UAmt: "300", UScript: "s", TAmt: "10", OpAdd: 9}), "stmarket_ABCD_kaspa:q1_tx1,kaspa:q2,300,10,9"},
+        {"market nil", StateKindMarket.MakeStLine("ABCD_kaspa:q1_tx1", nil), "stmarket_ABCD_kaspa:q1_tx1"},
+        {"blacklist", StateKindBlacklist.MakeStLine("ABCD_kaspa:q1", &StateBlacklistType{Tick: "ABCD", Address: "kaspa:q1", OpAdd: 9}), "stblacklist_ABCD_kaspa:q1,9"},
+        {"blacklist nil", StateKindBlacklist.MakeStLine("ABCD_kaspa:q1", nil), "stblacklist_ABCD_kaspa:q1"},
+        {"collection", StateKindCollection.MakeStLine("NFTS", &StateCollectionType{Tick: "NFTS", Max: "100", Buri: "ipfs://x", From: "kaspa:q1", Minted: "3", OpAdd: 7, OpMod: 9}), "stcollection_NFTS,100,ipfs://x,kaspa:q1,3,9"},
+        {"collection nil", StateKindCollection.MakeStLine("NFTS", nil), "stcollection_NFTS"},
+        {"nft", StateKindNft.MakeStLine("NFTS_3", &StateNftType{Tick: "NFTS", TokenId: "3", Owner: "kaspa:q1", OpMod: 9}), "stnft_NFTS_3,kaspa:q1,9"},
+        {"nft nil", StateKindNft.MakeStLine("NFTS_3", nil), "stnft_NFTS_3"},
+    }
+    for _, test := range testList {
+        if test.got != test.want {
+            t.Errorf("%s: line = %q, want %q", test.name, test.got, test.want)
+        }
+    }
+}
+
+////////////////////////////////
+func TestStateKindAppendStLine(t *testing.T) {
+    stLine := []string{}
+    stLine = StateKindToken.AppendStLine(stLine, "ABCD", nil, false)
+    stLine = StateKindBalance.AppendStLine(stLine, "kaspa:q1_ABCD", &StateBalanceType{Balance: "1", Locked: "0", OpMod: 1}, false)
+    stLine = StateKindToken.AppendStLine(stLine, "ABCD", &StateTokenType{Minted: "5", OpMod: 2}, false)
+    stLine = StateKindBalance.AppendStLine(stLine, "kaspa:q1_ABCD", &StateBalanceType{Balance: "2", Locked: "0", OpMod: 2}, true)
+    want := []string{"sttoken_ABCD", "stbalance_kaspa:q1_ABCD,0,2,0,2"}
+    if !reflect.DeepEqual(stLine, want) {
+        t.Fatalf("lines = %v, want %v", stLine, want)
+    }
+}
+
+////////////////////////////////
+// Fill the state map with one key of each kind, the keys created by the second batch are not in the first.
+func makeStateMapRoundTrip(isAfter bool) (DataStateMapType) {
+    stateMap := NewDataStateMap()
+    if !isAfter {
+        stateMap.StateTokenMap["ABCD"] = &StateTokenType{Tick: "ABCD", Max: "100", Lim: "1", Dec: 8, Minted: "5", OpAdd: 7, OpMod: 7}
+        stateMap.StateBalanceMap["kaspa:q1_ABCD"] = &StateBalanceType{Address: "kaspa:q1", Tick: "ABCD", Dec: 8, Balance: "5", Locked: "0", OpMod: 7}
+        stateMap.StateMarketMap["ABCD_kaspa:q1_tx1"] = &StateMarketType{Tick: "ABCD", TAddr: "kaspa:q1", UTxId: "tx1", UAddr: "kaspa:q2", UAmt: "300", TAmt: "1", OpAdd: 7}
+        stateMap.StateBlacklistMap["ABCD_kaspa:q3"] = &StateBlacklistType{Tick: "ABCD", Address: "kaspa:q3", OpAdd: 7}
+        stateMap.StateCollectionMap["NFTS"] = &StateCollectionType{Tick: "NFTS", Max: "100", Buri: "ipfs://x", From: "kaspa:q1", Minted: "1", OpAdd: 7, OpMod: 7}
+        stateMap.StateNftMap["NFTS_1"] = &StateNftType{Tick: "NFTS", TokenId: "1", Owner: "kaspa:q1", OpMod: 7}
+        return stateMap
+    }
+    stateMap.StateTokenMap["ABCD"] = &StateTokenType{Tick: "ABCD", Max: "100", Lim: "1", Dec: 8, Minted: "6", OpAdd: 7, OpMod: 9}
+    stateMap.StateBalanceMap["kaspa:q1_ABCD"] = &StateBalanceType{Address: "kaspa:q1", Tick: "ABCD", Dec: 8, Balance: "6", Locked: "0", OpMod: 9}
+    stateMap.StateBalanceMap["kaspa:q2_ABCD"] = &StateBalanceType{Address: "kaspa:q2", Tick: "ABCD", Dec: 8, Balance: "1", Locked: "0", OpMod: 9}
+    stateMap.StateMarketMap["ABCD_kaspa:q1_tx1"] = nil
+    stateMap.StateBlacklistMap["ABCD_kaspa:q3"] = nil
+    stateMap.StateBlacklistMap["ABCD_kaspa:q4"] = &StateBlacklistType{Tick: "ABCD", Address: "kaspa:q4", OpAdd: 9}
+    stateMap.StateCollectionMap["NFTS"] = &StateCollectionType{Tick: "NFTS", Max: "100", Buri: "ipfs://x", From: "kaspa:q1", Minted: "2", OpAdd: 7, OpMod: 9}
+    stateMap.StateNftMap["NFTS_1"] = &StateNftType{Tick: "NFTS", TokenId: "1", Owner: "kaspa:q2", OpMod: 9}
+    stateMap.StateNftMap["NFTS_2"] = &StateNftType{Tick: "NFTS", TokenId: "2", Owner: "kaspa:q1", OpMod: 9}
+    return stateMap
+}
+
+////////////////////////////////
+// Load the keys of the state map from the local db, in a new map.
+func getStateMapKeys(t *testing.T, stateMapKeys DataStateMapType) (DataStateMapType) {
+    stateMap := NewDataStateMap()
+    for key := range stateMapKeys.StateTokenMap {
+        stateMap.StateTokenMap[key] = nil
+    }
+    for key := range stateMapKeys.StateBalanceMap {
+        stateMap.StateBalanceMap[key] = nil
+    }
+    for key := range stateMapKeys.StateMarketMap {
+        stateMap.StateMarketMap[key] = nil
+    }
+    for key := range stateMapKeys.StateBlacklistMap {
+        stateMap.StateBlacklistMap[key] = nil
+    }
+    for key := range stateMapKeys.StateCollectionMap {
+        stateMap.StateCollectionMap[key] = nil
+    }
+    for key := range stateMapKeys.StateNftMap {
+        stateMap.StateNftMap[key] = nil
+    }
+    _, err := GetStateMap(stateMap)
+    if err != nil {
+        t.Fatal(err)
+    }
+    return stateMap
+}
+
+////////////////////////////////
+// Compare the state map of each kind registered.
+func checkStateMapEqual(t *testing.T, step string, stateMap DataStateMapType, stateMapWant DataStateMapType) {
+    for _, kind := range GetStateKindList() {
+        valueMap := kind.ValueMap(stateMap)
+        valueMapWant := kind.ValueMap(stateMapWant)
+        if !reflect.DeepEqual(valueMap, valueMapWant) {
+            t.Errorf("%s: %s map = %v, want %v", step, kind.Name(), valueMap, valueMapWant)
+        }
+    }
+}
+
+////////////////////////////////
+// Copy the state before executing, save the state after and roll it back, for all the kinds registered.
+func TestStateKindRoundTrip(t *testing.T) {
+    InitRocks(config.RocksConfig{Path: t.TempDir()})
+    kindList := GetStateKindList()
+    if len(kindList) != 6 {
+        t.Fatalf("kind count = %d, want 6", len(kindList))
+    }
+    stateMapInit := makeStateMapRoundTrip(false)
+    stateMapInitBefore := NewDataStateMap()
+    MarkDataStateMapCreated(stateMapInit, &stateMapInitBefore)
+    for _, kind := range kindList {
+        if kind.Len(stateMapInit) == 0 {
+            t.Fatalf("%s: no state in the round trip", kind.Name())
+        }
+        if kind.Len(stateMapInitBefore) != kind.Len(stateMapInit) {
+            t.Errorf("%s: created count = %d, want %d", kind.Name(), kind.Len(stateMapInitBefore), kind.Len(stateMapInit))
+        }
+    }
+    _, err := SaveOpStateBatch(nil, stateMapInit, DataRollbackType{DaaScoreStart: 100, DaaScoreEnd: 100, StateMapBefore: stateMapInitBefore}, nil)
+    if err != nil {
+        t.Fatal(err)
+    }
+    checkStateMapEqual(t, "init", getStateMapKeys(t, stateMapInit), stateMapInit)
+    
+    // Prepare and copy the state before, the copy must not share the state data with the map executed.
+    stateMapAfter := makeStateMapRoundTrip(true)
+    stateMap := getStateMapKeys(t, stateMapAfter)
+    stateMapBefore := NewDataStateMap()
+    CopyDataStateMap(stateMap, &stateMapBefore)
+    stateMap.StateTokenMap["ABCD"].Minted = "999"
+    if stateMapBefore.StateTokenMap["ABCD"].Minted != "5" {
+        t.Fatalf("token before = %+v, the copy is shallow", stateMapBefore.StateTokenMap["ABCD"])
+    }
+    stateMapAfter.StateNftMap["NFTS_3"] = &StateNftType{Tick: "NFTS", TokenId: "3", Owner: "kaspa:q1", OpMod: 9}
+    MarkDataStateMapCreated(stateMapAfter, &stateMapBefore)
+    stNft, exists := stateMapBefore.StateNftMap["NFTS_3"]
+    if (!exists || stNft != nil) {
+        t.Fatalf("nft created before = %v, %v", stNft, exists)
+    }
+    _, err = SaveOpStateBatch(nil, stateMapAfter, DataRollbackType{DaaScoreStart: 110, DaaScoreEnd: 110, StateMapBefore: stateMapBefore}, nil)
+    if err != nil {
+        t.Fatal(err)
+    }
+    checkStateMapEqual(t, "save", getStateMapKeys(t, stateMapAfter), stateMapAfter)
+    
+    // The rollback restores the state of the init batch and deletes the keys created.
+    recordList, err := GetRollbackRecordLast(1)
+    if (err != nil || len(recordList) != 1 || recordList[0].Rollback.DaaScoreStart != 110) {
+        t.Fatalf("record list = %+v, %v", recordList, err)
+    }
+    _, err = RollbackOpStateBatch(recordList[0].Rollback)
+    if err != nil {
+        t.Fatal(err)
+    }
+    checkStateMapEqual(t, "rollback", getStateMapKeys(t, stateMapAfter), stateMapBefore)
+    checkStateMapEqual(t, "rollback init", getStateMapKeys(t, stateMapInit), stateMapInit)
+}
@@ -32,13 +32,13 @@ func GetTokenBalances(tick string) ([]*models.TokenBalance, error) {
 
 	for iter.Scan(&address, &tickResult, &dec, &balance, &locked) {
 		// Only append non-zero balances
-		bal := parseStringToUint64(balance)
-		lock := parseStringToUint64(locked)
-		if bal > 0 || lock > 0 {
+		bal := ParseAmount(balance)
+		lock := ParseAmount(locked)
+		if bal.Sign() > 0 || lock.Sign() > 0 {
 			balances = append(balances, &models.TokenBalance{
 				Address: address,
-				Balance: bal,
-				Locked:  lock,
+				Balance: bal.String(),
+				Locked:  lock.String(),
 				Dec:     dec,
 			})
 		}
@@ -66,44 +66,18 @@ func GetTokenInfo(tick string) (*models.TokenInfo, error) {
 	return &models.TokenInfo{
 		Tick:   tick,
 		Meta:   meta,
-		Minted: parseStringToUint64(minted),
+		Minted: ParseAmount(minted).String(),
 		OpMod:  int64(opMod),
 		MtsMod: mtsMod,
 	}, nil
 }
 
-func parseStringToUint64(s string) uint64 {
-	// Helper function to parse string to uint64
-	var result uint64
-	json.Unmarshal([]byte(s), &result)
-	return result
-}
-
 func GetTokenHoldersPaginated(tick string, page, pageSize int) ([]models.HolderInfo, int, error) {
-	// First get token info to get max supply
-	tokenInfo, err := GetTokenInfo(tick)
+	maxInt, err := getTokenMax(tick)
 	if err != nil {
-		log.Printf("ERROR: Failed to get token info for %s: %v", tick, err)
-		return nil, 0, err
-	}
-
-	// Parse meta to get max supply
-	var metaData map[string]interface{}
-	if err := json.Unmarshal([]byte(tokenInfo.Meta), &metaData); err != nil {
-		log.Printf("ERROR: Failed to parse meta for tick %s: %v", tick, err)
-		return nil, 0, err
-	}
-
-	maxStr, ok := metaData["max"].(string)
-	if !ok {
-		log.Printf("ERROR: Max value not found or not a string in meta for tick %s", tick)
 		return nil, 0, err
 	}
 
-	// Parse max supply using big.Int
-	maxInt := new(big.Int)
-	maxInt.SetString(maxStr, 10)
-
 	// Use existing index on stbalance table
 	query := sRuntime.sessionCassa.Query(`
 		SELECT address, balance, locked 
@@ -119,14 +93,13 @@ func GetTokenHoldersPaginated(tick string, page, pageSize int) ([]models.HolderI
 
 	// Collect all non-zero balances
 	for iter.Scan(&address, &balance, &locked) {
-		bal := parseStringToUint64(balance)
-		lock := parseStringToUint64(locked)
-		total := bal + lock
-		if total > 0 {
+		bal := ParseAmount(balance)
+		lock := ParseAmount(locked)
+		if bal.Sign() > 0 || lock.Sign() > 0 {
 			holders = append(holders, models.HolderInfo{
 				Address: address,
-				Balance: bal,
-				Locked:  lock,
+				Balance: bal.String(),
+				Locked:  lock.String(),
 			})
 		}
 	}
@@ -135,40 +108,149 @@ func GetTokenHoldersPaginated(tick string, page, pageSize int) ([]models.HolderI
 		return nil, 0, err
 	}
 
-	// Sort by total balance (balance + locked)
-	sort.Slice(holders, func(i, j int) bool {
-		totalI := holders[i].Balance + holders[i].Locked
-		totalJ := holders[j].Balance + holders[j].Locked
-		return totalI > totalJ
-	})
+	return paginateHolders(holders, maxInt, page, pageSize)
+}
+
+// GetTokenHoldersPaginatedAt returns the holders of the tick as of the opScore, reconstructed from the op history.
+func GetTokenHoldersPaginatedAt(tick string, opScore uint64, page, pageSize int) ([]models.HolderInfo, int, error) {
+	maxInt, err := getTokenMax(tick)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	balanceMap, err := GetStateBalanceMapTickAt(tick, opScore)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	holders := make([]models.HolderInfo, 0, len(balanceMap))
+	for _, stBalance := range balanceMap {
+		bal := ParseAmount(stBalance.Balance)
+		lock := ParseAmount(stBalance.Locked)
+		if bal.Sign() > 0 || lock.Sign() > 0 {
+			holders = append(holders, models.HolderInfo{
+				Address: stBalance.Address,
+				Balance: bal.String(),
+				Locked:  lock.String(),
+			})
+		}
+	}
+
+	return paginateHolders(holders, maxInt, page, pageSize)
+}
+
+// GetTokenDec returns the decimals in the token meta.
+func GetTokenDec(tick string) (int, error) {
+	tokenInfo, err := GetTokenInfo(tick)
+	if err != nil {
+		return 0, err
+	}
+	meta := StateTokenMetaType{}
+	if err := json.Unmarshal([]byte(tokenInfo.Meta), &meta); err != nil {
+		return 0, err
+	}
+	return meta.Dec, nil
+}
+
+// MetaBurned returns the burned supply in the parsed token meta, "0" if never burned.
+func MetaBurned(metaData map[string]interface{}) string {
+	if burned, ok := metaData["burned"].(string); ok && burned != "" {
+		return burned
+	}
+	return "0"
+}
+
+// MetaMod returns the mode in the parsed token meta, "mint" if not in the issue mode.
+func MetaMod(metaData map[string]interface{}) string {
+	if mod, ok := metaData["mod"].(string); ok && mod != "" {
+		return mod
+	}
+	return "mint"
+}
+
+// MetaOwner returns the owner in the parsed token meta, empty if not in the issue mode.
+func MetaOwner(metaData map[string]interface{}) string {
+	owner, _ := metaData["owner"].(string)
+	return owner
+}
+
+// getTokenMax returns the max supply in the token meta.
+func getTokenMax(tick string) (*big.Int, error) {
+	// First get token info to get max supply
+	tokenInfo, err := GetTokenInfo(tick)
+	if err != nil {
+		log.Printf("ERROR: Failed to get token info for %s: %v", tick, err)
+		return nil, err
+	}
+
+	// Parse meta to get max supply
+	var metaData map[string]interface{}
+	if err := json.Unmarshal([]byte(tokenInfo.Meta), &metaData); err != nil {
+		log.Printf("ERROR: Failed to parse meta for tick %s: %v", tick, err)
+		return nil, err
+	}
+
+	maxStr, ok := metaData["max"].(string)
+	if !ok {
+		log.Printf("ERROR: Max value not found or not a string in meta for tick %s", tick)
+		return nil, fmt.Errorf("max not found for tick %s", tick)
+	}
+
+	// Parse max supply using big.Int
+	maxInt := new(big.Int)
+	maxInt.SetString(maxStr, 10)
+	return maxInt, nil
+}
+
+// paginateHolders sorts the holders by total balance, then fills the rank and share of the page.
+func paginateHolders(holders []models.HolderInfo, maxInt *big.Int, page, pageSize int) ([]models.HolderInfo, int, error) {
+	// Sort by total balance (balance + locked), then by address for the stable order
+	totalList := make([]*big.Int, len(holders))
+	for i := range holders {
+		totalList[i] = new(big.Int).Add(ParseAmount(holders[i].Balance), ParseAmount(holders[i].Locked))
+	}
+	sort.Sort(holdersByTotal{holders: holders, totalList: totalList})
 
 	// Calculate pagination
 	total := len(holders)
 	start := (page - 1) * pageSize
+	if start > total {
+		start = total
+	}
 	end := start + pageSize
 	if end > total {
 		end = total
 	}
 
-	// Calculate ranks and shares for the page using big.Int/big.Float for accuracy
+	// Calculate ranks and exact shares for the page
 	for i := start; i < end; i++ {
-		total := holders[i].Balance + holders[i].Locked
 		holders[i].Rank = i + 1
-
-		// Calculate share using big numbers
-		totalBig := new(big.Int).SetUint64(total)
-		share := new(big.Float).Quo(
-			new(big.Float).SetInt(totalBig),
-			new(big.Float).SetInt(maxInt),
-		)
-		share = share.Mul(share, new(big.Float).SetInt64(100))
-		shareFloat, _ := share.Float64()
-		holders[i].Share = shareFloat
+		holders[i].Share = ShareOf(totalList[i], maxInt)
 	}
 
 	return holders[start:end], total, nil
 }
 
+// holdersByTotal sorts the holders by the total balance descending, then by address.
+type holdersByTotal struct {
+	holders   []models.HolderInfo
+	totalList []*big.Int
+}
+
+func (h holdersByTotal) Len() int { return len(h.holders) }
+
+func (h holdersByTotal) Less(i, j int) bool {
+	if c := h.totalList[i].Cmp(h.totalList[j]); c != 0 {
+		return c > 0
+	}
+	return h.holders[i].Address < h.holders[j].Address
+}
+
+func (h holdersByTotal) Swap(i, j int) {
+	h.holders[i], h.holders[j] = h.holders[j], h.holders[i]
+	h.totalList[i], h.totalList[j] = h.totalList[j], h.totalList[i]
+}
+
 func GetAllTokens() ([]models.TokenListItem, error) {
 	// Query all tokens from the sttoken table
 	query := sRuntime.sessionCassa.Query(`
@@ -203,6 +285,9 @@ func GetAllTokens() ([]models.TokenListItem, error) {
 			State:      "finished",                // Assuming all tokens in DB are finished
 			HashRev:    metaData["txid"].(string), // Using txid as hashRev
 			MtsAdd:     int64(metaData["mtsadd"].(float64)),
+			Burned:     MetaBurned(metaData),
+			Mod:        MetaMod(metaData),
+			Owner:      MetaOwner(metaData),
 		}
 		tokens = append(tokens, token)
 	}
@@ -277,6 +362,9 @@ func GetOperationByHash(hash string) (*models.Operation, error) {
 	if checkpoint, ok := stateData["checkpoint"].(string); ok {
 		operation.Checkpoint = checkpoint
 	}
+	if opEvent, ok := stateData["opevent"].(string); ok {
+		operation.OpEvent = opEvent
+	}
 
 	// Safely extract required fields from script
 	if p, ok := scriptData["p"].(string); ok {
@@ -445,6 +533,9 @@ func GetAllOperationsPaginated(lastScore *uint64, pageSize int) ([]models.Operat
 		if opError, ok := stateData["operror"].(string); ok {
 			op.OpError = opError
 		}
+		if opEvent, ok := stateData["opevent"].(string); ok {
+			op.OpEvent = opEvent
+		}
 
 		// Only include opAccept if there are additional fields not already extracted
 		if opdataState != "" {
@@ -459,6 +550,7 @@ func GetAllOperationsPaginated(lastScore *uint64, pageSize int) ([]models.Operat
 			delete(remainingData, "mtsadd")
 			delete(remainingData, "opaccept")
 			delete(remainingData, "operror")
+			delete(remainingData, "opevent")
 			delete(remainingData, "opscore")
 
 			// If there's any data left, keep opAccept with only the remaining fields
@@ -479,3 +571,144 @@ func GetAllOperationsPaginated(lastScore *uint64, pageSize int) ([]models.Operat
 	log.Printf("Successfully fetched %d operations (hasMore: %v)", len(operations), hasMore)
 	return operations, hasMore, nil
 }
+
+// GetTokenOperations returns the operations of the tick from oplist_by_tick, newest first.
+// The page is older than the cursor opScore by default, or newer if forward, the op and accepted are filtered if specified.
+func GetTokenOperations(tick, op string, accepted *bool, cursor uint64, forward bool, pageSize int) ([]models.Operation, bool, error) {
+	bucketMin, bucketMax, err := getTickBucketRange(tick)
+	if err != nil {
+		return nil, false, err
+	}
+	if cursor/OpBucketTickBy > bucketMax {
+		bucketMax = cursor / OpBucketTickBy
+	}
+
+	// Build the query with the optional filters.
+	cqlFilter := ""
+	argsFilter := []interface{}{}
+	if op != "" {
+		cqlFilter += " AND op = ?"
+		argsFilter = append(argsFilter, op)
+	}
+	if accepted != nil {
+		cqlFilter += " AND accepted = ?"
+		argsFilter = append(argsFilter, *accepted)
+	}
+
+	operations := make([]models.Operation, 0, pageSize)
+	hasMore := false
+	_query := func(opBucket uint64) error {
+		cql := "SELECT opscore, txid, op, accepted, state, script FROM oplist_by_tick WHERE tick = ? AND opbucket = ?"
+		args := []interface{}{tick, opBucket}
+		if forward {
+			cql += " AND opscore > ?"
+			args = append(args, cursor)
+		} else if cursor > 0 {
+			cql += " AND opscore < ?"
+			args = append(args, cursor)
+		}
+		cql += cqlFilter
+		args = append(args, argsFilter...)
+		if forward {
+			cql += " ORDER BY opscore ASC"
+		}
+		cql += " LIMIT ?"
+		args = append(args, pageSize+1-len(operations))
+		if cqlFilter != "" {
+			cql += " ALLOW FILTERING"
+		}
+		iter := sRuntime.sessionCassa.Query(cql, args...).PageSize(pageSize + 1).Iter()
+		var opScore uint64
+		var txid, opType, state, script string
+		var acceptedRow bool
+		for iter.Scan(&opScore, &txid, &opType, &acceptedRow, &state, &script) {
+			if len(operations) >= pageSize {
+				hasMore = true
+				break
+			}
+			operations = append(operations, makeOperation(opScore, txid, state, script))
+		}
+		return iter.Close()
+	}
+	if forward {
+		bucketStart := cursor / OpBucketTickBy
+		if bucketStart < bucketMin {
+			bucketStart = bucketMin
+		}
+		for b := bucketStart; b <= bucketMax && !hasMore; b++ {
+			if err := _query(b); err != nil {
+				return nil, false, err
+			}
+		}
+		// Keep the page newest first in both directions.
+		for i, j := 0, len(operations)-1; i < j; i, j = i+1, j-1 {
+			operations[i], operations[j] = operations[j], operations[i]
+		}
+	} else {
+		bucketStart := bucketMax
+		if cursor > 0 {
+			bucketStart = cursor / OpBucketTickBy
+		}
+		for b := int64(bucketStart); b >= int64(bucketMin) && !hasMore; b-- {
+			if err := _query(uint64(b)); err != nil {
+				return nil, false, err
+			}
+		}
+	}
+
+	return operations, hasMore, nil
+}
+
+// getTickBucketRange returns the opscore bucket range of the tick, from the deploy op to the last checkpoint.
+func getTickBucketRange(tick string) (uint64, uint64, error) {
+	bucketMin := uint64(0)
+	if tokenInfo, err := GetTokenInfo(tick); err == nil {
+		meta := StateTokenMetaType{}
+		if err := json.Unmarshal([]byte(tokenInfo.Meta), &meta); err == nil {
+			bucketMin = meta.OpAdd / OpBucketTickBy
+		}
+	} else if err != gocql.ErrNotFound {
+		return 0, 0, err
+	}
+	head, err := GetCheckpointLast()
+	if err != nil {
+		return 0, 0, err
+	}
+	bucketMax := uint64(0)
+	if head != nil {
+		bucketMax = head.OpScore/OpBucketTickBy + 1
+	}
+	return bucketMin, bucketMax, nil
+}
+
+// makeOperation builds the operation from the state and script json stored in the op index.
+func makeOperation(opScore uint64, txid, state, script string) models.Operation {
+	op := models.Operation{
+		HashRev: txid,
+		OpScore: strconv.FormatUint(opScore, 10),
+	}
+	scriptData := DataScriptType{}
+	if err := json.Unmarshal([]byte(script), &scriptData); err != nil {
+		log.Printf("Error parsing script JSON for txid %s: %v", txid, err)
+	}
+	stateData := DataOpStateType{}
+	if err := json.Unmarshal([]byte(state), &stateData); err != nil {
+		log.Printf("Error parsing state JSON for txid %s: %v", txid, err)
+	}
+	op.P = scriptData.P
+	op.Op = scriptData.Op
+	op.Tick = scriptData.Tick
+	op.Amt = scriptData.Amt
+	op.From = scriptData.From
+	op.To = scriptData.To
+	op.FeeRev = strconv.FormatUint(stateData.Fee, 10)
+	op.FeeLeast = strconv.FormatUint(stateData.FeeLeast, 10)
+	op.TxAccept = strconv.Itoa(int(stateData.OpAccept))
+	op.BlockAccept = stateData.BlockAccept
+	op.OpError = stateData.OpError
+	op.OpEvent = stateData.OpEvent
+	op.Checkpoint = stateData.Checkpoint
+	op.MtsAdd = strconv.FormatInt(stateData.MtsAdd, 10)
+	op.MtsMod = op.MtsAdd
+	return op
+}
@@ -0,0 +1,227 @@
+package storage
+
+import (
+	"kasplex-executor/api/models"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Decimal digits of the unit price in KAS.
+const priceDecimals = 18
+
+// Decimals of the KAS amount in sompi.
+const DecKas = 8
+
+// tradeRowType is the row of the trade ledger.
+type tradeRowType struct {
+	opScore uint64
+	txid    string
+	event   string
+	seller  string
+	buyer   string
+	amt     string
+	price   string
+	mtsAdd  int64
+}
+
+// iterateTrades reads the trades of the tick newest first, older than the cursor opScore if not zero.
+// The iteration stops if fRow returns false.
+func iterateTrades(tick string, cursor uint64, fRow func(*tradeRowType) bool) error {
+	bucketMin, bucketMax, err := getTickBucketRange(tick)
+	if err != nil {
+		return err
+	}
+	if cursor > 0 && cursor/OpBucketTickBy < bucketMax {
+		bucketMax = cursor / OpBucketTickBy
+	}
+	for b := int64(bucketMax); b >= int64(bucketMin); b-- {
+		cql := "SELECT opscore, txid, event, seller, buyer, amt, price, mtsadd FROM optrade WHERE tick = ? AND opbucket = ?"
+		args := []interface{}{tick, uint64(b)}
+		if cursor > 0 {
+			cql += " AND opscore < ?"
+			args = append(args, cursor)
+		}
+		iter := sRuntime.sessionCassa.Query(cql, args...).PageSize(2000).Iter()
+		row := tradeRowType{}
+		next := true
+		for iter.Scan(&row.opScore, &row.txid, &row.event, &row.seller, &row.buyer, &row.amt, &row.price, &row.mtsAdd) {
+			if next = fRow(&row); !next {
+				break
+			}
+		}
+		if err := iter.Close(); err != nil {
+			return err
+		}
+		if !next {
+			return nil
+		}
+	}
+	return nil
+}
+
+// getUnitPrice returns the KAS per whole token of the trade, nil if the amount is zero.
+func getUnitPrice(row *tradeRowType, dec int) *big.Rat {
+	amt := ParseAmount(row.amt)
+	if amt.Sign() <= 0 {
+		return nil
+	}
+	price := new(big.Int).Mul(ParseAmount(row.price), new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(dec)), nil))
+	return new(big.Rat).SetFrac(price, new(big.Int).Mul(amt, new(big.Int).Exp(big.NewInt(10), big.NewInt(DecKas), nil)))
+}
+
+// formatPrice renders the unit price with priceDecimals digits, the trailing zeros are trimmed.
+func formatPrice(price *big.Rat) string {
+	if price == nil {
+		return ""
+	}
+	s := price.FloatString(priceDecimals)
+	s = strings.TrimRight(s, "0")
+	return strings.TrimSuffix(s, ".")
+}
+
+// GetTrades returns the trades or the cancels of the tick by the event, newest first, older than the cursor opScore if not zero.
+func GetTrades(tick, event string, cursor uint64, pageSize int) ([]models.Trade, bool, error) {
+	dec, err := GetTokenDec(tick)
+	if err != nil {
+		return nil, false, err
+	}
+	trades := make([]models.Trade, 0, pageSize)
+	hasMore := false
+	err = iterateTrades(tick, cursor, func(row *tradeRowType) bool {
+		if row.event != event {
+			return true
+		}
+		if len(trades) >= pageSize {
+			hasMore = true
+			return false
+		}
+		trades = append(trades, models.Trade{
+			Tick:      tick,
+			Event:     row.event,
+			HashRev:   row.txid,
+			Seller:    row.seller,
+			Buyer:     row.buyer,
+			Amt:       row.amt,
+			Price:     row.price,
+			UnitPrice: formatPrice(getUnitPrice(row, dec)),
+			OpScore:   strconv.FormatUint(row.opScore, 10),
+			MtsAdd:    row.mtsAdd,
+		})
+		return true
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	return trades, hasMore, nil
+}
+
+// GetTradeCandles returns the OHLC candles of the trades of the tick in [mtsFrom, mtsTo), oldest first.
+// The candles are aligned to the interval in milliseconds, the interval without trades is skipped.
+func GetTradeCandles(tick string, interval, mtsFrom, mtsTo int64) ([]models.TradeCandle, error) {
+	dec, err := GetTokenDec(tick)
+	if err != nil {
+		return nil, err
+	}
+	type candleType struct {
+		mtsOpen     int64
+		open, high  *big.Rat
+		low, close  *big.Rat
+		volume, amt *big.Int
+		trades      int
+	}
+	candleMap := map[int64]*candleType{}
+	err = iterateTrades(tick, 0, func(row *tradeRowType) bool {
+		if row.mtsAdd < mtsFrom {
+			return false
+		}
+		if row.mtsAdd >= mtsTo || row.event != OpEventTrade {
+			return true
+		}
+		price := getUnitPrice(row, dec)
+		if price == nil {
+			return true
+		}
+		// The trades are newest first, so the candle is built from the close.
+		mtsOpen := row.mtsAdd - row.mtsAdd%interval
+		candle := candleMap[mtsOpen]
+		if candle == nil {
+			candle = &candleType{
+				mtsOpen: mtsOpen,
+				high:    price,
+				low:     price,
+				close:   price,
+				volume:  new(big.Int),
+				amt:     new(big.Int),
+			}
+			candleMap[mtsOpen] = candle
+		}
+		candle.open = price
+		if price.Cmp(candle.high) > 0 {
+			candle.high = price
+		}
+		if price.Cmp(candle.low) < 0 {
+			candle.low = price
+		}
+		candle.volume.Add(candle.volume, ParseAmount(row.price))
+		candle.amt.Add(candle.amt, ParseAmount(row.amt))
+		candle.trades++
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	candleList := make([]*candleType, 0, len(candleMap))
+	for _, candle := range candleMap {
+		candleList = append(candleList, candle)
+	}
+	sort.Slice(candleList, func(i, j int) bool {
+		return candleList[i].mtsOpen < candleList[j].mtsOpen
+	})
+	candles := make([]models.TradeCandle, 0, len(candleList))
+	for _, candle := range candleList {
+		candles = append(candles, models.TradeCandle{
+			MtsOpen: candle.mtsOpen,
+			Open:    formatPrice(candle.open),
+			High:    formatPrice(candle.high),
+			Low:     formatPrice(candle.low),
+			Close:   formatPrice(candle.close),
+			Volume:  candle.volume.String(),
+			Amt:     candle.amt.String(),
+			Trades:  candle.trades,
+		})
+	}
+	return candles, nil
+}
+
+// GetTradeVolume returns the trade volume of the tick in [mtsFrom, mtsTo), the cancels are excluded.
+func GetTradeVolume(tick string, mtsFrom, mtsTo int64) (*models.TradeVolume, error) {
+	volume := new(big.Int)
+	amt := new(big.Int)
+	trades := 0
+	err := iterateTrades(tick, 0, func(row *tradeRowType) bool {
+		if row.mtsAdd < mtsFrom {
+			return false
+		}
+		if row.mtsAdd >= mtsTo || row.event != OpEventTrade {
+			return true
+		}
+		volume.Add(volume, ParseAmount(row.price))
+		amt.Add(amt, ParseAmount(row.amt))
+		trades++
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &models.TradeVolume{
+		Tick:      tick,
+		MtsFrom:   mtsFrom,
+		MtsTo:     mtsTo,
+		Volume:    volume.String(),
+		VolumeKas: FormatAmount(volume.String(), DecKas),
+		Amt:       amt.String(),
+		Trades:    trades,
+	}, nil
+}
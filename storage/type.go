@@ -3,6 +3,7 @@ package storage
 
 import (
 	"kasplex-executor/protowire"
+	"math/big"
 )
 
 // //////////////////////////////
@@ -22,18 +23,21 @@ type DataTransactionType struct {
 
 // //////////////////////////////
 type DataScriptType struct {
-	P     string `json:"p"`
-	Op    string `json:"op"`
-	From  string `json:"from,omitempty"`
-	To    string `json:"to,omitempty"`
-	Tick  string `json:"tick,omitempty"`
-	Max   string `json:"max,omitempty"`
-	Lim   string `json:"lim,omitempty"`
-	Pre   string `json:"pre,omitempty"`
-	Dec   string `json:"dec,omitempty"`
-	Amt   string `json:"amt,omitempty"`
-	Utxo  string `json:"utxo,omitempty"`
-	Price string `json:"price,omitempty"`
+	P       string `json:"p"`
+	Op      string `json:"op"`
+	From    string `json:"from,omitempty"`
+	To      string `json:"to,omitempty"`
+	Tick    string `json:"tick,omitempty"`
+	Max     string `json:"max,omitempty"`
+	Lim     string `json:"lim,omitempty"`
+	Pre     string `json:"pre,omitempty"`
+	Dec     string `json:"dec,omitempty"`
+	Amt     string `json:"amt,omitempty"`
+	Utxo    string `json:"utxo,omitempty"`
+	Price   string `json:"price,omitempty"`
+	Mod     string `json:"mod,omitempty"`
+	Buri    string `json:"buri,omitempty"`
+	TokenId string `json:"tokenid,omitempty"`
 	// ...
 }
 
@@ -46,6 +50,7 @@ type DataOpStateType struct {
 	OpScore     uint64 `json:"opscore,omitempty"`
 	OpAccept    int8   `json:"opaccept,omitempty"`
 	OpError     string `json:"operror,omitempty"`
+	OpEvent     string `json:"opevent,omitempty"`
 	Checkpoint  string `json:"checkpoint,omitempty"`
 }
 
@@ -67,6 +72,7 @@ type DataOperationType struct {
 	OpScore     uint64
 	OpAccept    int8
 	OpError     string
+	OpEvent     string
 	OpScript    []*DataScriptType
 	ScriptSig   string
 	StBefore    []string
@@ -86,6 +92,9 @@ type StateTokenMetaType struct {
 	TxId   string `json:"txid,omitempty"`
 	OpAdd  uint64 `json:"opadd,omitempty"`
 	MtsAdd int64  `json:"mtsadd,omitempty"`
+	Burned string `json:"burned,omitempty"`
+	Mod    string `json:"mod,omitempty"`
+	Owner  string `json:"owner,omitempty"`
 }
 
 // //////////////////////////////
@@ -98,11 +107,14 @@ type StateTokenType struct {
 	From   string `json:"from,omitempty"`
 	To     string `json:"to,omitempty"`
 	Minted string `json:"minted,omitempty"`
+	Burned string `json:"burned,omitempty"`
 	TxId   string `json:"txid,omitempty"`
 	OpAdd  uint64 `json:"opadd,omitempty"`
 	OpMod  uint64 `json:"opmod,omitempty"`
 	MtsAdd int64  `json:"mtsadd,omitempty"`
 	MtsMod int64  `json:"mtsmod,omitempty"`
+	Mod    string `json:"mod,omitempty"`
+	Owner  string `json:"owner,omitempty"`
 }
 
 // //////////////////////////////
@@ -127,14 +139,58 @@ type StateMarketType struct {
 	OpAdd   uint64 `json:"opadd,omitempty"`
 }
 
+// //////////////////////////////
+type StateBlacklistType struct {
+	Tick    string `json:"tick,omitempty"`
+	Address string `json:"address,omitempty"`
+	OpAdd   uint64 `json:"opadd,omitempty"`
+}
+
+// //////////////////////////////
+type StateCollectionMetaType struct {
+	Max    string `json:"max,omitempty"`
+	Buri   string `json:"buri,omitempty"`
+	From   string `json:"from,omitempty"`
+	TxId   string `json:"txid,omitempty"`
+	OpAdd  uint64 `json:"opadd,omitempty"`
+	MtsAdd int64  `json:"mtsadd,omitempty"`
+}
+
+// //////////////////////////////
+// The KRC-721 collection, the minted is the count of the tokens and the last token id.
+type StateCollectionType struct {
+	Tick   string `json:"tick,omitempty"`
+	Max    string `json:"max,omitempty"`
+	Buri   string `json:"buri,omitempty"`
+	From   string `json:"from,omitempty"`
+	Minted string `json:"minted,omitempty"`
+	TxId   string `json:"txid,omitempty"`
+	OpAdd  uint64 `json:"opadd,omitempty"`
+	OpMod  uint64 `json:"opmod,omitempty"`
+	MtsAdd int64  `json:"mtsadd,omitempty"`
+	MtsMod int64  `json:"mtsmod,omitempty"`
+}
+
+// //////////////////////////////
+// The KRC-721 token of the collection and its owner.
+type StateNftType struct {
+	Tick    string `json:"tick,omitempty"`
+	TokenId string `json:"tokenid,omitempty"`
+	Owner   string `json:"owner,omitempty"`
+	OpMod   uint64 `json:"opmod,omitempty"`
+}
+
 ////////////////////////////////
 // type StateXxx ...
 
 // //////////////////////////////
 type DataStateMapType struct {
-	StateTokenMap   map[string]*StateTokenType   `json:"statetokenmap,omitempty"`
-	StateBalanceMap map[string]*StateBalanceType `json:"statebalancemap,omitempty"`
-	StateMarketMap  map[string]*StateMarketType  `json:"statemarketmap,omitempty"`
+	StateTokenMap      map[string]*StateTokenType      `json:"statetokenmap,omitempty"`
+	StateBalanceMap    map[string]*StateBalanceType    `json:"statebalancemap,omitempty"`
+	StateMarketMap     map[string]*StateMarketType     `json:"statemarketmap,omitempty"`
+	StateBlacklistMap  map[string]*StateBlacklistType  `json:"stateblacklistmap,omitempty"`
+	StateCollectionMap map[string]*StateCollectionType `json:"statecollectionmap,omitempty"`
+	StateNftMap        map[string]*StateNftType        `json:"statenftmap,omitempty"`
 	// StateXxx ...
 }
 
@@ -150,6 +206,35 @@ type DataRollbackType struct {
 	TxIdList         []string         `json:"txidlist"`
 }
 
+// //////////////////////////////
+type DataRollbackRecordType struct {
+	Rollback DataRollbackType `json:"rollback"`
+	VspcList []DataVspcType   `json:"vspclist"`
+}
+
+// //////////////////////////////
+type DataWebhookType struct {
+	Id          string   `json:"id"`
+	Url         string   `json:"url"`
+	Secret      string   `json:"secret"`
+	TickList    []string `json:"ticklist,omitempty"`
+	AddressList []string `json:"addresslist,omitempty"`
+	OpList      []string `json:"oplist,omitempty"`
+	Receive     bool     `json:"receive,omitempty"`
+	OpScoreAdd  uint64   `json:"opscoreadd"`
+	MtsAdd      int64    `json:"mtsadd"`
+}
+
+// //////////////////////////////
+type DataWebhookDeadType struct {
+	WebhookId  string `json:"webhookid"`
+	DeliveryId string `json:"deliveryid"`
+	Payload    string `json:"payload"`
+	Attempt    int    `json:"attempt"`
+	Error      string `json:"error"`
+	MtsAdd     int64  `json:"mtsadd"`
+}
+
 // //////////////////////////////
 type DataInputType struct {
 	Hash   string
@@ -170,14 +255,14 @@ type DataFeeType struct {
 // //////////////////////////////
 type PortfolioHolding struct {
 	Tick    string
-	Balance uint64
-	Locked  uint64
+	Balance string
+	Locked  string
 	Dec     int
 }
 
 type HolderPortfolio struct {
 	Address    string
 	TokenCount int
-	TotalValue uint64
+	TotalValue *big.Int
 	Holdings   []PortfolioHolding
 }
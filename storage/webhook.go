@@ -0,0 +1,105 @@
+
+////////////////////////////////
+package storage
+
+import (
+    "fmt"
+    "encoding/json"
+)
+
+////////////////////////////////
+const keyPrefixWebhook = "WHK_"  // webhook subscription, by id.
+const keyPrefixWebhookDead = "WHD_"  // dead letter of the webhook delivery, by id and mtsAdd.
+
+////////////////////////////////
+func makeKeyWebhook(id string) ([]byte) {
+    return []byte(keyPrefixWebhook + id)
+}
+
+////////////////////////////////
+func makeKeyWebhookDead(id string, mtsAdd int64, deliveryId string) ([]byte) {
+    return []byte(keyPrefixWebhookDead + id + "_" + fmt.Sprintf("%020d", mtsAdd) + "_" + deliveryId)
+}
+
+////////////////////////////////
+// Save the webhook subscription, in the local db.
+func SaveWebhook(webhook DataWebhookType) (error) {
+    valueJson, _ := json.Marshal(webhook)
+    return sRuntime.rocksTx.Put(sRuntime.wOptRocks, makeKeyWebhook(webhook.Id), valueJson)
+}
+
+////////////////////////////////
+// Delete the webhook subscription and its dead letters, in the local db.
+func DeleteWebhook(id string) (error) {
+    err := sRuntime.rocksTx.Delete(sRuntime.wOptRocks, makeKeyWebhook(id))
+    if err != nil {
+        return err
+    }
+    _, err = DeleteWebhookDeadList(id)
+    return err
+}
+
+////////////////////////////////
+// Get all the webhook subscriptions, in the local db.
+func GetWebhookList() ([]DataWebhookType, error) {
+    webhookList := []DataWebhookType{}
+    err := doIterateRocks(keyPrefixWebhook, false, func(key []byte, value []byte) (bool, error) {
+        webhook := DataWebhookType{}
+        err := json.Unmarshal(value, &webhook)
+        if err != nil {
+            return false, err
+        }
+        webhookList = append(webhookList, webhook)
+        return true, nil
+    })
+    if err != nil {
+        return nil, err
+    }
+    return webhookList, nil
+}
+
+////////////////////////////////
+// Save the dead letter of the webhook delivery failed, in the local db.
+func SaveWebhookDead(dead DataWebhookDeadType) (error) {
+    valueJson, _ := json.Marshal(dead)
+    return sRuntime.rocksTx.Put(sRuntime.wOptRocks, makeKeyWebhookDead(dead.WebhookId, dead.MtsAdd, dead.DeliveryId), valueJson)
+}
+
+////////////////////////////////
+// Get the last dead letters of the webhook in descending order, in the local db.
+func GetWebhookDeadList(id string, lenDead int) ([]DataWebhookDeadType, error) {
+    deadList := []DataWebhookDeadType{}
+    err := doIterateRocks(keyPrefixWebhookDead + id + "_", true, func(key []byte, value []byte) (bool, error) {
+        dead := DataWebhookDeadType{}
+        err := json.Unmarshal(value, &dead)
+        if err != nil {
+            return false, err
+        }
+        deadList = append(deadList, dead)
+        return len(deadList) < lenDead, nil
+    })
+    if err != nil {
+        return nil, err
+    }
+    return deadList, nil
+}
+
+////////////////////////////////
+// Delete all the dead letters of the webhook, in the local db.
+func DeleteWebhookDeadList(id string) (int, error) {
+    keyList := [][]byte{}
+    err := doIterateRocks(keyPrefixWebhookDead + id + "_", false, func(key []byte, value []byte) (bool, error) {
+        keyList = append(keyList, key)
+        return true, nil
+    })
+    if err != nil {
+        return 0, err
+    }
+    for _, key := range keyList {
+        err = sRuntime.rocksTx.Delete(sRuntime.wOptRocks, key)
+        if err != nil {
+            return 0, err
+        }
+    }
+    return len(keyList), nil
+}
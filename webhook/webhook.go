@@ -0,0 +1,456 @@
+
+////////////////////////////////
+package webhook
+
+import (
+    "io"
+    "log"
+    "sort"
+    "sync"
+    "time"
+    "bytes"
+    "errors"
+    "context"
+    "strconv"
+    "strings"
+    "net/url"
+    "net/http"
+    "log/slog"
+    "crypto/hmac"
+    "crypto/rand"
+    "crypto/sha256"
+    "crypto/subtle"
+    "encoding/hex"
+    "encoding/json"
+    "kasplex-executor/config"
+    "kasplex-executor/events"
+    "kasplex-executor/storage"
+)
+
+////////////////////////////////
+const lenWebhookMax = 100
+const lenDeliveryQueueMax = 10000
+const lenResponseMax = 65536
+const nAttemptMaxDefault = 8
+const nBackoffMaxDefault = 300
+const nTimeoutDefault = 10
+const mtsDelayResubscribe = 1000
+
+////////////////////////////////
+const HeaderWebhook = "X-Kasplex-Webhook"
+const HeaderDelivery = "X-Kasplex-Delivery"
+const HeaderTimestamp = "X-Kasplex-Timestamp"
+const HeaderSignature = "X-Kasplex-Signature"
+
+////////////////////////////////
+var ErrDisabled = errors.New("webhook disabled")
+var ErrNotFound = errors.New("webhook not found")
+var ErrInvalidUrl = errors.New("invalid url")
+var ErrTooMany = errors.New("too many webhooks")
+
+////////////////////////////////
+// The body posted to the webhook, the id is unique for each event and same in the retries.
+type payloadType struct {
+    Id string `json:"id"`
+    WebhookId string `json:"webhookId"`
+    Event json.RawMessage `json:"event"`
+}
+
+////////////////////////////////
+type deliveryType struct {
+    id string
+    payload []byte
+}
+
+////////////////////////////////
+type hookType struct {
+    data storage.DataWebhookType
+    filter events.FilterType
+    chDelivery chan *deliveryType
+    chStop chan struct{}
+}
+
+////////////////////////////////
+type runtimeType struct {
+    ctx context.Context
+    cfg config.WebhookConfig
+    client *http.Client
+    mutex sync.RWMutex
+    hookMap map[string]*hookType
+    enabled bool
+}
+var wRuntime runtimeType
+
+////////////////////////////////
+// Load the webhooks in the local db and deliver the events published after each batch committed.
+func Init(ctx context.Context, cfg config.WebhookConfig) {
+    slog.Info("webhook.Init start.")
+    if cfg.AttemptMax <= 0 {
+        cfg.AttemptMax = nAttemptMaxDefault
+    }
+    if cfg.BackoffMax <= 0 {
+        cfg.BackoffMax = nBackoffMaxDefault
+    }
+    if cfg.Timeout <= 0 {
+        cfg.Timeout = nTimeoutDefault
+    }
+    wRuntime.ctx = ctx
+    wRuntime.cfg = cfg
+    wRuntime.client = &http.Client{
+        Timeout: time.Duration(cfg.Timeout) * time.Second,
+        CheckRedirect: func(req *http.Request, via []*http.Request) (error) {
+            return http.ErrUseLastResponse
+        },
+    }
+    wRuntime.hookMap = map[string]*hookType{}
+    webhookList, err := storage.GetWebhookList()
+    if err != nil {
+        log.Fatalln("webhook.Init fatal:", err.Error())
+    }
+    for _, data := range webhookList {
+        startHook(data)
+    }
+    // The events are never dropped by the hub, the dead letter is saved if the queue of the webhook is full.
+    sub, err := events.SubscribeBlocking(ctx, events.FilterType{})
+    if err != nil {
+        log.Fatalln("webhook.Init fatal:", err.Error())
+    }
+    wRuntime.enabled = true
+    go run(sub)
+    slog.Info("webhook ready.", "webhooks", len(webhookList))
+}
+
+////////////////////////////////
+// Dispatch the events to the webhooks matched, subscribe again if closed by the hub.
+func run(sub *events.Subscriber) {
+    for {
+        select {
+            case <-wRuntime.ctx.Done():
+                sub.Unsubscribe()
+                slog.Info("webhook stopped.")
+                return
+            case event, ok := <-sub.C():
+                if ok {
+                    dispatch(event)
+                    continue
+                }
+                slog.Warn("webhook.run events closed, subscribe again.")
+                for {
+                    var err error
+                    sub, err = events.SubscribeBlocking(wRuntime.ctx, events.FilterType{})
+                    if err == nil {
+                        break
+                    }
+                    select {
+                        case <-wRuntime.ctx.Done():
+                            return
+                        case <-time.After(mtsDelayResubscribe*time.Millisecond):
+                    }
+                }
+        }
+    }
+}
+
+////////////////////////////////
+// Queue the event to each webhook matched, the dead letter is saved if the queue is full.
+func dispatch(event *events.DataEventType) {
+    wRuntime.mutex.RLock()
+    defer wRuntime.mutex.RUnlock()
+    for _, hook := range wRuntime.hookMap {
+        if (event.OpScore <= hook.data.OpScoreAdd || !hook.match(event)) {
+            continue
+        }
+        delivery := &deliveryType{
+            id: event.Type + "_" + strconv.FormatUint(event.OpScore, 10) + "_" + event.TxId,
+        }
+        delivery.payload, _ = json.Marshal(payloadType{
+            Id: delivery.id,
+            WebhookId: hook.data.Id,
+            Event: json.RawMessage(event.Json),
+        })
+        select {
+            case hook.chDelivery <- delivery:
+            default:
+                saveDead(hook, delivery, 0, errors.New("delivery queue full"))
+        }
+    }
+}
+
+////////////////////////////////
+// Start the delivery of the webhook, the caller must hold the lock after Init.
+func startHook(data storage.DataWebhookType) {
+    hook := &hookType{
+        data: data,
+        filter: events.FilterType{
+            TickList: data.TickList,
+            AddressList: data.AddressList,
+            OpList: data.OpList,
+        },
+        chDelivery: make(chan *deliveryType, lenDeliveryQueueMax),
+        chStop: make(chan struct{}),
+    }
+    wRuntime.hookMap[data.Id] = hook
+    go hook.run()
+}
+
+////////////////////////////////
+// Check if the event matches the webhook, the address must receive the tick if "receive" is set.
+func (hook *hookType) match(event *events.DataEventType) (bool) {
+    if !hook.filter.Match(event) {
+        return false
+    }
+    if (!hook.data.Receive || len(hook.data.AddressList) <= 0) {
+        return true
+    }
+    // The retraction has no balance, the receiver is the "to" of the op retracted.
+    if event.Type == events.EventTypeRetract {
+        return matchList(hook.data.AddressList, event.Op.To)
+    }
+    for _, balance := range event.BalanceList {
+        if (!matchList(hook.data.AddressList, balance.Address) || balance.Delta == "0" || strings.HasPrefix(balance.Delta, "-")) {
+            continue
+        }
+        if (len(hook.data.TickList) > 0 && !matchList(hook.data.TickList, strings.ToUpper(balance.Tick))) {
+            continue
+        }
+        return true
+    }
+    return false
+}
+
+////////////////////////////////
+func matchList(list []string, value string) (bool) {
+    if value == "" {
+        return false
+    }
+    for _, item := range list {
+        if item == value {
+            return true
+        }
+    }
+    return false
+}
+
+////////////////////////////////
+// Deliver the queued events in order until the webhook removed.
+func (hook *hookType) run() {
+    for {
+        select {
+            case <-wRuntime.ctx.Done():
+                return
+            case <-hook.chStop:
+                return
+            case delivery := <-hook.chDelivery:
+                hook.deliver(delivery)
+        }
+    }
+}
+
+////////////////////////////////
+// Post the delivery with the exponential backoff, the dead letter is saved if all attempts failed.
+func (hook *hookType) deliver(delivery *deliveryType) {
+    backoff := time.Second
+    backoffMax := time.Duration(wRuntime.cfg.BackoffMax) * time.Second
+    var err error
+    for attempt := 1; attempt <= wRuntime.cfg.AttemptMax; attempt ++ {
+        err = post(&hook.data, delivery)
+        if err == nil {
+            return
+        }
+        slog.Debug("webhook.deliver failed.", "webhook", hook.data.Id, "delivery", delivery.id, "attempt", attempt, "error", err.Error())
+        if attempt >= wRuntime.cfg.AttemptMax {
+            break
+        }
+        select {
+            case <-wRuntime.ctx.Done():
+                return
+            case <-hook.chStop:
+                return
+            case <-time.After(backoff):
+        }
+        backoff *= 2
+        if backoff > backoffMax {
+            backoff = backoffMax
+        }
+    }
+    slog.Warn("webhook.deliver failed, dead letter saved.", "webhook", hook.data.Id, "delivery", delivery.id, "error", err.Error())
+    saveDead(hook, delivery, wRuntime.cfg.AttemptMax, err)
+}
+
+////////////////////////////////
+// Post the payload signed with the secret of the webhook, any 2xx status is delivered.
+func post(data *storage.DataWebhookType, delivery *deliveryType) (error) {
+    timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+    req, err := http.NewRequestWithContext(wRuntime.ctx, http.MethodPost, data.Url, bytes.NewReader(delivery.payload))
+    if err != nil {
+        return err
+    }
+    req.Header.Set("Content-Type", "application/json")
+    req.Header.Set(HeaderWebhook, data.Id)
+    req.Header.Set(HeaderDelivery, delivery.id)
+    req.Header.Set(HeaderTimestamp, timestamp)
+    req.Header.Set(HeaderSignature, Sign(data.Secret, timestamp, delivery.payload))
+    resp, err := wRuntime.client.Do(req)
+    if err != nil {
+        return err
+    }
+    io.Copy(io.Discard, io.LimitReader(resp.Body, lenResponseMax))
+    resp.Body.Close()
+    if (resp.StatusCode < 200 || resp.StatusCode >= 300) {
+        return errors.New("response status " + strconv.Itoa(resp.StatusCode))
+    }
+    return nil
+}
+
+////////////////////////////////
+// Sign the payload as "sha256=" + hex(HMAC-SHA256(secret, timestamp + "." + payload)).
+func Sign(secret string, timestamp string, payload []byte) (string) {
+    mac := hmac.New(sha256.New, []byte(secret))
+    mac.Write([]byte(timestamp + "."))
+    mac.Write(payload)
+    return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+////////////////////////////////
+func saveDead(hook *hookType, delivery *deliveryType, attempt int, errDeliver error) {
+    err := storage.SaveWebhookDead(storage.DataWebhookDeadType{
+        WebhookId: hook.data.Id,
+        DeliveryId: delivery.id,
+        Payload: string(delivery.payload),
+        Attempt: attempt,
+        Error: errDeliver.Error(),
+        MtsAdd: time.Now().UnixMilli(),
+    })
+    if err != nil {
+        slog.Warn("webhook.saveDead failed.", "webhook", hook.data.Id, "delivery", delivery.id, "error", err.Error())
+    }
+}
+
+////////////////////////////////
+func makeRandomHex(lenBytes int) (string, error) {
+    b := make([]byte, lenBytes)
+    _, err := rand.Read(b)
+    if err != nil {
+        return "", err
+    }
+    return hex.EncodeToString(b), nil
+}
+
+////////////////////////////////
+// Check if the webhook api is available.
+func Enabled() (bool) {
+    return wRuntime.enabled
+}
+
+////////////////////////////////
+// Check the admin token of the webhook api, always false if the token is not configured.
+func Authorize(token string) (bool) {
+    if (!wRuntime.enabled || wRuntime.cfg.Token == "") {
+        return false
+    }
+    return subtle.ConstantTimeCompare([]byte(token), []byte(wRuntime.cfg.Token)) == 1
+}
+
+////////////////////////////////
+// Register the webhook with the url and filter, the id and secret are generated.
+// Only the ops after the last checkpoint are delivered.
+func Register(data storage.DataWebhookType) (*storage.DataWebhookType, error) {
+    if !wRuntime.enabled {
+        return nil, ErrDisabled
+    }
+    u, err := url.Parse(data.Url)
+    if (err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "") {
+        return nil, ErrInvalidUrl
+    }
+    head, err := storage.GetCheckpointLast()
+    if err != nil {
+        return nil, err
+    }
+    if head != nil {
+        data.OpScoreAdd = head.OpScore
+    }
+    data.Id, err = makeRandomHex(16)
+    if err != nil {
+        return nil, err
+    }
+    data.Secret, err = makeRandomHex(32)
+    if err != nil {
+        return nil, err
+    }
+    data.MtsAdd = time.Now().UnixMilli()
+    wRuntime.mutex.Lock()
+    defer wRuntime.mutex.Unlock()
+    if len(wRuntime.hookMap) >= lenWebhookMax {
+        return nil, ErrTooMany
+    }
+    err = storage.SaveWebhook(data)
+    if err != nil {
+        return nil, err
+    }
+    startHook(data)
+    return &data, nil
+}
+
+////////////////////////////////
+// Remove the webhook and its dead letters, the pending deliveries are dropped.
+func Remove(id string) (error) {
+    if !wRuntime.enabled {
+        return ErrDisabled
+    }
+    wRuntime.mutex.Lock()
+    defer wRuntime.mutex.Unlock()
+    hook := wRuntime.hookMap[id]
+    if hook == nil {
+        return ErrNotFound
+    }
+    close(hook.chStop)
+    delete(wRuntime.hookMap, id)
+    return storage.DeleteWebhook(id)
+}
+
+////////////////////////////////
+// Get the webhooks registered, the secret is not included.
+func GetList() ([]storage.DataWebhookType) {
+    wRuntime.mutex.RLock()
+    defer wRuntime.mutex.RUnlock()
+    webhookList := make([]storage.DataWebhookType, 0, len(wRuntime.hookMap))
+    for _, hook := range wRuntime.hookMap {
+        data := hook.data
+        data.Secret = ""
+        webhookList = append(webhookList, data)
+    }
+    sort.Slice(webhookList, func(i int, j int) (bool) {
+        return webhookList[i].MtsAdd < webhookList[j].MtsAdd
+    })
+    return webhookList
+}
+
+////////////////////////////////
+// Get the last dead letters of the webhook in descending order.
+func GetDeadList(id string, lenDead int) ([]storage.DataWebhookDeadType, error) {
+    if !wRuntime.enabled {
+        return nil, ErrDisabled
+    }
+    wRuntime.mutex.RLock()
+    exists := wRuntime.hookMap[id] != nil
+    wRuntime.mutex.RUnlock()
+    if !exists {
+        return nil, ErrNotFound
+    }
+    return storage.GetWebhookDeadList(id, lenDead)
+}
+
+////////////////////////////////
+// Clear the dead letters of the webhook.
+func ClearDeadList(id string) (int, error) {
+    if !wRuntime.enabled {
+        return 0, ErrDisabled
+    }
+    wRuntime.mutex.RLock()
+    exists := wRuntime.hookMap[id] != nil
+    wRuntime.mutex.RUnlock()
+    if !exists {
+        return 0, ErrNotFound
+    }
+    return storage.DeleteWebhookDeadList(id)
+}
@@ -0,0 +1,113 @@
+
+////////////////////////////////
+package webhook
+
+import (
+    "io"
+    "time"
+    "context"
+    "testing"
+    "net/http"
+    "net/http/httptest"
+    "kasplex-executor/events"
+    "kasplex-executor/storage"
+)
+
+////////////////////////////////
+func TestSign(t *testing.T) {
+    tests := []struct {
+        secret string
+        timestamp string
+        payload string
+        signature string
+    }{
+        {"secret", "1700000000", `{"id":"op_1_tx"}`, "sha256=1787bda6512d6f47a6f734668af9937757054c22be27c2edc9c6ee1f8878d762"},
+        {"", "0", "", "sha256=b849d5a581847b281957065739df36df2463d1977ea8d6e1e4e6cf33fadc68c3"},
+    }
+    for _, tt := range tests {
+        signature := Sign(tt.secret, tt.timestamp, []byte(tt.payload))
+        if signature != tt.signature {
+            t.Errorf("Sign(%q, %q, %q) = %s, want %s", tt.secret, tt.timestamp, tt.payload, signature, tt.signature)
+        }
+    }
+    if Sign("secret", "1700000001", []byte(`{"id":"op_1_tx"}`)) == tests[0].signature {
+        t.Error("Sign ignores the timestamp")
+    }
+}
+
+////////////////////////////////
+// The payload is posted with the signature of the timestamp header, verified as the receiver.
+func TestPostSigned(t *testing.T) {
+    var errReceived string
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        body, _ := io.ReadAll(r.Body)
+        if r.Header.Get(HeaderSignature) != Sign("secret", r.Header.Get(HeaderTimestamp), body) {
+            errReceived = "signature mismatch"
+        } else if (r.Header.Get(HeaderWebhook) != "w1" || r.Header.Get(HeaderDelivery) != "op_1_tx") {
+            errReceived = "header mismatch"
+        }
+        if string(body) == "fail" {
+            w.WriteHeader(http.StatusInternalServerError)
+        }
+    }))
+    defer server.Close()
+    wRuntime.ctx = context.Background()
+    wRuntime.client = &http.Client{Timeout: 5*time.Second}
+    data := &storage.DataWebhookType{Id: "w1", Url: server.URL, Secret: "secret"}
+    err := post(data, &deliveryType{id: "op_1_tx", payload: []byte(`{"id":"op_1_tx"}`)})
+    if (err != nil || errReceived != "") {
+        t.Fatalf("post = %v, received %s", err, errReceived)
+    }
+    err = post(data, &deliveryType{id: "op_1_tx", payload: []byte("fail")})
+    if (err == nil || err.Error() != "response status 500") {
+        t.Fatalf("post failed = %v", err)
+    }
+}
+
+////////////////////////////////
+func TestHookMatch(t *testing.T) {
+    addr1 := "kaspa:qzrsq2mfj9sf7uye3u5q7juejzlr0axk5jz9fpg4vqe76erdyvxxze84k9nk7"
+    addr2 := "kaspa:qq8guq855gxkfrj2w25skwgj7cp4hy08x6a8mz70tdtmgv5p2ngwqxpj4cknc"
+    eventTransfer := &events.DataEventType{
+        Type: events.EventTypeOp,
+        Op: &storage.DataScriptType{P: "KRC-20", Op: "transfer", Tick: "TEST", From: addr1, To: addr2},
+        BalanceList: []events.DataEventBalanceType{
+            {Address: addr1, Tick: "TEST", Balance: "0", Delta: "-10"},
+            {Address: addr2, Tick: "TEST", Balance: "10", Delta: "10"},
+        },
+    }
+    eventRetract := &events.DataEventType{
+        Type: events.EventTypeRetract,
+        Op: eventTransfer.Op,
+    }
+    tests := []struct {
+        name string
+        data storage.DataWebhookType
+        event *events.DataEventType
+        match bool
+    }{
+        {"any", storage.DataWebhookType{}, eventTransfer, true},
+        {"tick", storage.DataWebhookType{TickList: []string{"TEST"}}, eventTransfer, true},
+        {"tick other", storage.DataWebhookType{TickList: []string{"KASP"}}, eventTransfer, false},
+        {"op other", storage.DataWebhookType{OpList: []string{"mint"}}, eventTransfer, false},
+        {"address sender", storage.DataWebhookType{AddressList: []string{addr1}}, eventTransfer, true},
+        {"receive sender", storage.DataWebhookType{AddressList: []string{addr1}, Receive: true}, eventTransfer, false},
+        {"receive receiver", storage.DataWebhookType{AddressList: []string{addr2}, Receive: true}, eventTransfer, true},
+        {"receive tick other", storage.DataWebhookType{AddressList: []string{addr2}, TickList: []string{"KASP"}, Receive: true}, eventTransfer, false},
+        {"receive retract", storage.DataWebhookType{AddressList: []string{addr2}, Receive: true}, eventRetract, true},
+        {"receive retract sender", storage.DataWebhookType{AddressList: []string{addr1}, Receive: true}, eventRetract, false},
+    }
+    for _, tt := range tests {
+        hook := &hookType{
+            data: tt.data,
+            filter: events.FilterType{
+                TickList: tt.data.TickList,
+                AddressList: tt.data.AddressList,
+                OpList: tt.data.OpList,
+            },
+        }
+        if hook.match(tt.event) != tt.match {
+            t.Errorf("%s: match = %v, want %v", tt.name, !tt.match, tt.match)
+        }
+    }
+}